@@ -0,0 +1,234 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `TIDCodec` converts IDs to and from their representation in the
+	// plain text storage format.
+	//
+	// The text of an ID must neither contain a linefeed nor start with
+	// one of the characters `[`, `{`, `|`, `=`, `~`, or `+` which mark
+	// other lines of the text format. `DecodeID()` must reject any
+	// text not produced by `EncodeID()`.
+	//
+	// NOTE: The binary storage format doesn't need a codec since all
+	// types satisfying `cmp.Ordered` are handled natively by `gob`.
+	TIDCodec[ID cmp.Ordered] interface {
+		// `EncodeID()` returns the text representation of `aID`.
+		EncodeID(aID ID) string
+
+		// `DecodeID()` returns the ID represented by `aText`.
+		DecodeID(aText string) (ID, error)
+	}
+
+	// `TInt64Codec` represents `int64` IDs as 16 hexadecimal digits,
+	// i.e. the format used by all versions of this package.
+	TInt64Codec struct{}
+
+	// `TStringCodec` represents `string` IDs (e.g. UUIDs or slugs) as
+	// double-quoted Go string literals.
+	TStringCodec struct{}
+
+	// `tKindCodec` represents IDs by their underlying kind: integers
+	// and floats as decimal numbers, strings as quoted literals.
+	tKindCodec[ID cmp.Ordered] struct{}
+)
+
+var (
+	// `errBadID` is returned for texts not representing an ID.
+	errBadID = errors.New("invalid ID")
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `DefaultCodec()` returns the codec used for `ID` if none is given
+// to [NewIndex].
+//
+// `int64` IDs use [TInt64Codec] and `string` IDs use [TStringCodec];
+// all other types are represented by their underlying kind, i.e.
+// numbers in decimal and strings as quoted literals.
+//
+// Returns:
+//   - `TIDCodec[ID]`: The default codec for `ID`.
+func DefaultCodec[ID cmp.Ordered]() TIDCodec[ID] {
+	var zero ID
+
+	switch any(zero).(type) {
+	case int64:
+		if codec, ok := any(TInt64Codec{}).(TIDCodec[ID]); ok {
+			return codec
+		}
+	case string:
+		if codec, ok := any(TStringCodec{}).(TIDCodec[ID]); ok {
+			return codec
+		}
+	}
+
+	return tKindCodec[ID]{}
+} // DefaultCodec()
+
+// -------------------------------------------------------------------------
+// methods of `TInt64Codec`:
+
+// `DecodeID()` returns the ID represented by `aText`.
+//
+// Parameters:
+//   - `aText`: The hexadecimal representation of an ID.
+//
+// Returns:
+//   - `int64`: The decoded ID.
+//   - `error`: A possible parsing error.
+func (TInt64Codec) DecodeID(aText string) (int64, error) {
+	return strconv.ParseInt(aText, 16, 64)
+} // DecodeID()
+
+// `EncodeID()` returns `aID` as a string of 16 hexadecimal digits.
+//
+// Parameters:
+//   - `aID`: The ID to encode.
+//
+// Returns:
+//   - `string`: The hexadecimal representation of `aID`.
+func (TInt64Codec) EncodeID(aID int64) string {
+	if result := fmt.Sprintf("%x", aID); 16 > len(result) {
+		return strings.Repeat("0", 16-len(result)) + result
+	} else {
+		return result
+	}
+} // EncodeID()
+
+// -------------------------------------------------------------------------
+// methods of `TStringCodec`:
+
+// `DecodeID()` returns the ID represented by `aText`.
+//
+// Parameters:
+//   - `aText`: The quoted representation of an ID.
+//
+// Returns:
+//   - `string`: The decoded ID.
+//   - `error`: A possible parsing error.
+func (TStringCodec) DecodeID(aText string) (string, error) {
+	if (2 > len(aText)) || ('"' != aText[0]) {
+		return "", errBadID
+	}
+
+	return strconv.Unquote(aText)
+} // DecodeID()
+
+// `EncodeID()` returns `aID` as a double-quoted Go string literal.
+//
+// Parameters:
+//   - `aID`: The ID to encode.
+//
+// Returns:
+//   - `string`: The quoted representation of `aID`.
+func (TStringCodec) EncodeID(aID string) string {
+	return strconv.Quote(aID)
+} // EncodeID()
+
+// -------------------------------------------------------------------------
+// methods of `tKindCodec`:
+
+// `DecodeID()` returns the ID represented by `aText`.
+//
+// Parameters:
+//   - `aText`: The representation of an ID.
+//
+// Returns:
+//   - `ID`: The decoded ID.
+//   - `error`: A possible parsing error.
+func (tKindCodec[ID]) DecodeID(aText string) (ID, error) {
+	var result ID
+	if 0 == len(aText) {
+		return result, errBadID
+	}
+
+	v := reflect.ValueOf(&result).Elem()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(aText, 10, v.Type().Bits())
+		if nil != err {
+			return result, err
+		}
+		v.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(aText, 10, v.Type().Bits())
+		if nil != err {
+			return result, err
+		}
+		v.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		if ('+' == aText[0]) || ('-' != aText[0] && ('0' > aText[0] || '9' < aText[0])) {
+			return result, errBadID
+		}
+		f, err := strconv.ParseFloat(aText, v.Type().Bits())
+		if nil != err {
+			return result, err
+		}
+		v.SetFloat(f)
+
+	case reflect.String:
+		if '"' != aText[0] {
+			return result, errBadID
+		}
+		s, err := strconv.Unquote(aText)
+		if nil != err {
+			return result, err
+		}
+		v.SetString(s)
+
+	default:
+		return result, errBadID
+	}
+
+	return result, nil
+} // DecodeID()
+
+// `EncodeID()` returns the representation of `aID`.
+//
+// Parameters:
+//   - `aID`: The ID to encode.
+//
+// Returns:
+//   - `string`: The representation of `aID`.
+func (tKindCodec[ID]) EncodeID(aID ID) string {
+	v := reflect.ValueOf(aID)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+
+	case reflect.String:
+		return strconv.Quote(v.String())
+	}
+
+	return fmt.Sprint(aID)
+} // EncodeID()
+
+/* EoF */
@@ -0,0 +1,89 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"cmp"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+// `testCodec()` checks that `aCodec` decodes its own encodings and
+// rejects all of `aBad`.
+func testCodec[ID cmp.Ordered](t *testing.T, aCodec TIDCodec[ID], aIDs []ID, aBad []string) {
+	t.Helper()
+
+	for _, id := range aIDs {
+		text := aCodec.EncodeID(id)
+		got, err := aCodec.DecodeID(text)
+		if nil != err {
+			t.Errorf("DecodeID(%q) error = %v", text, err)
+			continue
+		}
+		if got != id {
+			t.Errorf("DecodeID(EncodeID(%v)) = %v", id, got)
+		}
+	}
+	for _, text := range aBad {
+		if _, err := aCodec.DecodeID(text); nil == err {
+			t.Errorf("DecodeID(%q) accepted invalid text", text)
+		}
+	}
+} // testCodec()
+
+// `tSlug` is a named string type used as ID.
+type tSlug string
+
+func Test_DefaultCodec(t *testing.T) {
+	if _, ok := DefaultCodec[int64]().(TInt64Codec); !ok {
+		t.Error("DefaultCodec[int64]() isn't a TInt64Codec")
+	}
+	if _, ok := DefaultCodec[string]().(TStringCodec); !ok {
+		t.Error("DefaultCodec[string]() isn't a TStringCodec")
+	}
+	if _, ok := DefaultCodec[uint32]().(tKindCodec[uint32]); !ok {
+		t.Error("DefaultCodec[uint32]() isn't a tKindCodec")
+	}
+} // Test_DefaultCodec()
+
+func Test_TInt64Codec(t *testing.T) {
+	codec := TInt64Codec{}
+	if got := codec.EncodeID(0x1f); "000000000000001f" != got {
+		t.Errorf("TInt64Codec.EncodeID() = %q, want %q", got, "000000000000001f")
+	}
+	testCodec[int64](t, codec,
+		[]int64{0, 1, 0x7fffffffffffffff},
+		[]string{"", "xyz", "[#tag]", "=pinned"})
+} // Test_TInt64Codec()
+
+func Test_TStringCodec(t *testing.T) {
+	codec := TStringCodec{}
+	if got := codec.EncodeID("a b"); `"a b"` != got {
+		t.Errorf("TStringCodec.EncodeID() = %q, want %q", got, `"a b"`)
+	}
+	testCodec[string](t, codec,
+		[]string{"", "a b", "line\nbreak", "[#tag]", `"quoted"`},
+		[]string{"", "abc", `"open`, "[#tag]", "+1"})
+} // Test_TStringCodec()
+
+func Test_tKindCodec(t *testing.T) {
+	testCodec[uint8](t, tKindCodec[uint8]{},
+		[]uint8{0, 1, 255},
+		[]string{"", "-1", "256", "ff"})
+	testCodec[int32](t, tKindCodec[int32]{},
+		[]int32{-2147483648, 0, 2147483647},
+		[]string{"", "1.5", "2147483648"})
+	testCodec[float64](t, tKindCodec[float64]{},
+		[]float64{-1.5, 0, 3.25e100},
+		[]string{"", "+1", "~1 2", "NaN", "Inf"})
+	testCodec[tSlug](t, tKindCodec[tSlug]{},
+		[]tSlug{"", "my-post", "a b"},
+		[]string{"", "my-post", "|x"})
+} // Test_tKindCodec()
+
+/* EoF */
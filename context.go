@@ -0,0 +1,526 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	se "github.com/mwat56/sourceerror"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+// The methods in this file form a context-aware alternative to the
+// methods returning a bare `bool`: They report why nothing changed,
+// stop working once their context is done, and return the error of
+// a failed autosave (see [TIndex.SetAutoSave]).
+//
+// All errors are wrapped by `sourceerror` (i.e. [THashTagError]);
+// use `errors.Is()` to check for the sentinels below or for
+// `context.Canceled` and `context.DeadlineExceeded`.
+
+var (
+	// `ErrAutoSave` is returned if the list was changed but storing
+	// it afterwards failed.
+	ErrAutoSave = errors.New("autosave failed")
+
+	// `ErrInvalidTag` is returned for an empty tag or a tag not
+	// satisfying the list's validation rules.
+	ErrInvalidTag = errors.New("invalid tag")
+
+	// `ErrUnchanged` is returned if a call didn't change the list,
+	// e.g. because an ID was already associated with a tag.
+	ErrUnchanged = errors.New("nothing changed")
+)
+
+// -------------------------------------------------------------------------
+// methods of `TIndex`:
+
+// `add()` appends `aID` to the list of `aTag`.
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//   - `aDelim`: The start character of words to use (i.e. either '@' or '#').
+//   - `aTag`: The `#hashtag` or `@mention` to use.
+//   - `aID`: The ID to add.
+//
+// Returns:
+//   - `error`: `nil` if `aID` was added, or the reason otherwise.
+func (ht *TIndex[ID]) add(aCtx context.Context, aDelim byte, aTag string, aID ID) error {
+	name, err := ht.lock(aCtx, aDelim, aTag)
+	if nil != err {
+		return err // already wrapped
+	}
+	if ht.safe {
+		defer ht.mtx.Unlock()
+	}
+
+	if !ht.vr.isValid(normalise(name)) {
+		return se.New(ErrInvalidTag, 1)
+	}
+	if !ht.insert(aDelim, name, aID, time.Time{}) {
+		return se.New(ErrUnchanged, 1)
+	}
+
+	return ht.autoSave()
+} // add()
+
+// `AddHash()` appends `aID` to the list of `aHash`.
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//   - `aHash`: The `#hashtag` to use.
+//   - `aID`: The ID to add.
+//
+// Returns:
+//   - `error`: `nil` if `aID` was added, or `ErrInvalidTag`, `ErrUnchanged`, `ErrAutoSave`, `ErrReadOnly`, or the context's error.
+func (ht *TIndex[ID]) AddHash(aCtx context.Context, aHash string, aID ID) error {
+	return ht.add(aCtx, MarkHash, aHash, aID)
+} // AddHash()
+
+// `AddMention()` appends `aID` to the list of `aMention`.
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//   - `aMention`: The `@mention` to use.
+//   - `aID`: The ID to add.
+//
+// Returns:
+//   - `error`: `nil` if `aID` was added, or `ErrInvalidTag`, `ErrUnchanged`, `ErrAutoSave`, `ErrReadOnly`, or the context's error.
+func (ht *TIndex[ID]) AddMention(aCtx context.Context, aMention string, aID ID) error {
+	return ht.add(aCtx, MarkMention, aMention, aID)
+} // AddMention()
+
+// `AutoSave()` reports whether the list is stored after each change
+// of its tags and IDs.
+//
+// Returns:
+//   - `bool`: `true` if the autosave mode is enabled.
+func (ht *TIndex[ID]) AutoSave() bool {
+	if nil != ht.root {
+		return ht.root.AutoSave()
+	}
+
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	return ht.auto
+} // AutoSave()
+
+// `autoSave()` stores the whole list if the autosave mode is enabled.
+//
+// NOTE: This method expects the caller to hold the list's lock.
+//
+// Returns:
+//   - `error`: `nil` in case of success, or `ErrAutoSave` otherwise.
+func (ht *TIndex[ID]) autoSave() error {
+	root := ht
+	if nil != ht.root {
+		root = ht.root
+	}
+	if !root.auto || ("" == root.fn) {
+		return nil
+	}
+
+	if _, err := root.storeFile(); nil != err {
+		return se.New(fmt.Errorf("%w: %w", ErrAutoSave, err), 1)
+	}
+
+	return nil
+} // autoSave()
+
+// `LoadContext()` reads the configured file like [TIndex.Load] but
+// stops reading once `aCtx` is done.
+//
+// The file is read completely before the list's data are replaced,
+// i.e. if reading fails or is stopped the list remains unchanged.
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//
+// Returns:
+//   - `error`: `nil` in case of success, or the I/O or context's error.
+func (ht *TIndex[ID]) LoadContext(aCtx context.Context) error {
+	if nil != ht.root {
+		return ht.root.LoadContext(aCtx)
+	}
+
+	return ht.loadFresh(aCtx, LoadLenient, nil)
+} // LoadContext()
+
+// `loadFresh()` reads the configured file into new data structures
+// which replace the list's data only if reading succeeded.
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//   - `aMode`: How to handle malformed data.
+//   - `aReport`: Optional report of the malformed data found.
+//
+// Returns:
+//   - `error`: `nil` in case of success, or the I/O or context's error.
+func (ht *TIndex[ID]) loadFresh(aCtx context.Context, aMode TLoadMode, aReport *TLoadReport) error {
+	if ht.safe {
+		ht.mtx.RLock()
+	}
+	fn, codec := ht.fn, ht.xt.ic
+	if ht.safe {
+		ht.mtx.RUnlock()
+	}
+	if "" == fn {
+		return nil
+	}
+	if _, err := os.Stat(fn); os.IsNotExist(err) {
+		return nil // keep the data in memory
+	}
+
+	var stamp tFileStamp
+	hm, xt := newHashMap[ID](), newExtras[ID]()
+	xt.ic = codec
+	if _, err := hm.loadFile(aCtx, fn, xt, aMode, aReport, &stamp); nil != err {
+		return err // already wrapped
+	}
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+	*ht.hm, *ht.xt = *hm, *xt
+	ht.rebindScopes()
+	ht.cc.cl = nil
+	ht.invalidate()
+	ht.clearHistory()
+	ht.synced(stamp)
+
+	return nil
+} // loadFresh()
+
+// `lock()` prepares `aTag` and acquires the list's write lock.
+//
+// If an error is returned the lock is not held.
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//   - `aDelim`: The start character of words to use (i.e. either '@' or '#').
+//   - `aTag`: The `#hashtag` or `@mention` to prepare.
+//
+// Returns:
+//   - `string`: The tag including its leading mark.
+//   - `error`: `ErrInvalidTag`, `ErrReadOnly`, or the context's error.
+func (ht *TIndex[ID]) lock(aCtx context.Context, aDelim byte, aTag string) (string, error) {
+	if aTag = strings.TrimSpace(aTag); "" == aTag {
+		return "", se.New(ErrInvalidTag, 1)
+	}
+	if aTag[0] != aDelim {
+		aTag = string(aDelim) + aTag
+	}
+
+	if err := ht.lockContext(aCtx); nil != err {
+		return "", err // already wrapped
+	}
+
+	return aTag, nil
+} // lock()
+
+// `lockContext()` acquires the list's write lock unless the list is
+// read-only or `aCtx` is done.
+//
+// The context is checked before and after acquiring the lock; waiting
+// for the lock itself isn't interrupted by `aCtx`. If an error is
+// returned the lock is not held.
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//
+// Returns:
+//   - `error`: `ErrReadOnly` or the context's error (if any).
+func (ht *TIndex[ID]) lockContext(aCtx context.Context) error {
+	if ht.readOnly() {
+		return se.New(ErrReadOnly, 1)
+	}
+	if err := aCtx.Err(); nil != err {
+		return se.New(err, 1)
+	}
+	if !ht.safe {
+		return nil
+	}
+
+	ht.mtx.Lock()
+	if err := aCtx.Err(); nil != err {
+		ht.mtx.Unlock()
+		return se.New(err, 2)
+	}
+
+	return nil
+} // lockContext()
+
+// `Parse()` searches `aText` for `#hashtags` and `@mentions` and adds
+// `aID` to their lists like [TIndex.IDparse] does.
+//
+// The text is searched line by line, checking `aCtx` in between; if
+// `aCtx` is done before all tags were found the list remains unchanged.
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//   - `aID`: The ID to add.
+//   - `aText`: The text to search.
+//
+// Returns:
+//   - `TDiff`: The tags `aID` was added to (i.e. `Added` only).
+//   - `error`: `nil` in case of success, or `ErrUnchanged`, `ErrAutoSave`, `ErrReadOnly`, or the context's error.
+func (ht *TIndex[ID]) Parse(aCtx context.Context, aID ID, aText []byte) (TDiff, error) {
+	var result TDiff
+
+	tags, err := ht.scan(aCtx, aText)
+	if nil != err {
+		return result, err // already wrapped
+	}
+	if err = ht.lockContext(aCtx); nil != err {
+		return result, err // already wrapped
+	}
+	if ht.safe {
+		defer ht.mtx.Unlock()
+	}
+
+	var offsets map[string][]int
+	if ht.pos {
+		offsets = make(map[string][]int, len(tags))
+	}
+	for _, tag := range tags {
+		if ht.insert(tag.Kind, tag.Text, aID, time.Time{}) {
+			result.Added = append(result.Added, tag.Key)
+		}
+		if nil != offsets {
+			offsets[tag.Key] = append(offsets[tag.Key], tag.Start)
+		}
+	}
+	for key, offs := range offsets {
+		ht.xt.Pos.set(key, aID, offs)
+	}
+	if 0 == len(result.Added) {
+		return result, se.New(ErrUnchanged, 1)
+	}
+	slices.Sort(result.Added)
+
+	return result, ht.autoSave()
+} // Parse()
+
+// `remove()` deletes `aID` from the list of `aTag`.
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//   - `aDelim`: The start character of words to use (i.e. either '@' or '#').
+//   - `aTag`: The `#hashtag` or `@mention` to use.
+//   - `aID`: The ID to remove.
+//
+// Returns:
+//   - `error`: `nil` if `aID` was removed, or the reason otherwise.
+func (ht *TIndex[ID]) remove(aCtx context.Context, aDelim byte, aTag string, aID ID) error {
+	name, err := ht.lock(aCtx, aDelim, aTag)
+	if nil != err {
+		return err // already wrapped
+	}
+	if ht.safe {
+		defer ht.mtx.Unlock()
+	}
+
+	if !ht.removeHM(aDelim, name, aID) {
+		return se.New(ErrUnchanged, 1)
+	}
+
+	return ht.autoSave()
+} // remove()
+
+// `RemoveHash()` deletes `aID` from the list of `aHash`.
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//   - `aHash`: The `#hashtag` to use.
+//   - `aID`: The ID to remove.
+//
+// Returns:
+//   - `error`: `nil` if `aID` was removed, or `ErrInvalidTag`, `ErrUnchanged`, `ErrAutoSave`, `ErrReadOnly`, or the context's error.
+func (ht *TIndex[ID]) RemoveHash(aCtx context.Context, aHash string, aID ID) error {
+	return ht.remove(aCtx, MarkHash, aHash, aID)
+} // RemoveHash()
+
+// `RemoveID()` deletes `aID` from the lists of all `#hashtags` and
+// `@mentions`.
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//   - `aID`: The ID to remove.
+//
+// Returns:
+//   - `error`: `nil` if `aID` was removed, or `ErrUnchanged`, `ErrAutoSave`, `ErrReadOnly`, or the context's error.
+func (ht *TIndex[ID]) RemoveID(aCtx context.Context, aID ID) error {
+	if err := ht.lockContext(aCtx); nil != err {
+		return err // already wrapped
+	}
+	if ht.safe {
+		defer ht.mtx.Unlock()
+	}
+
+	if !ht.removeID(aID) {
+		return se.New(ErrUnchanged, 1)
+	}
+
+	return ht.autoSave()
+} // RemoveID()
+
+// `RemoveMention()` deletes `aID` from the list of `aMention`.
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//   - `aMention`: The `@mention` to use.
+//   - `aID`: The ID to remove.
+//
+// Returns:
+//   - `error`: `nil` if `aID` was removed, or `ErrInvalidTag`, `ErrUnchanged`, `ErrAutoSave`, `ErrReadOnly`, or the context's error.
+func (ht *TIndex[ID]) RemoveMention(aCtx context.Context, aMention string, aID ID) error {
+	return ht.remove(aCtx, MarkMention, aMention, aID)
+} // RemoveMention()
+
+// `RenameID()` replaces all occurrences of `aOldID` by `aNewID`.
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//   - `aOldID`: The ID to be replaced in all lists.
+//   - `aNewID`: The replacement in all lists.
+//
+// Returns:
+//   - `error`: `nil` if `aOldID` was renamed, or `ErrUnchanged`, `ErrAutoSave`, `ErrReadOnly`, or the context's error.
+func (ht *TIndex[ID]) RenameID(aCtx context.Context, aOldID, aNewID ID) error {
+	if err := ht.lockContext(aCtx); nil != err {
+		return err // already wrapped
+	}
+	if ht.safe {
+		defer ht.mtx.Unlock()
+	}
+
+	if (aOldID == aNewID) || !ht.renameID(aOldID, aNewID) {
+		return se.New(ErrUnchanged, 1)
+	}
+
+	return ht.autoSave()
+} // RenameID()
+
+// `scan()` returns all valid tags found in `aText`.
+//
+// Parameters:
+//   - `aCtx`: The context to check between the lines of `aText`.
+//   - `aText`: The text to search.
+//
+// Returns:
+//   - `[]TTag`: The tags found in order of their occurrence.
+//   - `error`: The context's error (if any).
+func (ht *TIndex[ID]) scan(aCtx context.Context, aText []byte) ([]TTag, error) {
+	if ht.safe {
+		ht.mtx.RLock()
+	}
+	ex := &tExtractor{vr: ht.vr}
+	if ht.safe {
+		ht.mtx.RUnlock()
+	}
+
+	var (
+		end    int
+		result []TTag
+	)
+	yield := func(aTag TTag) bool {
+		result = append(result, aTag)
+		return true
+	}
+	for offset := 0; offset < len(aText); offset = end {
+		if err := aCtx.Err(); nil != err {
+			return nil, se.New(err, 1)
+		}
+		if end = bytes.IndexByte(aText[offset:], '\n'); 0 > end {
+			end = len(aText)
+		} else {
+			end += offset + 1
+		}
+		ex.extract(aText[offset:end], offset, yield)
+	}
+
+	return result, nil
+} // scan()
+
+// `SetAutoSave()` enables or disables storing the whole list after
+// each change of its tags and IDs, e.g. by [TIndex.AddHash],
+// [TIndex.Parse], [TIndex.HashAdd] or [TIndex.Merge].
+//
+// The list is stored while its lock is held, and a failure is
+// returned as `ErrAutoSave` by the context-aware method causing the
+// change (the change itself remains in effect); the other methods
+// can't report such a failure. The mode applies to all scopes of
+// the list.
+//
+// Parameters:
+//   - `aAutoSave`: Whether to store the list after each change.
+//
+// Returns:
+//   - `*TIndex[ID]`: The updated list.
+func (ht *TIndex[ID]) SetAutoSave(aAutoSave bool) *TIndex[ID] {
+	if nil != ht.root {
+		ht.root.SetAutoSave(aAutoSave)
+		return ht
+	}
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+	ht.auto = aAutoSave
+
+	return ht
+} // SetAutoSave()
+
+// `Update()` replaces the tags associated with `aID` by those found
+// in `aText` like [TIndex.IDupdateDiff] does.
+//
+// The text is searched line by line, checking `aCtx` in between; if
+// `aCtx` is done before all tags were found the list remains unchanged.
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//   - `aID`: The ID to update.
+//   - `aText`: The new text to use.
+//
+// Returns:
+//   - `TDiff`: The tags added and removed.
+//   - `error`: `nil` in case of success, or `ErrUnchanged`, `ErrAutoSave`, `ErrReadOnly`, or the context's error.
+func (ht *TIndex[ID]) Update(aCtx context.Context, aID ID, aText []byte) (TDiff, error) {
+	tags, err := ht.scan(aCtx, aText)
+	if nil != err {
+		return TDiff{}, err // already wrapped
+	}
+	if err = ht.lockContext(aCtx); nil != err {
+		return TDiff{}, err // already wrapped
+	}
+	if ht.safe {
+		defer ht.mtx.Unlock()
+	}
+
+	diff := ht.tagsDiff(aID, tags)
+	ht.update(aID, diff, tags)
+	if (0 == len(diff.Added)) && (0 == len(diff.Removed)) {
+		return diff, se.New(ErrUnchanged, 1)
+	}
+
+	return diff, ht.autoSave()
+} // Update()
+
+/* EoF */
@@ -0,0 +1,232 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func Test_THashTags_AddHash(t *testing.T) {
+	ctx := context.Background()
+	ht, _ := New("")
+
+	tests := []struct {
+		name  string
+		hash  string
+		id    int64
+		wantE error
+	}{
+		{"1", "#go", 1, nil},
+		{"2", "go", 2, nil},
+		{"3", "#Go", 1, ErrUnchanged},
+		{"4", " ", 3, ErrInvalidTag},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ht.AddHash(ctx, tt.hash, tt.id)
+			if !errors.Is(err, tt.wantE) || ((nil == tt.wantE) != (nil == err)) {
+				t.Errorf("THashTags.AddHash() error = %v, want %v", err, tt.wantE)
+			}
+		})
+	}
+
+	if got := ht.HashList("#go"); !slices.Equal(got, []int64{1, 2}) {
+		t.Errorf("THashTags.HashList() = %v, want [1 2]", got)
+	}
+	if err := ht.RemoveHash(ctx, "#go", 1); nil != err {
+		t.Errorf("THashTags.RemoveHash() error = %v", err)
+	}
+	if err := ht.RemoveHash(ctx, "#go", 1); !errors.Is(err, ErrUnchanged) {
+		t.Errorf("THashTags.RemoveHash() error = %v, want %v", err, ErrUnchanged)
+	}
+	if err := ht.AddMention(ctx, "bob", 2); nil != err {
+		t.Errorf("THashTags.AddMention() error = %v", err)
+	}
+	if err := ht.RemoveMention(ctx, "@bob", 2); nil != err {
+		t.Errorf("THashTags.RemoveMention() error = %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := ht.AddHash(cancelled, "#rust", 3); !errors.Is(err, context.Canceled) {
+		t.Errorf("THashTags.AddHash() error = %v, want %v", err, context.Canceled)
+	}
+	if 0 < ht.HashLen("#rust") {
+		t.Error("THashTags.AddHash() changed the list despite cancellation")
+	}
+} // Test_THashTags_AddHash()
+
+func Test_THashTags_Parse(t *testing.T) {
+	ctx := context.Background()
+	ht, _ := New("")
+
+	diff, err := ht.Parse(ctx, 1, []byte("#Go and @Bob\nlike #rust"))
+	if nil != err {
+		t.Fatalf("THashTags.Parse() error = %v", err)
+	}
+	if want := []string{"#go", "#rust", "@bob"}; !slices.Equal(diff.Added, want) {
+		t.Errorf("THashTags.Parse() = %v, want %v", diff.Added, want)
+	}
+	if diff, err = ht.Parse(ctx, 1, []byte("#go")); !errors.Is(err, ErrUnchanged) || (0 != len(diff.Added)) {
+		t.Errorf("THashTags.Parse() = %v, %v, want %v", diff, err, ErrUnchanged)
+	}
+
+	diff, err = ht.Update(ctx, 1, []byte("#go and #zig"))
+	if nil != err {
+		t.Fatalf("THashTags.Update() error = %v", err)
+	}
+	if !slices.Equal(diff.Added, []string{"#zig"}) ||
+		!slices.Equal(diff.Removed, []string{"#rust", "@bob"}) {
+		t.Errorf("THashTags.Update() = %v", diff)
+	}
+	if _, err = ht.Update(ctx, 1, []byte("#zig #go")); !errors.Is(err, ErrUnchanged) {
+		t.Errorf("THashTags.Update() error = %v, want %v", err, ErrUnchanged)
+	}
+
+	if err = ht.RenameID(ctx, 1, 2); nil != err {
+		t.Errorf("THashTags.RenameID() error = %v", err)
+	}
+	if err = ht.RenameID(ctx, 1, 2); !errors.Is(err, ErrUnchanged) {
+		t.Errorf("THashTags.RenameID() error = %v, want %v", err, ErrUnchanged)
+	}
+	if err = ht.RemoveID(ctx, 2); nil != err {
+		t.Errorf("THashTags.RemoveID() error = %v", err)
+	}
+	if err = ht.RemoveID(ctx, 2); !errors.Is(err, ErrUnchanged) {
+		t.Errorf("THashTags.RemoveID() error = %v, want %v", err, ErrUnchanged)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err = ht.Parse(cancelled, 3, []byte("#go")); !errors.Is(err, context.Canceled) {
+		t.Errorf("THashTags.Parse() error = %v, want %v", err, context.Canceled)
+	}
+	if _, err = ht.Update(cancelled, 3, []byte("#go")); !errors.Is(err, context.Canceled) {
+		t.Errorf("THashTags.Update() error = %v, want %v", err, context.Canceled)
+	}
+	if 0 != ht.Len() {
+		t.Errorf("THashTags.Len() = %d, want 0", ht.Len())
+	}
+} // Test_THashTags_Parse()
+
+func Test_THashTags_LoadContext(t *testing.T) {
+	ctx := context.Background()
+	fn := filepath.Join(t.TempDir(), "context.db")
+
+	ht, _ := New(fn)
+	ht.IDparse(1, []byte("#go and @bob"))
+	if _, err := ht.Store(); nil != err {
+		t.Fatalf("THashTags.Store() error = %v", err)
+	}
+
+	ht2, _ := New(fn)
+	ht2.Clear()
+	ht2.HashAdd("#keep", 9)
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := ht2.LoadContext(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("THashTags.LoadContext() error = %v, want %v", err, context.Canceled)
+	}
+	if 1 != ht2.HashLen("#keep") {
+		t.Error("THashTags.LoadContext() changed the list despite cancellation")
+	}
+
+	if err := ht2.LoadContext(ctx); nil != err {
+		t.Fatalf("THashTags.LoadContext() error = %v", err)
+	}
+	if (0 < ht2.HashLen("#keep")) || (1 != ht2.MentionLen("@bob")) {
+		t.Errorf("THashTags.LoadContext() = %v", ht2.String())
+	}
+} // Test_THashTags_LoadContext()
+
+func Test_THashTags_SetAutoSave(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	fn := filepath.Join(dir, "auto.db")
+
+	ht, _ := New(fn)
+	if ht.AutoSave() {
+		t.Error("THashTags.AutoSave() = true, want false")
+	}
+	scope := ht.Scope("news")
+	scope.SetAutoSave(true)
+	if !ht.AutoSave() || !scope.AutoSave() {
+		t.Error("THashTags.SetAutoSave() didn't apply to the root list")
+	}
+
+	if err := scope.AddHash(ctx, "#go", 1); nil != err {
+		t.Fatalf("THashTags.AddHash() error = %v", err)
+	}
+	if _, err := os.Stat(fn); nil != err {
+		t.Errorf("THashTags.AddHash() didn't store the list: %v", err)
+	}
+
+	ht.SetFilename(dir) // a directory can't be written as a file
+	err := ht.AddHash(ctx, "#rust", 2)
+	if !errors.Is(err, ErrAutoSave) {
+		t.Errorf("THashTags.AddHash() error = %v, want %v", err, ErrAutoSave)
+	}
+	if 1 != ht.HashLen("#rust") {
+		t.Error("THashTags.AddHash() didn't keep the change")
+	}
+} // Test_THashTags_SetAutoSave()
+
+func Test_THashTags_SetAutoSave_bool(t *testing.T) {
+	a, b := openPair(t, ConflictFail)
+	b.SetAutoSave(true)
+	fn := b.Filename()
+
+	tests := []struct {
+		name   string
+		modify func() bool
+		tag    string
+	}{
+		{"HashAdd", func() bool { return b.HashAdd("#b", 2) }, "#b"},
+		{"IDparse", func() bool { return b.IDparse(3, []byte("#c")) }, "#c"},
+		{"Merge", func() bool { return b.Merge(setList(map[string][]int64{"#d": {4}})) }, "#d"},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.modify() {
+				t.Fatalf("%s() = false, want true", tt.name)
+			}
+			got, _ := New(fn)
+			if 1 != got.HashLen(tt.tag) {
+				t.Errorf("%s() didn't store the list: %v", tt.name, got.String())
+			}
+			// the file's state is known to the list
+			if modified, err := b.Modified(); modified || (nil != err) {
+				t.Errorf("THashTags.Modified() = %v, %v, want false", modified, err)
+			}
+		})
+	}
+
+	// the conflict policy applies to the autosave mode
+	if _, err := a.Reload(); nil != err {
+		t.Fatalf("THashTags.Reload() error = %v", err)
+	}
+	a.HashAdd("#a", 1)
+	if _, err := a.Store(); nil != err {
+		t.Fatalf("THashTags.Store() error = %v", err)
+	}
+	b.HashAdd("#e", 5)
+	got, _ := New(fn)
+	if (1 != got.HashLen("#a")) || (-1 != got.HashLen("#e")) {
+		t.Errorf("THashTags.HashAdd() overwrote the file: %v", got.String())
+	}
+} // Test_THashTags_SetAutoSave_bool()
+
+/* EoF */
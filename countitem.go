@@ -6,13 +6,22 @@ Copyright © 2023, 2025  M.Watermann, 10247 Berlin, Germany
 */
 package hashtags
 
+import (
+	"time"
+)
+
 //lint:file-ignore ST1017 - I prefer Yoda conditions
 
 type (
 	// `TCountItem` holds a `#hashtag` and its number of occurrences.
 	TCountItem struct {
-		Count int    // number of IDs for this `#hashtag`
-		Tag   string // name of `#hashtag`
+		Count       int       // number of IDs for this `#hashtag`
+		Tag         string    // name of `#hashtag`
+		Display     string    // original spelling of `#hashtag`
+		Description string    // free-text description of `#hashtag`
+		Created     time.Time // time `#hashtag` got its first ID
+		Modified    time.Time // time the IDs of `#hashtag` last changed
+		Pinned      bool      // flag marking a curated `#hashtag`
 	}
 )
 
@@ -16,13 +16,13 @@ func Test_TCountItem_Compare(t *testing.T) {
 	ci0 := TCountItem{}
 	it0 := TCountItem{}
 
-	ci1 := TCountItem{1, "#one"}
-	it1 := TCountItem{1, "@one"}
+	ci1 := TCountItem{Count: 1, Tag: "#one"}
+	it1 := TCountItem{Count: 1, Tag: "@one"}
 
-	ci2 := TCountItem{1, "#one"}
-	it2 := TCountItem{2, "@one"}
+	ci2 := TCountItem{Count: 1, Tag: "#one"}
+	it2 := TCountItem{Count: 2, Tag: "@one"}
 
-	ci4 := TCountItem{1, "#two"}
+	ci4 := TCountItem{Count: 1, Tag: "#two"}
 
 	tests := []struct {
 		name string
@@ -52,9 +52,9 @@ func Test_TCountItem_Compare(t *testing.T) {
 
 func Test_TCountItem_Equal(t *testing.T) {
 	ci0 := TCountItem{}
-	ci1 := TCountItem{11, "one"}
-	ci2 := TCountItem{222, "#two"}
-	ci3 := TCountItem{222, "#alphons"}
+	ci1 := TCountItem{Count: 11, Tag: "one"}
+	ci2 := TCountItem{Count: 222, Tag: "#two"}
+	ci3 := TCountItem{Count: 222, Tag: "#alphons"}
 	ci4 := ci3
 
 	tests := []struct {
@@ -82,9 +82,9 @@ func Test_TCountItem_Equal(t *testing.T) {
 
 func Test_TCountItem_Less(t *testing.T) {
 	ci0 := TCountItem{}
-	ci1 := TCountItem{11, "one"}
-	ci2 := TCountItem{222, "#two"}
-	ci3 := TCountItem{222, "#Xaver"}
+	ci1 := TCountItem{Count: 11, Tag: "one"}
+	ci2 := TCountItem{Count: 222, Tag: "#two"}
+	ci3 := TCountItem{Count: 222, Tag: "#Xaver"}
 
 	tests := []struct {
 		name string
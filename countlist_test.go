@@ -16,11 +16,11 @@ func TestTCountList_Compare(t *testing.T) {
 	cl0 := TCountList{}
 	wl0 := TCountList{}
 
-	cl2 := TCountList{TCountItem{2, "#two"}}
-	wl2 := TCountList{TCountItem{2, "@two"}}
+	cl2 := TCountList{TCountItem{Count: 2, Tag: "#two"}}
+	wl2 := TCountList{TCountItem{Count: 2, Tag: "@two"}}
 
-	wl4 := TCountList{TCountItem{1, "@two"}}
-	wl5 := TCountList{TCountItem{1, "zero"}}
+	wl4 := TCountList{TCountItem{Count: 1, Tag: "@two"}}
+	wl5 := TCountList{TCountItem{Count: 1, Tag: "zero"}}
 
 	tests := []struct {
 		name string
@@ -51,16 +51,16 @@ func TestTCountList_Equal(t *testing.T) {
 	cl1 := TCountList{}
 	wl1 := TCountList{}
 
-	cl2 := TCountList{TCountItem{2, "two"}}
-	wl2 := TCountList{TCountItem{2, "two"}}
+	cl2 := TCountList{TCountItem{Count: 2, Tag: "two"}}
+	wl2 := TCountList{TCountItem{Count: 2, Tag: "two"}}
 	wl3 := wl2
 
 	cl4 := TCountList{
-		TCountItem{1, "one"}, TCountItem{2, "two"}}
-	wl4 := TCountList{TCountItem{2, "two"}, TCountItem{1, "one"}}
+		TCountItem{Count: 1, Tag: "one"}, TCountItem{Count: 2, Tag: "two"}}
+	wl4 := TCountList{TCountItem{Count: 2, Tag: "two"}, TCountItem{Count: 1, Tag: "one"}}
 
 	cl5 := cl4
-	wl5 := TCountList{TCountItem{11, "one"}, TCountItem{22, "two"}}
+	wl5 := TCountList{TCountItem{Count: 11, Tag: "one"}, TCountItem{Count: 22, Tag: "two"}}
 
 	tests := []struct {
 		name string
@@ -88,13 +88,13 @@ func TestTCountList_Equal(t *testing.T) {
 
 func TestTCountList_Insert(t *testing.T) {
 	cl := TCountList{}
-	i1 := TCountItem{1, "one"}
+	i1 := TCountItem{Count: 1, Tag: "one"}
 	wl1 := &TCountList{i1}
 
-	i2 := TCountItem{2, "two"}
+	i2 := TCountItem{Count: 2, Tag: "two"}
 	wl2 := &TCountList{i1, i2}
 
-	i3 := TCountItem{3, "part3"}
+	i3 := TCountItem{Count: 3, Tag: "part3"}
 	wl3 := &TCountList{i1, i3, i2}
 
 	tests := []struct {
@@ -120,7 +120,7 @@ func TestTCountList_Insert(t *testing.T) {
 
 func TestTCountList_Len(t *testing.T) {
 	cl0 := TCountList{}
-	cl1 := TCountList{TCountItem{1, "one"}}
+	cl1 := TCountList{TCountItem{Count: 1, Tag: "one"}}
 
 	tests := []struct {
 		name string
@@ -145,68 +145,68 @@ func TestTCountList_Len(t *testing.T) {
 func TestTCountList_sort(t *testing.T) {
 	cl0 := &TCountList{}
 	cl1 := &TCountList{
-		TCountItem{345, "three"},
-		TCountItem{234, "@pure"},
-		TCountItem{123, "#one"},
+		TCountItem{Count: 345, Tag: "three"},
+		TCountItem{Count: 234, Tag: "@pure"},
+		TCountItem{Count: 123, Tag: "#one"},
 	}
 	wl1 := TCountList{
-		TCountItem{123, "#one"},
-		TCountItem{234, "@pure"},
-		TCountItem{345, "three"},
+		TCountItem{Count: 123, Tag: "#one"},
+		TCountItem{Count: 234, Tag: "@pure"},
+		TCountItem{Count: 345, Tag: "three"},
 	}
 	cl4 := &TCountList{
-		TCountItem{123, "#one"},
-		TCountItem{234, "@pure"},
-		TCountItem{345, "three"},
-		TCountItem{678, "#one"},
+		TCountItem{Count: 123, Tag: "#one"},
+		TCountItem{Count: 234, Tag: "@pure"},
+		TCountItem{Count: 345, Tag: "three"},
+		TCountItem{Count: 678, Tag: "#one"},
 	}
 	wl4 := TCountList{
-		TCountItem{123, "#one"},
-		TCountItem{678, "#one"},
-		TCountItem{234, "@pure"},
-		TCountItem{345, "three"},
+		TCountItem{Count: 123, Tag: "#one"},
+		TCountItem{Count: 678, Tag: "#one"},
+		TCountItem{Count: 234, Tag: "@pure"},
+		TCountItem{Count: 345, Tag: "three"},
 	}
 	cl5 := &TCountList{
-		TCountItem{123, "#one"},
-		TCountItem{234, "@pure"},
-		TCountItem{234, "#one"},
-		TCountItem{345, "three"},
-		TCountItem{345, "#one"},
+		TCountItem{Count: 123, Tag: "#one"},
+		TCountItem{Count: 234, Tag: "@pure"},
+		TCountItem{Count: 234, Tag: "#one"},
+		TCountItem{Count: 345, Tag: "three"},
+		TCountItem{Count: 345, Tag: "#one"},
 	}
 	wl5 := TCountList{
-		TCountItem{123, "#one"},
-		TCountItem{234, "#one"},
-		TCountItem{345, "#one"},
-		TCountItem{234, "@pure"},
-		TCountItem{345, "three"},
+		TCountItem{Count: 123, Tag: "#one"},
+		TCountItem{Count: 234, Tag: "#one"},
+		TCountItem{Count: 345, Tag: "#one"},
+		TCountItem{Count: 234, Tag: "@pure"},
+		TCountItem{Count: 345, Tag: "three"},
 	}
 	cl6 := &TCountList{
-		TCountItem{987, "#one"},
-		TCountItem{234, "@pure"},
-		TCountItem{654, "#one"},
-		TCountItem{345, "three"},
-		TCountItem{321, "#one"},
+		TCountItem{Count: 987, Tag: "#one"},
+		TCountItem{Count: 234, Tag: "@pure"},
+		TCountItem{Count: 654, Tag: "#one"},
+		TCountItem{Count: 345, Tag: "three"},
+		TCountItem{Count: 321, Tag: "#one"},
 	}
 	wl6 := TCountList{
-		TCountItem{321, "#one"},
-		TCountItem{654, "#one"},
-		TCountItem{987, "#one"},
-		TCountItem{234, "@pure"},
-		TCountItem{345, "three"},
+		TCountItem{Count: 321, Tag: "#one"},
+		TCountItem{Count: 654, Tag: "#one"},
+		TCountItem{Count: 987, Tag: "#one"},
+		TCountItem{Count: 234, Tag: "@pure"},
+		TCountItem{Count: 345, Tag: "three"},
 	}
 	cl7 := &TCountList{
-		TCountItem{987, "#one"},
-		TCountItem{235, "two"},
-		TCountItem{654, "#one"},
-		TCountItem{235, "two"},
-		TCountItem{321, "#one"},
+		TCountItem{Count: 987, Tag: "#one"},
+		TCountItem{Count: 235, Tag: "two"},
+		TCountItem{Count: 654, Tag: "#one"},
+		TCountItem{Count: 235, Tag: "two"},
+		TCountItem{Count: 321, Tag: "#one"},
 	}
 	wl7 := TCountList{
-		TCountItem{321, "#one"},
-		TCountItem{654, "#one"},
-		TCountItem{987, "#one"},
-		TCountItem{235, "two"},
-		TCountItem{235, "two"},
+		TCountItem{Count: 321, Tag: "#one"},
+		TCountItem{Count: 654, Tag: "#one"},
+		TCountItem{Count: 987, Tag: "#one"},
+		TCountItem{Count: 235, Tag: "two"},
+		TCountItem{Count: 235, Tag: "two"},
 	}
 
 	tests := []struct {
@@ -237,9 +237,9 @@ func TestTCountList_sort(t *testing.T) {
 
 func TestTCountList_String(t *testing.T) {
 	cl := TCountList{
-		TCountItem{123, "one"},
-		TCountItem{234, "pure"},
-		TCountItem{345, "three"},
+		TCountItem{Count: 123, Tag: "one"},
+		TCountItem{Count: 234, Tag: "pure"},
+		TCountItem{Count: 345, Tag: "three"},
 	}
 	ws := "one: 123\npure: 234\nthree: 345\n"
 
@@ -264,19 +264,19 @@ func TestTCountList_String(t *testing.T) {
 func TestTCountList_Swap(t *testing.T) {
 	c1, c2, c3 := 123, 234, 345
 	cl := &TCountList{
-		TCountItem{c3, "three"},
-		TCountItem{c2, "pure"},
-		TCountItem{c1, "one"},
+		TCountItem{Count: c3, Tag: "three"},
+		TCountItem{Count: c2, Tag: "pure"},
+		TCountItem{Count: c1, Tag: "one"},
 	}
 	wl2 := TCountList{
-		TCountItem{c2, "pure"},
-		TCountItem{c3, "three"},
-		TCountItem{c1, "one"},
+		TCountItem{Count: c2, Tag: "pure"},
+		TCountItem{Count: c3, Tag: "three"},
+		TCountItem{Count: c1, Tag: "one"},
 	}
 	wl3 := TCountList{
-		TCountItem{c2, "pure"},
-		TCountItem{c1, "one"},
-		TCountItem{c3, "three"},
+		TCountItem{Count: c2, Tag: "pure"},
+		TCountItem{Count: c1, Tag: "one"},
+		TCountItem{Count: c3, Tag: "three"},
 	}
 
 	type tArgs struct {
@@ -0,0 +1,435 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	se "github.com/mwat56/sourceerror"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `TTagDiff` lists the changes of the IDs associated with a tag.
+	TTagDiff[ID cmp.Ordered] struct {
+		Tag     string // the normalised `#hashtag` or `@mention`
+		Added   []ID   // IDs associated only in the newer list
+		Removed []ID   // IDs associated only in the older list
+	}
+
+	// `TIndexDiff` lists the changes between two lists.
+	//
+	// Its `String()` method returns the changes in a textual patch
+	// format which can be read by [ParseDiff] and applied to a list
+	// by [TIndex.ApplyDiff].
+	TIndexDiff[ID cmp.Ordered] struct {
+		AddedTags   []string       // tags only present in the newer list
+		RemovedTags []string       // tags only present in the older list
+		Tags        []TTagDiff[ID] // the changes of all tags, sorted by tag
+	}
+)
+
+var (
+	// `ErrBadPatch` is returned if a patch can't be parsed.
+	ErrBadPatch = errors.New("malformed patch")
+
+	// match: [+#hashtag] or [-@mention] or [#hashtag]
+	htPatchHeadRE = regexp.MustCompile(`^\[\s*([+-]?)\s*([#@][^\]]*?)\s*\]$`)
+	//                                        11111       2222222222222
+)
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `Diff()` returns the changes between the lists `aOld` and `aNew`.
+//
+// Only the lists' tags and IDs are compared; to compare the lists'
+// scopes call this function with the respective scopes.
+//
+// Parameters:
+//   - `aOld`: The older list.
+//   - `aNew`: The newer list.
+//
+// Returns:
+//   - `TIndexDiff[ID]`: The changes turning `aOld` into `aNew`.
+func Diff[ID cmp.Ordered](aOld, aNew *TIndex[ID]) TIndexDiff[ID] {
+	oldMap, newMap := newHashMap[ID](), newHashMap[ID]()
+	if nil != aOld {
+		oldMap, _ = aOld.snapshot()
+	}
+	if nil != aNew {
+		newMap, _ = aNew.snapshot()
+	}
+
+	return diffMaps(*oldMap, *newMap)
+} // Diff()
+
+// `DiffFiles()` returns the changes between the [THashTags] files
+// `aOld` and `aNew`, see [DiffIndexFiles] for details.
+//
+// Parameters:
+//   - `aOld`: The name of the older file.
+//   - `aNew`: The name of the newer file.
+//
+// Returns:
+//   - `TIndexDiff[int64]`: The changes turning `aOld` into `aNew`.
+//   - `error`: `nil` in case of success, or a possible I/O error.
+func DiffFiles(aOld, aNew string) (TIndexDiff[int64], error) {
+	return DiffIndexFiles[int64](aOld, aNew, nil)
+} // DiffFiles()
+
+// `DiffIndexFiles()` returns the changes between the [TIndex] files
+// `aOld` and `aNew`.
+//
+// The files are expected in the format selected by [UseBinaryStorage].
+//
+// Parameters:
+//   - `aOld`: The name of the older file.
+//   - `aNew`: The name of the newer file.
+//   - `aCodec`: The codec for the IDs (`nil` = [DefaultCodec]).
+//
+// Returns:
+//   - `TIndexDiff[ID]`: The changes turning `aOld` into `aNew`.
+//   - `error`: `nil` in case of success, or a possible I/O error.
+func DiffIndexFiles[ID cmp.Ordered](aOld, aNew string, aCodec TIDCodec[ID]) (TIndexDiff[ID], error) {
+	maps := make([]*tHashMap[ID], 0, 2)
+	for _, fn := range []string{aOld, aNew} {
+		if _, err := os.Stat(fn); nil != err {
+			return TIndexDiff[ID]{}, se.New(loadError(fn, err), 1)
+		}
+		hm, xt := newHashMap[ID](), newExtras[ID]()
+		xt.ic = aCodec
+		if _, err := hm.loadFile(context.Background(), fn, xt, LoadLenient, nil, nil); nil != err {
+			return TIndexDiff[ID]{}, err // already wrapped
+		}
+		maps = append(maps, hm)
+	}
+
+	return diffMaps(*maps[0], *maps[1]), nil
+} // DiffIndexFiles()
+
+// `diffMaps()` returns the changes between the hash maps `aOld`
+// and `aNew`.
+//
+// Parameters:
+//   - `aOld`: The older hash map.
+//   - `aNew`: The newer hash map.
+//
+// Returns:
+//   - `TIndexDiff[ID]`: The changes turning `aOld` into `aNew`.
+func diffMaps[ID cmp.Ordered](aOld, aNew tHashMap[ID]) TIndexDiff[ID] {
+	var result TIndexDiff[ID]
+
+	keys := make([]string, 0, len(aNew))
+	for key := range aOld {
+		keys = append(keys, key)
+	}
+	for key := range aNew {
+		if _, ok := aOld[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, cmp4sort)
+
+	for _, key := range keys {
+		var oldList, newList tSourceList[ID]
+		if sl, ok := aOld[key]; ok && (nil != sl) {
+			oldList = *sl
+		}
+		if sl, ok := aNew[key]; ok && (nil != sl) {
+			newList = *sl
+		}
+		added, removed := oldList.diff(newList)
+		if (0 == len(added)) && (0 == len(removed)) {
+			continue
+		}
+
+		switch {
+		case 0 == len(oldList):
+			result.AddedTags = append(result.AddedTags, key)
+		case 0 == len(newList):
+			result.RemovedTags = append(result.RemovedTags, key)
+		}
+		result.Tags = append(result.Tags,
+			TTagDiff[ID]{Tag: key, Added: added, Removed: removed})
+	}
+
+	return result
+} // diffMaps()
+
+// `ParseDiff()` reads a patch written by [TIndexDiff.String] for
+// a [THashTags] list.
+//
+// Parameters:
+//   - `aPatch`: The patch to read.
+//
+// Returns:
+//   - `TIndexDiff[int64]`: The changes read.
+//   - `error`: `nil` in case of success, or `ErrBadPatch` otherwise.
+func ParseDiff(aPatch string) (TIndexDiff[int64], error) {
+	return ParseIndexDiff[int64](aPatch, nil)
+} // ParseDiff()
+
+// `ParseIndexDiff()` reads a patch written by [TIndexDiff.Patch].
+//
+// The patch consists of one section per tag: a header like `[#tag]`
+// (`[+#tag]` for an added, `[-#tag]` for a removed tag) followed by
+// one line per ID starting with `+` for an added or `-` for a removed
+// association. Empty lines are ignored.
+//
+// Parameters:
+//   - `aPatch`: The patch to read.
+//   - `aCodec`: The codec for the IDs (`nil` = [DefaultCodec]).
+//
+// Returns:
+//   - `TIndexDiff[ID]`: The changes read.
+//   - `error`: `nil` in case of success, or `ErrBadPatch` otherwise.
+func ParseIndexDiff[ID cmp.Ordered](aPatch string, aCodec TIDCodec[ID]) (TIndexDiff[ID], error) {
+	var result TIndexDiff[ID]
+	if nil == aCodec {
+		aCodec = DefaultCodec[ID]()
+	}
+
+	current := -1 // index of the current tag's changes
+	for idx, line := range strings.Split(aPatch, "\n") {
+		if line = strings.TrimSpace(line); "" == line {
+			continue
+		}
+
+		switch line[0] {
+		case '[':
+			matches := htPatchHeadRE.FindStringSubmatch(line)
+			if nil == matches {
+				return result, se.New(fmt.Errorf("%w: line %d: %q", ErrBadPatch, idx+1, line), 2)
+			}
+			tag := normalise(matches[2])
+			switch matches[1] {
+			case "+":
+				result.AddedTags = append(result.AddedTags, tag)
+			case "-":
+				result.RemovedTags = append(result.RemovedTags, tag)
+			}
+			result.Tags = append(result.Tags, TTagDiff[ID]{Tag: tag})
+			current = len(result.Tags) - 1
+
+		case '+', '-':
+			if 0 > current {
+				return result, se.New(fmt.Errorf("%w: line %d: ID outside of a tag's section", ErrBadPatch, idx+1), 1)
+			}
+			id, err := aCodec.DecodeID(strings.TrimSpace(line[1:]))
+			if nil != err {
+				return result, se.New(fmt.Errorf("%w: line %d: %w", ErrBadPatch, idx+1, err), 2)
+			}
+			td := &result.Tags[current]
+			if '+' == line[0] {
+				td.Added = append(td.Added, id)
+			} else {
+				td.Removed = append(td.Removed, id)
+			}
+
+		default:
+			return result, se.New(fmt.Errorf("%w: line %d: %q", ErrBadPatch, idx+1, line), 1)
+		}
+	}
+
+	return result, nil
+} // ParseIndexDiff()
+
+// -------------------------------------------------------------------------
+// methods of `TIndexDiff`:
+
+// `clone()` returns a deep copy of `df`.
+//
+// Returns:
+//   - `TIndexDiff[ID]`: The copy of the changes.
+func (df TIndexDiff[ID]) clone() TIndexDiff[ID] {
+	result := TIndexDiff[ID]{
+		AddedTags:   slices.Clone(df.AddedTags),
+		RemovedTags: slices.Clone(df.RemovedTags),
+		Tags:        make([]TTagDiff[ID], len(df.Tags)),
+	}
+	for idx, td := range df.Tags {
+		result.Tags[idx] = TTagDiff[ID]{
+			Tag:     td.Tag,
+			Added:   slices.Clone(td.Added),
+			Removed: slices.Clone(td.Removed),
+		}
+	}
+
+	return result
+} // clone()
+
+// `Empty()` reports whether there are no changes.
+//
+// Returns:
+//   - `bool`: `true` if both lists are equal, or `false` otherwise.
+func (df TIndexDiff[ID]) Empty() bool {
+	return 0 == len(df.Tags)
+} // Empty()
+
+// `Patch()` returns the changes in the textual patch format (see
+// [ParseIndexDiff]).
+//
+// Parameters:
+//   - `aCodec`: The codec for the IDs (`nil` = [DefaultCodec]).
+//
+// Returns:
+//   - `string`: The patch.
+func (df TIndexDiff[ID]) Patch(aCodec TIDCodec[ID]) string {
+	if nil == aCodec {
+		aCodec = DefaultCodec[ID]()
+	}
+
+	// the marks of the tags added or removed as a whole
+	marks := make(map[string]string, len(df.AddedTags)+len(df.RemovedTags))
+	for _, tag := range df.RemovedTags {
+		marks[tag] = "-"
+	}
+	for _, tag := range df.AddedTags {
+		marks[tag] = "+"
+	}
+
+	var buf strings.Builder
+	for _, td := range df.Tags {
+		buf.WriteString(fmt.Sprintf("[%s%s]\n", marks[td.Tag], td.Tag))
+		for _, added := range td.Added {
+			buf.WriteString("+" + aCodec.EncodeID(added) + "\n")
+		}
+		for _, removed := range td.Removed {
+			buf.WriteString("-" + aCodec.EncodeID(removed) + "\n")
+		}
+	}
+
+	return buf.String()
+} // Patch()
+
+// `reverse()` returns the changes undoing `df`.
+//
+// Returns:
+//   - `TIndexDiff[ID]`: The reversed changes.
+func (df TIndexDiff[ID]) reverse() TIndexDiff[ID] {
+	result := TIndexDiff[ID]{
+		AddedTags:   df.RemovedTags,
+		RemovedTags: df.AddedTags,
+		Tags:        make([]TTagDiff[ID], len(df.Tags)),
+	}
+	for idx, td := range df.Tags {
+		result.Tags[idx] = TTagDiff[ID]{
+			Tag:     td.Tag,
+			Added:   td.Removed,
+			Removed: td.Added,
+		}
+	}
+
+	return result
+} // reverse()
+
+// `String()` implements the `fmt.Stringer` interface returning the
+// changes in the textual patch format using the [DefaultCodec].
+//
+// Returns:
+//   - `string`: The patch.
+func (df TIndexDiff[ID]) String() string {
+	return df.Patch(nil)
+} // String()
+
+// -------------------------------------------------------------------------
+// methods of `TIndex`:
+
+// `ApplyDiff()` applies the changes `aDiff` to the list, i.e. adds
+// and removes the listed associations.
+//
+// Applying the result of `Diff(a, b)` to a list equal to `a` makes
+// it equal to `b`. Added tags are subject to the list's validation
+// rules (see [TIndex.SetValidation]).
+//
+// Parameters:
+//   - `aDiff`: The changes to apply.
+//
+// Returns:
+//   - `bool`: `true` if the list was changed, or `false` otherwise.
+func (ht *TIndex[ID]) ApplyDiff(aDiff TIndexDiff[ID]) bool {
+	if ht.readOnly() || aDiff.Empty() {
+		return false
+	}
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+	defer ht.deferredStore()()
+
+	if !ht.applyDiff(aDiff) {
+		return false
+	}
+	ht.hs.reset()
+
+	return true
+} // ApplyDiff()
+
+// `applyDiff()` adds and removes the associations listed in `aDiff`.
+//
+// NOTE: This method expects the caller to hold the list's write lock.
+//
+// Parameters:
+//   - `aDiff`: The changes to apply.
+//
+// Returns:
+//   - `bool`: `true` if the list was changed, or `false` otherwise.
+func (ht *TIndex[ID]) applyDiff(aDiff TIndexDiff[ID]) bool {
+	var result bool
+	for _, td := range aDiff.Tags {
+		tag := tagName(td.Tag)
+		if "" == tag {
+			continue
+		}
+		key := normalise(tag)
+
+		changed := false
+		for _, removed := range td.Removed {
+			if ht.hm.removeHM(tag[0], tag, removed) {
+				ht.xt.dropPair(key, removed)
+				changed = true
+			}
+		}
+		if (0 < len(td.Added)) && ht.vr.isValid(key) {
+			ht.xt.Info.note(tag)
+			for _, added := range td.Added {
+				if ht.hm.insert(tag, added) {
+					ht.stampPair(key, added, time.Time{})
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		result = true
+		if 0 > ht.hm.idxLen(tag[0], tag) {
+			// the tag's last ID was removed
+			ht.xt.Info.drop(tag)
+		}
+	}
+	if result {
+		ht.cc.cl = nil
+		ht.invalidate()
+	}
+
+	return result
+} // applyDiff()
+
+/* EoF */
@@ -0,0 +1,123 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+const wantPatch = `[#c]
+-0000000000000002
+[#go]
++0000000000000002
+-0000000000000001
+[-@go]
+-0000000000000001
+[+#rust]
++0000000000000004
+[-#zig]
+-0000000000000001
+`
+
+// `diffLists()` returns the lists used by the `Diff()` tests.
+func diffLists() (*THashTags, *THashTags) {
+	a, _ := New("")
+	a.IDparse(1, []byte("#go and @go and #zig"))
+	a.IDparse(2, []byte("#c"))
+	a.IDparse(3, []byte("#c #go"))
+
+	b, _ := New("")
+	b.IDparse(2, []byte("#go"))
+	b.IDparse(3, []byte("#c #go"))
+	b.IDparse(4, []byte("#Rust"))
+
+	return a, b
+} // diffLists()
+
+func Test_Diff(t *testing.T) {
+	a, b := diffLists()
+
+	diff := Diff(a, b)
+	if !slices.Equal(diff.AddedTags, []string{"#rust"}) {
+		t.Errorf("Diff().AddedTags = %v", diff.AddedTags)
+	}
+	if !slices.Equal(diff.RemovedTags, []string{"@go", "#zig"}) {
+		t.Errorf("Diff().RemovedTags = %v", diff.RemovedTags)
+	}
+	if got := diff.String(); got != wantPatch {
+		t.Errorf("Diff().String() =\n%s\n>>>> want >>>>\n%s", got, wantPatch)
+	}
+	if !Diff(a, a).Empty() || Diff(a, nil).Empty() {
+		t.Error("Diff().Empty() returned the wrong result")
+	}
+
+	// applying the patch turns `a` into `b`
+	parsed, err := ParseDiff(diff.String())
+	if nil != err {
+		t.Fatalf("ParseDiff() error = %v", err)
+	}
+	if !a.ApplyDiff(parsed) {
+		t.Error("THashTags.ApplyDiff() = false, want true")
+	}
+	if !a.hm.equals(*b.hm) {
+		t.Errorf("THashTags.ApplyDiff() =\n%v\n>>>> want >>>>\n%v", a.String(), b.String())
+	}
+	if a.ApplyDiff(parsed) {
+		t.Error("THashTags.ApplyDiff() = true for an applied patch")
+	}
+} // Test_Diff()
+
+func Test_DiffFiles(t *testing.T) {
+	dir := t.TempDir()
+	a, b := diffLists()
+	_ = a.SetFilename(filepath.Join(dir, "old.db"))
+	_ = b.SetFilename(filepath.Join(dir, "new.db"))
+	_, _ = a.Store()
+	_, _ = b.Store()
+
+	diff, err := DiffFiles(a.Filename(), b.Filename())
+	if (nil != err) || (wantPatch != diff.String()) {
+		t.Errorf("DiffFiles() = %v, %v", diff, err)
+	}
+	if _, err = DiffFiles(a.Filename(), filepath.Join(dir, "missing")); nil == err {
+		t.Error("DiffFiles() error = nil for a missing file")
+	}
+} // Test_DiffFiles()
+
+func Test_ParseDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		wantErr bool
+	}{
+		{"0", "", false},
+		{"1", "\n[#go]\n+0000000000000001\n\n", false},
+		{"2", "+0000000000000001\n", true},
+		{"3", "[#go]\n+x\n", true},
+		{"4", "[go]\n", true},
+		{"5", "[#go]\n0000000000000001\n", true},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDiff(tt.patch)
+			if (nil != err) != tt.wantErr {
+				t.Errorf("ParseDiff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (nil != err) && !errors.Is(err, ErrBadPatch) {
+				t.Errorf("ParseDiff() error = %v, want %v", err, ErrBadPatch)
+			}
+		})
+	}
+} // Test_ParseDiff()
+
+/* EoF */
@@ -0,0 +1,194 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"unicode/utf8"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `TFileError` describes a problem with reading or writing the
+	// list's file.
+	//
+	// Use `errors.Is()` to check for the error's `Kind` (e.g.
+	// `ErrCorruptFile`) or its cause (e.g. `fs.ErrPermission`), and
+	// `errors.As()` to get the file's name and the problem's position.
+	TFileError struct {
+		Kind     error  // the sentinel classifying the problem (if any)
+		Err      error  // the underlying error (if any)
+		Filename string // name of the file concerned
+		Line     int    // number of the line concerned (`0` = unknown)
+		Offset   int64  // byte offset of that line (valid if `0 < Line`)
+	}
+)
+
+var (
+	// `ErrCorruptFile` is returned if the list's file can't be
+	// decoded, e.g. because it's truncated.
+	ErrCorruptFile = errors.New("corrupt file")
+
+	// `ErrEmptyFilename` is returned if the list is to be stored
+	// without a filename.
+	ErrEmptyFilename = errors.New("empty filename")
+
+	// `ErrFormatMismatch` is returned if the list's file was written
+	// in the other format (see [UseBinaryStorage]).
+	ErrFormatMismatch = errors.New("file format mismatch")
+
+	// `ErrReadOnly` is returned if the list's file can't be written.
+	ErrReadOnly = errors.New("read-only")
+)
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `isCancelled()` reports whether `aErr` was caused by a context.
+//
+// Parameters:
+//   - `aErr`: The error to check.
+//
+// Returns:
+//   - `bool`: `true` if `aErr` is a context's error, or `false` otherwise.
+func isCancelled(aErr error) bool {
+	return errors.Is(aErr, context.Canceled) ||
+		errors.Is(aErr, context.DeadlineExceeded)
+} // isCancelled()
+
+// `isBinaryLine()` reports whether `aLine` can't be part of a file
+// in plain text format.
+//
+// Parameters:
+//   - `aLine`: The line to check.
+//
+// Returns:
+//   - `bool`: `true` if `aLine` holds binary data, or `false` otherwise.
+func isBinaryLine(aLine []byte) bool {
+	return (0 <= bytes.IndexByte(aLine, 0)) || !utf8.Valid(aLine)
+} // isBinaryLine()
+
+// `isTextFile()` reports whether `aFile` starts like a file written
+// in plain text format.
+//
+// Parameters:
+//   - `aFile`: The file to check.
+//
+// Returns:
+//   - `bool`: `true` if `aFile` seems to hold plain text, or `false` otherwise.
+func isTextFile(aFile io.ReadSeeker) bool {
+	if _, err := aFile.Seek(0, io.SeekStart); nil != err {
+		return false
+	}
+	buf := make([]byte, 512)
+	n, _ := io.ReadFull(aFile, buf)
+	if buf = bytes.TrimSpace(buf[:n]); 0 == len(buf) {
+		return false
+	}
+	if idx := bytes.IndexByte(buf, '\n'); 0 < idx {
+		buf = buf[:idx] // the first line is complete
+	}
+
+	return (('[' == buf[0]) || (textScopeMark == buf[0])) &&
+		!isBinaryLine(buf)
+} // isTextFile()
+
+// `loadError()` returns `aErr` as a [TFileError] for `aFilename`.
+//
+// Errors caused by a context are returned unchanged.
+//
+// Parameters:
+//   - `aFilename`: The name of the file read.
+//   - `aErr`: The error to wrap.
+//
+// Returns:
+//   - `error`: The file error (or `nil` if `aErr` is `nil`).
+func loadError(aFilename string, aErr error) error {
+	if (nil == aErr) || isCancelled(aErr) {
+		return aErr
+	}
+
+	var fe *TFileError
+	if errors.As(aErr, &fe) {
+		fe.Filename = aFilename
+		return fe
+	}
+
+	return &TFileError{Err: aErr, Filename: aFilename}
+} // loadError()
+
+// `storeError()` returns `aErr` as a [TFileError] for `aFilename`.
+//
+// A missing permission is classified as `ErrReadOnly`.
+//
+// Parameters:
+//   - `aFilename`: The name of the file written.
+//   - `aErr`: The error to wrap.
+//
+// Returns:
+//   - `*TFileError`: The file error.
+func storeError(aFilename string, aErr error) *TFileError {
+	result := &TFileError{Err: aErr, Filename: aFilename}
+	if errors.Is(aErr, fs.ErrPermission) {
+		result.Kind = ErrReadOnly
+	}
+
+	return result
+} // storeError()
+
+// -------------------------------------------------------------------------
+// methods of `TFileError`:
+
+// `Error()` implements the `error` interface.
+//
+// The message starts with the file's name and (if known) the
+// position of the problem, e.g. `tags.db:12 (offset 345): corrupt
+// file: bufio.Scanner: token too long`.
+//
+// Returns:
+//   - `string`: The error's message.
+func (fe *TFileError) Error() string {
+	var buf strings.Builder
+
+	buf.WriteString(fe.Filename)
+	if 0 < fe.Line {
+		fmt.Fprintf(&buf, ":%d (offset %d)", fe.Line, fe.Offset)
+	}
+	for _, err := range []error{fe.Kind, fe.Err} {
+		if nil != err {
+			buf.WriteString(": ")
+			buf.WriteString(err.Error())
+		}
+	}
+
+	return buf.String()
+} // Error()
+
+// `Unwrap()` returns the error's `Kind` and `Err` for `errors.Is()`
+// and `errors.As()`.
+//
+// Returns:
+//   - `[]error`: The wrapped errors.
+func (fe *TFileError) Unwrap() []error {
+	result := make([]error, 0, 2)
+	for _, err := range []error{fe.Kind, fe.Err} {
+		if nil != err {
+			result = append(result, err)
+		}
+	}
+
+	return result
+} // Unwrap()
+
+/* EoF */
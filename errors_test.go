@@ -0,0 +1,107 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func Test_TFileError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		fe   *TFileError
+		want string
+	}{
+		{"1", &TFileError{Filename: "a.db"}, "a.db"},
+		{"2", &TFileError{Kind: ErrCorruptFile, Filename: "a.db"}, "a.db: corrupt file"},
+		{"3", &TFileError{Kind: ErrFormatMismatch, Filename: "a.db", Line: 3, Offset: 42}, "a.db:3 (offset 42): file format mismatch"},
+		{"4", &TFileError{Kind: ErrReadOnly, Err: fs.ErrPermission, Filename: "a.db"}, "a.db: read-only: permission denied"},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fe.Error(); got != tt.want {
+				t.Errorf("TFileError.Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+} // Test_TFileError_Error()
+
+func Test_storeError(t *testing.T) {
+	err := error(storeError("a.db", fmt.Errorf("open: %w", fs.ErrPermission)))
+	if !errors.Is(err, ErrReadOnly) || !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("storeError() = %v, want %v", err, ErrReadOnly)
+	}
+	if err = storeError("a.db", fs.ErrClosed); errors.Is(err, ErrReadOnly) {
+		t.Errorf("storeError() = %v, want no %v", err, ErrReadOnly)
+	}
+	if !errors.Is(loadError("a.db", context.Canceled), context.Canceled) {
+		t.Error("loadError() changed the context's error")
+	}
+} // Test_storeError()
+
+func Test_THashTags_fileErrors(t *testing.T) {
+	saveBinary := UseBinaryStorage
+	defer func() {
+		UseBinaryStorage = saveBinary
+	}()
+	dir := t.TempDir()
+
+	ht, _ := New("")
+	if _, err := ht.Store(); !errors.Is(err, ErrEmptyFilename) {
+		t.Errorf("THashTags.Store() error = %v, want %v", err, ErrEmptyFilename)
+	}
+	if err := ht.SetFilename(" "); !errors.Is(err, ErrEmptyFilename) {
+		t.Errorf("THashTags.SetFilename() error = %v, want %v", err, ErrEmptyFilename)
+	}
+	ht.IDparse(1, []byte("#go and @bob"))
+
+	// store in one format, load in the other:
+	for _, binary := range []bool{true, false} {
+		fn := filepath.Join(dir, "mismatch"+exts[binary])
+		UseBinaryStorage = binary
+		_ = ht.SetFilename(fn)
+		if _, err := ht.Store(); nil != err {
+			t.Fatalf("THashTags.Store() error = %v", err)
+		}
+
+		UseBinaryStorage = !binary
+		_, err := New(fn)
+		if !errors.Is(err, ErrFormatMismatch) {
+			t.Errorf("New(%q) error = %v, want %v", fn, err, ErrFormatMismatch)
+		}
+		var fe *TFileError
+		if !errors.As(err, &fe) || (fn != fe.Filename) {
+			t.Errorf("New(%q) error = %v, want a TFileError", fn, err)
+		} else if binary && (1 != fe.Line) {
+			t.Errorf("New(%q) error line = %d, want 1", fn, fe.Line)
+		}
+	}
+
+	// truncated binary file:
+	UseBinaryStorage = true
+	fn := filepath.Join(dir, "corrupt.gob")
+	_ = ht.SetFilename(fn)
+	if _, err := ht.Store(); nil != err {
+		t.Fatalf("THashTags.Store() error = %v", err)
+	}
+	data, _ := os.ReadFile(fn)
+	_ = os.WriteFile(fn, data[:len(data)/2], 0600)
+	if _, err := New(fn); !errors.Is(err, ErrCorruptFile) {
+		t.Errorf("New(%q) error = %v, want %v", fn, err, ErrCorruptFile)
+	}
+} // Test_THashTags_fileErrors()
+
+/* EoF */
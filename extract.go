@@ -0,0 +1,178 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"bufio"
+	"errors"
+	"io"
+
+	se "github.com/mwat56/sourceerror"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `TTag` is a `#hashtag` or `@mention` found in a text.
+	TTag struct {
+		Kind  byte   // either `MarkHash` or `MarkMention`
+		Key   string // normalised tag as used by the index
+		Text  string // tag as found in the text
+		Start int    // byte offset of the tag's leading mark
+		End   int    // byte offset following the tag's last character
+	}
+
+	// `TExtractOptions` configures [Extract] and [ExtractReader].
+	//
+	// The zero value reports all tags found.
+	TExtractOptions struct {
+		Validation TValidation // rules the tags have to satisfy
+		Unique     bool        // report only the first occurrence of each key
+	}
+
+	// `tExtractor` holds the state of a single extraction run.
+	tExtractor struct {
+		vr   *tValidator         // the rules to check the tags with
+		seen map[string]struct{} // keys already reported (if unique)
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newExtractor()` prepares an extraction run using `aOptions`.
+//
+// Parameters:
+//   - `aOptions`: The options to use.
+//
+// Returns:
+//   - `*tExtractor`: The prepared extractor.
+func newExtractor(aOptions TExtractOptions) *tExtractor {
+	ex := &tExtractor{
+		vr: newValidator(aOptions.Validation),
+	}
+	if aOptions.Unique {
+		ex.seen = make(map[string]struct{}, defaultListSize)
+	}
+
+	return ex
+} // newExtractor()
+
+// --------------------------------------------------------------------------
+// extraction functions:
+
+// `Extract()` returns all `#hashtags` and `@mentions` found in `aText`.
+//
+// The tags are identified, normalised, and validated exactly as
+// [THashTags.IDparse] would do when indexing `aText` with the
+// validation rules of `aOptions`; no index is involved.
+//
+// Parameters:
+//   - `aText`: The text to search.
+//   - `aOptions`: The options to use.
+//
+// Returns:
+//   - `[]TTag`: The tags found in order of their occurrence (or `nil`).
+func Extract(aText []byte, aOptions TExtractOptions) []TTag {
+	var result []TTag
+
+	newExtractor(aOptions).extract(aText, 0, func(aTag TTag) bool {
+		result = append(result, aTag)
+		return true
+	})
+
+	return result
+} // Extract()
+
+// `ExtractReader()` reads the text provided by `aReader` and calls
+// `aYield` for each `#hashtag` or `@mention` found.
+//
+// The text is processed line by line, so arbitrarily large inputs
+// can be handled with constant memory; the offsets of the tags are
+// relative to the start of the whole input. Reading stops when
+// `aYield` returns `false`.
+//
+// Parameters:
+//   - `aReader`: The source of the text to search.
+//   - `aOptions`: The options to use.
+//   - `aYield`: The function to call with each tag found.
+//
+// Returns:
+//   - `error`: A possible I/O error.
+func ExtractReader(aReader io.Reader, aOptions TExtractOptions, aYield func(aTag TTag) bool) error {
+	if (nil == aReader) || (nil == aYield) {
+		return se.New(errors.New("missing reader or yield function"), 1)
+	}
+
+	var (
+		err    error
+		line   []byte
+		offset int
+	)
+	ex := newExtractor(aOptions)
+	reader := bufio.NewReader(aReader)
+	for {
+		line, err = reader.ReadBytes('\n')
+		if 0 < len(line) {
+			if !ex.extract(line, offset, aYield) {
+				return nil
+			}
+			offset += len(line)
+		}
+		if nil != err {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return se.New(err, 11)
+		}
+	}
+} // ExtractReader()
+
+// -------------------------------------------------------------------------
+// methods of `tExtractor`:
+
+// `extract()` calls `aYield` for each valid tag found in `aText`.
+//
+// Parameters:
+//   - `aText`: The text to search.
+//   - `aOffset`: The offset of `aText` within the whole input.
+//   - `aYield`: The function to call with each tag found.
+//
+// Returns:
+//   - `bool`: `false` if `aYield` asked to stop, or `true` otherwise.
+func (ex *tExtractor) extract(aText []byte, aOffset int, aYield func(aTag TTag) bool) bool {
+	var (
+		key string
+		tag string
+	)
+	for _, match := range findTags(aText) {
+		tag = string(aText[match.start:match.end])
+		if key = normalise(tag); !ex.vr.isValid(key) {
+			continue
+		}
+		if nil != ex.seen {
+			if _, ok := ex.seen[key]; ok {
+				continue
+			}
+			ex.seen[key] = struct{}{}
+		}
+
+		if !aYield(TTag{
+			Kind:  tag[0],
+			Key:   key,
+			Text:  tag,
+			Start: aOffset + match.start,
+			End:   aOffset + match.end,
+		}) {
+			return false
+		}
+	}
+
+	return true
+} // extract()
+
+/* EoF */
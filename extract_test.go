@@ -0,0 +1,92 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func Test_Extract(t *testing.T) {
+	text := []byte("#Go and @Bob like #go, not #2024 or me@example.com.")
+
+	tests := []struct {
+		name string
+		opts TExtractOptions
+		want []TTag
+	}{
+		{"all", TExtractOptions{}, []TTag{
+			{MarkHash, "#go", "#Go", 0, 3},
+			{MarkMention, "@bob", "@Bob", 8, 12},
+			{MarkHash, "#go", "#go", 18, 21},
+			{MarkHash, "#2024", "#2024", 27, 32},
+		}},
+		{"unique", TExtractOptions{Unique: true}, []TTag{
+			{MarkHash, "#go", "#Go", 0, 3},
+			{MarkMention, "@bob", "@Bob", 8, 12},
+			{MarkHash, "#2024", "#2024", 27, 32},
+		}},
+		{"validated", TExtractOptions{
+			Validation: TValidation{NoNumeric: true, Deny: []string{"@bob"}},
+			Unique:     true,
+		}, []TTag{
+			{MarkHash, "#go", "#Go", 0, 3},
+		}},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Extract(text, tt.opts); !slices.Equal(got, tt.want) {
+				t.Errorf("%q: Extract() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+
+	if got := Extract(nil, TExtractOptions{}); nil != got {
+		t.Errorf("Extract(nil) = %v, want nil", got)
+	}
+} // Test_Extract()
+
+func Test_ExtractReader(t *testing.T) {
+	text := "first #One\nsecond @Two and #one\n\nlast #Three"
+	want := Extract([]byte(text), TExtractOptions{})
+
+	var got []TTag
+	err := ExtractReader(strings.NewReader(text), TExtractOptions{},
+		func(aTag TTag) bool {
+			got = append(got, aTag)
+			return true
+		})
+	if nil != err {
+		t.Errorf("ExtractReader() error = %v", err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ExtractReader() = %v, want %v", got, want)
+	}
+
+	// stop after the first tag
+	got = got[:0]
+	err = ExtractReader(strings.NewReader(text), TExtractOptions{},
+		func(aTag TTag) bool {
+			got = append(got, aTag)
+			return false
+		})
+	if (nil != err) || (1 != len(got)) {
+		t.Errorf("ExtractReader() stopping = %v, %v", got, err)
+	}
+
+	if err = ExtractReader(nil, TExtractOptions{}, nil); nil == err {
+		t.Error("ExtractReader(nil) error = nil, want error")
+	}
+} // Test_ExtractReader()
+
+/* EoF */
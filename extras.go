@@ -0,0 +1,423 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `tExtras` bundles the optional data kept alongside a `tHashMap`
+	// and stored in the same file.
+	//
+	// NOTE: All fields to be stored must be exported to allow for
+	// `gob` encoding.
+	tExtras[ID cmp.Ordered] struct {
+		Info   tTagInfoMap             // additional data per `#hashtag`/`@mention`
+		Pos    tPairMap[ID, []int]     // byte offsets per tag and ID
+		Times  tPairMap[ID, time.Time] // time of association per tag and ID
+		Scopes map[string]*tScope[ID]  // additional indices stored in the same file
+		ic     TIDCodec[ID]            // the text format's codec (not stored)
+	}
+)
+
+const (
+	// `textInfoMark` starts a line of tag metadata in the text format.
+	//
+	// Such a line holds a field name followed by its value, e.g.
+	// `=created 2025-01-02T03:04:05Z` or `=description "Go news"`.
+	textInfoMark = '='
+
+	// `textTimeMark` starts a line of an association's time in the
+	// text format.
+	//
+	// Such a line looks like `+0000000000000001 2025-01-02T03:04:05Z`
+	// i.e. the ID followed by the time it was associated with the tag.
+	textTimeMark = '+'
+
+	// `textScopeMark` starts the section of a scope in the text format.
+	//
+	// Such a line looks like `{blog}` i.e. the scope's name in braces.
+	textScopeMark = '{'
+
+	// `textScopeLine` starts each line of a scope's section in the
+	// text format, hiding those lines from older versions.
+	textScopeLine = '|'
+
+	// `textPosMark` starts a line of tag positions in the text format.
+	//
+	// Such a line looks like `~0000000000000001 12,345` i.e. the ID
+	// followed by a comma separated list of byte offsets.
+	textPosMark = '~'
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newExtras()` returns a new and empty `tExtras` instance.
+//
+// Returns:
+//   - `*tExtras[ID]`: The new `tExtras` instance.
+func newExtras[ID cmp.Ordered]() *tExtras[ID] {
+	return &tExtras[ID]{
+		Info: make(tTagInfoMap, defaultListSize),
+	}
+} // newExtras()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `earlierTime()` returns the earlier one of `aOld` and `aNew`.
+//
+// Parameters:
+//   - `aOld`: The first time to compare.
+//   - `aNew`: The second time to compare.
+//
+// Returns:
+//   - `time.Time`: The earlier time.
+func earlierTime(aOld, aNew time.Time) time.Time {
+	if aNew.Before(aOld) {
+		return aNew
+	}
+
+	return aOld
+} // earlierTime()
+
+// `mergeOffsets()` returns the sorted union of `aOld` and `aNew`.
+//
+// Parameters:
+//   - `aOld`: The first list of offsets.
+//   - `aNew`: The second list of offsets.
+//
+// Returns:
+//   - `[]int`: The combined list of offsets.
+func mergeOffsets(aOld, aNew []int) []int {
+	result := append(slices.Clone(aOld), aNew...)
+	slices.Sort(result)
+
+	return slices.Compact(result)
+} // mergeOffsets()
+
+// `sortedIDs()` returns the sorted keys of `aMap`.
+//
+// Parameters:
+//   - `aMap`: The map whose keys to return.
+//
+// Returns:
+//   - `[]ID`: The sorted IDs.
+func sortedIDs[ID cmp.Ordered, V any](aMap map[ID]V) []ID {
+	result := make([]ID, 0, len(aMap))
+	for id := range aMap {
+		result = append(result, id)
+	}
+	slices.Sort(result)
+
+	return result
+} // sortedIDs()
+
+// -------------------------------------------------------------------------
+// methods of `tExtras`:
+
+// `clear()` removes all data.
+//
+// Returns:
+//   - `*tExtras[ID]`: The cleared instance.
+func (xt *tExtras[ID]) clear() *tExtras[ID] {
+	if nil != xt {
+		xt.Info.clear()
+		xt.Pos.clear()
+		xt.Times.clear()
+		clear(xt.Scopes)
+	}
+
+	return xt
+} // clear()
+
+// `codec()` returns the codec to use for IDs in the text format.
+//
+// Returns:
+//   - `TIDCodec[ID]`: The configured codec, or the [DefaultCodec].
+func (xt *tExtras[ID]) codec() TIDCodec[ID] {
+	if (nil == xt) || (nil == xt.ic) {
+		return DefaultCodec[ID]()
+	}
+
+	return xt.ic
+} // codec()
+
+// `cutID()` splits `aLine` at its last space into an ID and the
+// data following it.
+//
+// The last space is used since the text of an ID (e.g. a quoted
+// string) may contain spaces itself.
+//
+// Parameters:
+//   - `aLine`: The line to split (without its leading mark).
+//
+// Returns:
+//   - `ID`: The decoded ID.
+//   - `string`: The data following the ID.
+//   - `bool`: `true` if `aLine` starts with a valid ID, or `false` otherwise.
+func (xt *tExtras[ID]) cutID(aLine string) (ID, string, bool) {
+	var zero ID
+
+	idx := strings.LastIndexByte(aLine, ' ')
+	if 0 > idx {
+		return zero, "", false
+	}
+	id, err := xt.codec().DecodeID(aLine[:idx])
+	if nil != err {
+		return zero, "", false
+	}
+
+	return id, aLine[idx+1:], true
+} // cutID()
+
+// `dropID()` removes all data associated with `aID`.
+//
+// Parameters:
+//   - `aID`: The ID whose data is to be removed.
+func (xt *tExtras[ID]) dropID(aID ID) {
+	xt.Pos.dropID(aID)
+	xt.Times.dropID(aID)
+} // dropID()
+
+// `dropPair()` removes the data associated with `aTag` and `aID`.
+//
+// Parameters:
+//   - `aTag`: The tag whose data is to be removed.
+//   - `aID`: The ID whose data is to be removed.
+func (xt *tExtras[ID]) dropPair(aTag string, aID ID) {
+	key := normalise(aTag)
+	xt.Pos.dropPair(key, aID)
+	xt.Times.dropPair(key, aID)
+} // dropPair()
+
+// `isEmpty()` reports whether there is no data to store.
+//
+// Returns:
+//   - `bool`: `true` if all data containers are empty.
+func (xt *tExtras[ID]) isEmpty() bool {
+	return (nil == xt) ||
+		((0 == len(xt.Info)) && (0 == len(xt.Pos)) &&
+			(0 == len(xt.Times)) && (0 == len(xt.Scopes)))
+} // isEmpty()
+
+// `merge()` moves the data of all `aSources` tags to `aTarget`.
+//
+// Parameters:
+//   - `aSources`: The tags whose data is to be moved.
+//   - `aTarget`: The tag to receive the data.
+func (xt *tExtras[ID]) merge(aSources []string, aTarget string) {
+	xt.Info.merge(aSources, aTarget)
+
+	keys := make([]string, 0, len(aSources))
+	for _, source := range aSources {
+		keys = append(keys, normalise(source))
+	}
+	target := normalise(aTarget)
+	xt.Pos.merge(keys, target, mergeOffsets)
+	xt.Times.merge(keys, target, earlierTime)
+} // merge()
+
+// `parseInfo()` reads a metadata field of `aKey` from `aLine`.
+//
+// Parameters:
+//   - `aKey`: The normalised tag of the current section.
+//   - `aLine`: The line to parse (without its leading mark).
+//
+// Returns:
+//   - `bool`: `true` if `aLine` was handled, or `false` otherwise.
+func (xt *tExtras[ID]) parseInfo(aKey, aLine string) bool {
+	name, value, _ := strings.Cut(aLine, " ")
+
+	var err error
+	ti := xt.Info.info(aKey)
+	switch name {
+	case "created":
+		ti.Created, err = time.Parse(time.RFC3339Nano, value)
+	case "modified":
+		ti.Modified, err = time.Parse(time.RFC3339Nano, value)
+	case "description":
+		ti.Description, err = strconv.Unquote(value)
+	case "pinned":
+		ti.Pinned = true
+	default:
+		return false
+	}
+
+	return nil == err
+} // parseInfo()
+
+// `parseText()` reads the data of `aKey` from `aLine` of a file in
+// the plain text format.
+//
+// Parameters:
+//   - `aKey`: The normalised tag of the current section.
+//   - `aLine`: The line to parse.
+//
+// Returns:
+//   - `bool`: `true` if `aLine` was handled, or `false` otherwise.
+func (xt *tExtras[ID]) parseText(aKey, aLine string) bool {
+	if ("" == aKey) || (2 > len(aLine)) {
+		return false
+	}
+
+	switch aLine[0] {
+	case textInfoMark:
+		return xt.parseInfo(aKey, aLine[1:])
+
+	case textPosMark:
+		id, offStr, ok := xt.cutID(aLine[1:])
+		if !ok {
+			return false
+		}
+		parts := strings.Split(offStr, ",")
+		offsets := make([]int, 0, len(parts))
+		for _, part := range parts {
+			if off, err := strconv.Atoi(part); nil == err {
+				offsets = append(offsets, off)
+			}
+		}
+		xt.Pos.set(aKey, id, offsets)
+		return true
+
+	case textTimeMark:
+		id, timeStr, ok := xt.cutID(aLine[1:])
+		if !ok {
+			return false
+		}
+		at, err := time.Parse(time.RFC3339Nano, timeStr)
+		if nil != err {
+			return false
+		}
+		xt.Times.set(aKey, id, at)
+		return true
+	}
+
+	return false
+} // parseText()
+
+// `prune()` removes the data of all tags and associations not present
+// in `aMap`.
+//
+// Parameters:
+//   - `aMap`: The hash map whose data are to be kept.
+func (xt *tExtras[ID]) prune(aMap tHashMap[ID]) {
+	xt.Info.prune(aMap.has)
+	xt.Pos.prune(aMap)
+	xt.Times.prune(aMap)
+} // prune()
+
+// `renameID()` replaces `aOldID` by `aNewID` in all data.
+//
+// Parameters:
+//   - `aOldID`: The ID to be replaced.
+//   - `aNewID`: The replacement ID.
+func (xt *tExtras[ID]) renameID(aOldID, aNewID ID) {
+	xt.Pos.renameID(aOldID, aNewID, mergeOffsets)
+	xt.Times.renameID(aOldID, aNewID, earlierTime)
+} // renameID()
+
+// `renormalise()` applies the current [Normalisation] to all keys.
+func (xt *tExtras[ID]) renormalise() {
+	xt.Info.renormalise()
+	xt.Pos.renormalise(mergeOffsets)
+	xt.Times.renormalise(earlierTime)
+} // renormalise()
+
+// `union()` adds all data of `aOther` to this instance.
+//
+// Existing tag metadata is kept, offsets are combined, and the
+// earlier one of two association times is used. The scopes of
+// `aOther` are added to the respective scopes of this instance.
+//
+// Parameters:
+//   - `aOther`: The data to add.
+//
+// Returns:
+//   - `bool`: `true` if at least one tag, ID or pair was added, or `false` otherwise.
+func (xt *tExtras[ID]) union(aOther *tExtras[ID]) bool {
+	if nil == aOther {
+		return false
+	}
+	if nil == xt.Info {
+		xt.Info = make(tTagInfoMap, len(aOther.Info))
+	}
+	result := xt.Info.union(aOther.Info)
+	result = xt.Pos.union(aOther.Pos, mergeOffsets) || result
+	result = xt.Times.union(aOther.Times, earlierTime) || result
+
+	for name, other := range aOther.Scopes {
+		if nil == other {
+			continue
+		}
+		sc := xt.scope(name)
+		if (nil != other.Map) && sc.Map.union(*other.Map) {
+			result = true
+		}
+		result = sc.Extras.union(other.Extras) || result
+	}
+
+	return result
+} // union()
+
+// `writeText()` appends the data of `aKey` in the plain text format
+// to `aBuf`.
+//
+// Parameters:
+//   - `aBuf`: The buffer to write to.
+//   - `aKey`: The normalised tag whose data is to be written.
+func (xt *tExtras[ID]) writeText(aBuf *bytes.Buffer, aKey string) {
+	if ti, ok := xt.Info[aKey]; ok {
+		if !ti.Created.IsZero() {
+			aBuf.WriteString(fmt.Sprintf("%ccreated %s\n",
+				textInfoMark, ti.Created.Format(time.RFC3339Nano)))
+		}
+		if !ti.Modified.IsZero() {
+			aBuf.WriteString(fmt.Sprintf("%cmodified %s\n",
+				textInfoMark, ti.Modified.Format(time.RFC3339Nano)))
+		}
+		if "" != ti.Description {
+			aBuf.WriteString(fmt.Sprintf("%cdescription %q\n",
+				textInfoMark, ti.Description))
+		}
+		if ti.Pinned {
+			aBuf.WriteString(fmt.Sprintf("%cpinned\n", textInfoMark))
+		}
+	}
+
+	codec := xt.codec()
+	if ids, ok := xt.Times[aKey]; ok {
+		for _, id := range sortedIDs(ids) {
+			aBuf.WriteString(fmt.Sprintf("%c%s %s\n",
+				textTimeMark, codec.EncodeID(id), ids[id].Format(time.RFC3339Nano)))
+		}
+	}
+
+	if ids, ok := xt.Pos[aKey]; ok {
+		var offs []string
+		for _, id := range sortedIDs(ids) {
+			offs = offs[:0]
+			for _, off := range ids[id] {
+				offs = append(offs, strconv.Itoa(off))
+			}
+			aBuf.WriteString(fmt.Sprintf("%c%s %s\n",
+				textPosMark, codec.EncodeID(id), strings.Join(offs, ",")))
+		}
+	}
+} // writeText()
+
+/* EoF */
@@ -0,0 +1,108 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func Test_tExtras_isEmpty(t *testing.T) {
+	var xt0 *tExtras[int64]
+	xt1 := newExtras[int64]()
+	xt2 := newExtras[int64]()
+	xt2.Info.note("#Tag")
+	xt3 := newExtras[int64]()
+	xt3.Info.note("#Tag")
+	xt3.clear()
+
+	tests := []struct {
+		name string
+		xt   *tExtras[int64]
+		want bool
+	}{
+		{"0", xt0, true},
+		{"1", xt1, true},
+		{"2", xt2, false},
+		{"3", xt3, true},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.xt.isEmpty(); got != tt.want {
+				t.Errorf("%q: tExtras.isEmpty() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_tExtras_isEmpty()
+
+func Test_tExtras_parseText(t *testing.T) {
+	xt := newExtras[int64]()
+
+	tests := []struct {
+		name string
+		key  string
+		line string
+		want bool
+	}{
+		{"empty", "#tag", "", false},
+		{"no key", "", "~0000000000000001 2,3", false},
+		{"no mark", "#tag", "0000000000000001", false},
+		{"no offsets", "#tag", "~0000000000000001", false},
+		{"bad ID", "#tag", "~xyz 1", false},
+		{"valid", "#tag", "~0000000000000001 2,3", true},
+		{"created", "#tag", "=created 2025-01-02T03:04:05Z", true},
+		{"bad time", "#tag", "=modified yesterday", false},
+		{"description", "#tag", `=description "a \"b\""`, true},
+		{"pinned", "#tag", "=pinned", true},
+		{"unknown field", "#tag", "=colour red", false},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := xt.parseText(tt.key, tt.line); got != tt.want {
+				t.Errorf("%q: tExtras.parseText() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+
+	if got, _ := xt.Pos.get("#tag", 1); !slices.Equal(got, []int{2, 3}) {
+		t.Errorf("tExtras.parseText() offsets = %v, want [2 3]", got)
+	}
+	if ti := xt.Info["#tag"]; (`a "b"` != ti.Description) || !ti.Pinned ||
+		(2025 != ti.Created.Year()) {
+		t.Errorf("tExtras.parseText() info = %v", ti)
+	}
+} // Test_tExtras_parseText()
+
+func Test_tExtras_writeText(t *testing.T) {
+	xt := newExtras[int64]()
+	xt.Pos.set("#tag", 2, []int{5})
+	xt.Pos.set("#tag", 1, []int{2, 3})
+
+	var buf bytes.Buffer
+	xt.writeText(&buf, "#tag")
+	want := "~0000000000000001 2,3\n~0000000000000002 5\n"
+	if got := buf.String(); got != want {
+		t.Errorf("tExtras.writeText() = %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	xt.writeText(&buf, "#none")
+	if 0 != buf.Len() {
+		t.Errorf("tExtras.writeText() unknown key = %q", buf.String())
+	}
+} // Test_tExtras_writeText()
+
+/* EoF */
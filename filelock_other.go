@@ -0,0 +1,30 @@
+//go:build !unix
+
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"os"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+// `lockFile()` does nothing on platforms without advisory file locks.
+//
+// Parameters:
+//   - `aFile`: The file to lock.
+//   - `aExclusive`: `true` for a write lock, `false` for a read lock.
+//
+// Returns:
+//   - `error`: Always `nil`.
+func lockFile(aFile *os.File, aExclusive bool) error {
+	return nil
+} // lockFile()
+
+/* EoF */
@@ -0,0 +1,44 @@
+//go:build unix
+
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"os"
+	"syscall"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+// `lockFile()` acquires an advisory lock of `aFile` waiting until
+// the lock is available.
+//
+// The lock is released by closing the file.
+//
+// Parameters:
+//   - `aFile`: The file to lock.
+//   - `aExclusive`: `true` for a write lock, `false` for a read lock.
+//
+// Returns:
+//   - `error`: A possible I/O error.
+func lockFile(aFile *os.File, aExclusive bool) error {
+	how := syscall.LOCK_SH
+	if aExclusive {
+		how = syscall.LOCK_EX
+	}
+
+	for {
+		err := syscall.Flock(int(aFile.Fd()), how)
+		if syscall.EINTR != err {
+			return err
+		}
+	}
+} // lockFile()
+
+/* EoF */
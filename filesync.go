@@ -0,0 +1,418 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"time"
+
+	se "github.com/mwat56/sourceerror"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `TConflictPolicy` determines how a list handles changes of its
+	// file made by other processes (or other list instances).
+	TConflictPolicy uint8
+
+	// `tFileStamp` describes the state of a list's file.
+	tFileStamp struct {
+		mod  time.Time // time of the last modification
+		size int64     // size in bytes
+		crc  uint32    // checksum of the file's contents
+	}
+)
+
+const (
+	// `ConflictLastWins` lets the last writer win: [TIndex.Store]
+	// overwrites the file, and [TIndex.Reload] replaces the list by
+	// the file's data (the default and former behaviour).
+	ConflictLastWins TConflictPolicy = iota
+
+	// `ConflictMerge` combines both versions: [TIndex.Store] and
+	// [TIndex.Reload] add the file's data to the list's unsaved
+	// changes (removals made by either side may be undone).
+	ConflictMerge
+
+	// `ConflictFail` rejects overwriting external changes:
+	// [TIndex.Store] returns `ErrConflict` if the file was changed
+	// since it was last read or written, and [TIndex.Reload] does so
+	// if the list has unsaved changes.
+	ConflictFail
+)
+
+var (
+	// `ErrConflict` is returned if the list's file was changed by
+	// another process and the conflict policy is `ConflictFail`.
+	ErrConflict = errors.New("file changed externally")
+)
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `contentSum()` returns a checksum of the tags and IDs of a list
+// including their additional data and all the list's scopes.
+//
+// Parameters:
+//   - `aMap`: The list's hash map.
+//   - `aExtras`: The list's additional data.
+//
+// Returns:
+//   - `uint32`: The computed checksum.
+func contentSum[ID cmp.Ordered](aMap *tHashMap[ID], aExtras *tExtras[ID]) uint32 {
+	// The text format is sorted (and includes the tags' metadata and
+	// the scopes) thus generating reproducible results
+	return crc32.Update(0, gCRCtable, []byte(aMap.text(aExtras)))
+} // contentSum()
+
+// `openLocked()` opens (or creates) `aFilename` for writing and
+// acquires an exclusive advisory lock of it.
+//
+// The lock is released by closing the returned file.
+//
+// Parameters:
+//   - `aFilename`: The name of the file to open.
+//
+// Returns:
+//   - `*os.File`: The opened and locked file.
+//   - `error`: A possible I/O error.
+func openLocked(aFilename string) (*os.File, error) {
+	file, err := os.OpenFile(aFilename, os.O_RDWR|os.O_CREATE, 0660) //#nosec G302 #nosec G304
+	if nil != err {
+		return nil, err
+	}
+	if err = lockFile(file, true); nil != err {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return file, nil
+} // openLocked()
+
+// `stampFile()` returns the current state of `aFile`.
+//
+// The file's contents are only read if its modification time or
+// size differ from `aKnown`.
+//
+// Parameters:
+//   - `aFile`: The file to check.
+//   - `aKnown`: The file's state known so far.
+//
+// Returns:
+//   - `tFileStamp`: The file's current state.
+//   - `error`: A possible I/O error.
+func stampFile(aFile *os.File, aKnown tFileStamp) (tFileStamp, error) {
+	fi, err := aFile.Stat()
+	if nil != err {
+		return aKnown, err
+	}
+	if fi.ModTime().Equal(aKnown.mod) && (fi.Size() == aKnown.size) {
+		return aKnown, nil
+	}
+
+	sum := crc32.New(gCRCtable)
+	if _, err = io.Copy(sum, io.NewSectionReader(aFile, 0, fi.Size())); nil != err {
+		return aKnown, err
+	}
+
+	return tFileStamp{mod: fi.ModTime(), size: fi.Size(), crc: sum.Sum32()}, nil
+} // stampFile()
+
+// `stampName()` returns the current state of the file `aFilename`.
+//
+// Parameters:
+//   - `aFilename`: The name of the file to check.
+//   - `aKnown`: The file's state known so far.
+//
+// Returns:
+//   - `tFileStamp`: The file's current state (zero if it doesn't exist).
+//   - `error`: A possible I/O error.
+func stampName(aFilename string, aKnown tFileStamp) (tFileStamp, error) {
+	file, err := os.OpenFile(aFilename, os.O_RDONLY, 0) //#nosec G304
+	if nil != err {
+		if os.IsNotExist(err) {
+			return tFileStamp{}, nil
+		}
+		return aKnown, err
+	}
+	defer file.Close()
+	if err = lockFile(file, false); nil != err {
+		return aKnown, err
+	}
+
+	return stampFile(file, aKnown)
+} // stampName()
+
+// `stampOf()` returns the state of `aFile` with the given checksum.
+//
+// Parameters:
+//   - `aFile`: The file to check.
+//   - `aCRC`: The checksum of the file's contents.
+//
+// Returns:
+//   - `tFileStamp`: The file's current state.
+//   - `error`: A possible I/O error.
+func stampOf(aFile *os.File, aCRC uint32) (tFileStamp, error) {
+	fi, err := aFile.Stat()
+	if nil != err {
+		return tFileStamp{}, err
+	}
+
+	return tFileStamp{mod: fi.ModTime(), size: fi.Size(), crc: aCRC}, nil
+} // stampOf()
+
+// -------------------------------------------------------------------------
+// methods of `tFileStamp`:
+
+// `differs()` reports whether the file's contents differ from
+// those described by `aOther`.
+//
+// Parameters:
+//   - `aOther`: The file state to compare with.
+//
+// Returns:
+//   - `bool`: `true` if the contents differ, or `false` otherwise.
+func (fs tFileStamp) differs(aOther tFileStamp) bool {
+	return (fs.size != aOther.size) || (fs.crc != aOther.crc)
+} // differs()
+
+// `missing()` reports whether the file didn't exist.
+//
+// Returns:
+//   - `bool`: `true` if the file didn't exist, or `false` otherwise.
+func (fs tFileStamp) missing() bool {
+	return fs.mod.IsZero()
+} // missing()
+
+// -------------------------------------------------------------------------
+// methods of `TIndex`:
+
+// `ConflictPolicy()` returns how the list handles changes of its file
+// made by other processes.
+//
+// Returns:
+//   - `TConflictPolicy`: The current conflict policy.
+func (ht *TIndex[ID]) ConflictPolicy() TConflictPolicy {
+	if nil != ht.root {
+		return ht.root.ConflictPolicy()
+	}
+
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	return ht.cp
+} // ConflictPolicy()
+
+// `Modified()` reports whether the list's file was changed by another
+// process (or list instance) since the list last read or wrote it.
+//
+// Returns:
+//   - `bool`: `true` if the file was changed, or `false` otherwise.
+//   - `error`: A possible I/O error.
+func (ht *TIndex[ID]) Modified() (bool, error) {
+	if nil != ht.root {
+		return ht.root.Modified()
+	}
+
+	if ht.safe {
+		ht.mtx.RLock()
+	}
+	fn, known := ht.fn, ht.fst
+	if ht.safe {
+		ht.mtx.RUnlock()
+	}
+	if "" == fn {
+		return false, nil
+	}
+
+	current, err := stampName(fn, known)
+	if nil != err {
+		return false, se.New(loadError(fn, err), 1)
+	}
+
+	return current.differs(known), nil
+} // Modified()
+
+// `Reload()` reads the list's file again if it was changed by another
+// process (or list instance) since the list last read or wrote it.
+//
+// How the file's data replace or complement the list's data depends
+// on the list's conflict policy (see [TIndex.SetConflictPolicy]).
+// If the file doesn't exist (anymore) the list remains unchanged.
+//
+// Returns:
+//   - `bool`: `true` if the file was read, or `false` otherwise.
+//   - `error`: `nil` in case of success, or `ErrConflict` or a possible I/O error.
+func (ht *TIndex[ID]) Reload() (bool, error) {
+	if nil != ht.root {
+		return ht.root.Reload()
+	}
+
+	if ht.safe {
+		ht.mtx.RLock()
+	}
+	fn, codec, known := ht.fn, ht.xt.ic, ht.fst
+	if ht.safe {
+		ht.mtx.RUnlock()
+	}
+	if "" == fn {
+		return false, nil
+	}
+
+	current, err := stampName(fn, known)
+	if nil != err {
+		return false, se.New(loadError(fn, err), 1)
+	}
+	if current.missing() || !current.differs(known) {
+		return false, nil
+	}
+
+	var stamp tFileStamp
+	hm, xt := newHashMap[ID](), newExtras[ID]()
+	xt.ic = codec
+	if _, err = hm.loadFile(context.Background(), fn, xt, LoadLenient, nil, &stamp); nil != err {
+		return false, err // already wrapped
+	}
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+	unsaved := contentSum(ht.hm, ht.xt) != ht.scrc
+
+	switch {
+	case unsaved && (ConflictFail == ht.cp):
+		return false, se.New(&TFileError{Kind: ErrConflict, Filename: fn}, 1)
+
+	case unsaved && (ConflictMerge == ht.cp):
+		ht.scrc = contentSum(hm, xt)
+		ht.hm.union(*hm)
+		ht.xt.union(xt)
+
+	default:
+		*ht.hm, *ht.xt = *hm, *xt
+		ht.scrc = contentSum(hm, xt)
+	}
+	ht.fst = stamp
+	ht.rebindScopes()
+	ht.cc.cl = nil
+	ht.invalidate()
+	ht.clearHistory()
+
+	return true, nil
+} // Reload()
+
+// `SetConflictPolicy()` sets how the list handles changes of its file
+// made by other processes (or other list instances).
+//
+// The policy is used by [TIndex.Store] (and the autosave mode) as
+// well as by [TIndex.Reload]. All accesses of the file are guarded
+// by advisory file locks (on Unix-like systems) which are respected
+// by all instances of this package.
+//
+// Parameters:
+//   - `aPolicy`: The new conflict policy.
+//
+// Returns:
+//   - `*TIndex[ID]`: The list itself, allowing for method chaining.
+func (ht *TIndex[ID]) SetConflictPolicy(aPolicy TConflictPolicy) *TIndex[ID] {
+	if nil != ht.root {
+		ht.root.SetConflictPolicy(aPolicy)
+		return ht
+	}
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+	ht.cp = aPolicy
+
+	return ht
+} // SetConflictPolicy()
+
+// `storeFile()` writes the whole list to the configured file while
+// holding an exclusive file lock, handling external changes of the
+// file according to the list's conflict policy.
+//
+// NOTE: This method expects the caller to hold the list's write lock.
+//
+// Returns:
+//   - `int`: Number of bytes written to storage.
+//   - `error`: `nil` in case of success, or `ErrConflict` or a possible I/O error.
+func (ht *TIndex[ID]) storeFile() (int, error) {
+	if "" == ht.fn {
+		return 0, se.New(ErrEmptyFilename, 1)
+	}
+
+	file, err := openLocked(ht.fn)
+	if nil != err {
+		return 0, se.New(storeError(ht.fn, err), 2)
+	}
+	defer file.Close()
+
+	if ConflictLastWins != ht.cp {
+		current, err := stampFile(file, ht.fst)
+		if nil != err {
+			return 0, se.New(storeError(ht.fn, err), 2)
+		}
+		if (0 < current.size) && current.differs(ht.fst) {
+			if ConflictFail == ht.cp {
+				return 0, se.New(&TFileError{Kind: ErrConflict, Filename: ht.fn}, 1)
+			}
+
+			// merge the file's data into the list
+			hm, xt := newHashMap[ID](), newExtras[ID]()
+			xt.ic = ht.xt.ic
+			reader := io.NewSectionReader(file, 0, current.size)
+			if UseBinaryStorage {
+				err = hm.loadBinary(reader, xt, LoadLenient, nil)
+			} else {
+				err = hm.loadText(reader, xt, LoadLenient, nil)
+			}
+			if nil != err {
+				return 0, se.New(loadError(ht.fn, err), 1)
+			}
+			ht.hm.union(*hm)
+			ht.xt.union(xt)
+			ht.rebindScopes()
+			ht.cc.cl = nil
+			ht.invalidate()
+			ht.clearHistory()
+		}
+	}
+
+	var stamp tFileStamp
+	size, err := ht.hm.storeTo(file, ht.xt, &stamp)
+	if nil != err {
+		return 0, se.New(storeError(ht.fn, err), 1)
+	}
+	ht.synced(stamp)
+
+	return size, nil
+} // storeFile()
+
+// `synced()` records the state of the list's file after reading or
+// writing it.
+//
+// NOTE: This method expects the caller to hold the list's write lock.
+//
+// Parameters:
+//   - `aStamp`: The file's state.
+func (ht *TIndex[ID]) synced(aStamp tFileStamp) {
+	ht.fst = aStamp
+	ht.scrc = contentSum(ht.hm, ht.xt)
+} // synced()
+
+/* EoF */
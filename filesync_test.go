@@ -0,0 +1,191 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+// `openPair()` returns two lists sharing the same file.
+func openPair(t *testing.T, aPolicy TConflictPolicy) (*THashTags, *THashTags) {
+	t.Helper()
+	fn := filepath.Join(t.TempDir(), "shared.db")
+	a, _ := New(fn)
+	a.SetConflictPolicy(aPolicy)
+	a.HashAdd("#go", 1)
+	if _, err := a.Store(); nil != err {
+		t.Fatalf("THashTags.Store() error = %v", err)
+	}
+	b, err := New(fn)
+	if nil != err {
+		t.Fatalf("New() error = %v", err)
+	}
+	b.SetConflictPolicy(aPolicy)
+
+	return a, b
+} // openPair()
+
+func Test_THashTags_Modified(t *testing.T) {
+	a, b := openPair(t, ConflictLastWins)
+	if got, err := a.Modified(); got || (nil != err) {
+		t.Errorf("THashTags.Modified() = %v, %v, want false", got, err)
+	}
+
+	b.HashAdd("#rust", 2)
+	_, _ = b.Store()
+	if got, err := a.Modified(); !got || (nil != err) {
+		t.Errorf("THashTags.Modified() = %v, %v, want true", got, err)
+	}
+	if got, _ := b.Modified(); got {
+		t.Error("THashTags.Modified() = true for the writer")
+	}
+
+	got, err := a.Reload()
+	if !got || (nil != err) || (1 != a.HashLen("#rust")) {
+		t.Errorf("THashTags.Reload() = %v, %v", got, err)
+	}
+	if got, _ = a.Reload(); got {
+		t.Error("THashTags.Reload() = true for an unchanged file")
+	}
+} // Test_THashTags_Modified()
+
+func Test_THashTags_Store_conflict(t *testing.T) {
+	saveBinary := UseBinaryStorage
+	defer func() {
+		UseBinaryStorage = saveBinary
+	}()
+
+	for _, binary := range []bool{true, false} {
+		UseBinaryStorage = binary
+
+		// the last writer overwrites the other's changes
+		a, b := openPair(t, ConflictLastWins)
+		a.HashAdd("#a", 1)
+		b.HashAdd("#b", 2)
+		_, _ = a.Store()
+		if _, err := b.Store(); nil != err {
+			t.Errorf("THashTags.Store() error = %v", err)
+		}
+		_, _ = a.Load()
+		if (-1 != a.HashLen("#a")) || (1 != a.HashLen("#b")) {
+			t.Errorf("ConflictLastWins: %v", a.String())
+		}
+
+		// the other's changes are merged
+		a, b = openPair(t, ConflictMerge)
+		a.HashAdd("#a", 1)
+		b.HashAdd("#b", 2)
+		_, _ = a.Store()
+		if _, err := b.Store(); nil != err {
+			t.Errorf("THashTags.Store() error = %v", err)
+		}
+		if (1 != b.HashLen("#a")) || (1 != b.HashLen("#b")) {
+			t.Errorf("ConflictMerge: %v", b.String())
+		}
+		_, _ = a.Load()
+		if got := a.HashList("#go"); !slices.Equal(got, []int64{1}) || (1 != a.HashLen("#b")) {
+			t.Errorf("ConflictMerge: %v", a.String())
+		}
+
+		// the other's changes are protected
+		a, b = openPair(t, ConflictFail)
+		a.HashAdd("#a", 1)
+		b.HashAdd("#b", 2)
+		_, _ = a.Store()
+		if _, err := b.Store(); !errors.Is(err, ErrConflict) {
+			t.Errorf("THashTags.Store() error = %v, want %v", err, ErrConflict)
+		}
+		if _, err := b.Reload(); !errors.Is(err, ErrConflict) {
+			t.Errorf("THashTags.Reload() error = %v, want %v", err, ErrConflict)
+		}
+		if _, err := b.Load(); nil != err {
+			t.Errorf("THashTags.Load() error = %v", err)
+		}
+		b.HashAdd("#b", 2)
+		if _, err := b.Store(); nil != err {
+			t.Errorf("THashTags.Store() error = %v", err)
+		}
+	}
+} // Test_THashTags_Store_conflict()
+
+func Test_THashTags_Reload_merge(t *testing.T) {
+	a, b := openPair(t, ConflictMerge)
+	a.HashAdd("#a", 1)
+	_, _ = a.Store()
+	b.MentionAdd("@bob", 3)
+
+	got, err := b.Reload()
+	if !got || (nil != err) {
+		t.Errorf("THashTags.Reload() = %v, %v", got, err)
+	}
+	if (1 != b.HashLen("#a")) || (1 != b.MentionLen("@bob")) {
+		t.Errorf("THashTags.Reload() = %v", b.String())
+	}
+
+	// the merged list still has unsaved changes
+	if _, err = b.Store(); nil != err {
+		t.Errorf("THashTags.Store() error = %v", err)
+	}
+	if got, _ = a.Reload(); !got || (1 != a.MentionLen("@bob")) {
+		t.Errorf("THashTags.Reload() = %v", a.String())
+	}
+} // Test_THashTags_Reload_merge()
+
+func Test_THashTags_Reload_fail(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(aList *THashTags)
+		wantErr error
+	}{
+		{"unchanged", func(aList *THashTags) {}, nil},
+		{"hash", func(aList *THashTags) { aList.HashAdd("#go", 2) }, ErrConflict},
+		{"description", func(aList *THashTags) { aList.SetDescription("#go", "a language") }, ErrConflict},
+		{"display", func(aList *THashTags) { aList.SetDisplayName("#go", "#GO") }, ErrConflict},
+		{"pinned", func(aList *THashTags) { aList.SetPinned("@go", true) }, ErrConflict},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := openPair(t, ConflictFail)
+			// tags differing only by their mark
+			a.MentionAdd("@go", 1)
+			_, _ = a.Store()
+			if _, err := b.Load(); nil != err {
+				t.Fatalf("THashTags.Load() error = %v", err)
+			}
+
+			tt.modify(b)
+			a.HashAdd("#a", 1)
+			_, _ = a.Store()
+			if _, err := b.Reload(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("THashTags.Reload() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+} // Test_THashTags_Reload_fail()
+
+func Test_contentSum(t *testing.T) {
+	ht := setList(map[string][]int64{"#go": {1}, "@go": {2}, "#c": {3}, "@c": {1}})
+	want := contentSum(ht.hm, ht.xt)
+	for range 100 {
+		if got := contentSum(ht.hm, ht.xt); want != got {
+			t.Fatalf("contentSum() = %d, want %d", got, want)
+		}
+	}
+
+	ht.SetDescription("#go", "a language")
+	if got := contentSum(ht.hm, ht.xt); want == got {
+		t.Error("contentSum() ignores the tags' metadata")
+	}
+} // Test_contentSum()
+
+/* EoF */
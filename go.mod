@@ -1,9 +1,10 @@
 module github.com/mwat56/hashtags
 
-go 1.22
+go 1.23
 
-toolchain go1.22.1
-
-require github.com/mwat56/sourceerror v0.3.0
+require (
+	github.com/mwat56/sourceerror v0.3.0
+	golang.org/x/text v0.22.0
+)
 
 replace github.com/mwat56/sourceerror => ../sourceerror
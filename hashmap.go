@@ -9,16 +9,18 @@ package hashtags
 import (
 	"bufio"
 	"bytes"
+	"cmp"
+	"context"
 	"encoding/gob"
 	"errors"
 	"fmt"
+	"hash"
 	"hash/crc32"
 	"io"
 	"os"
 	"regexp"
 	"slices"
 	"sort"
-	"strconv"
 	"strings"
 
 	se "github.com/mwat56/sourceerror"
@@ -29,7 +31,14 @@ import (
 type (
 	// `tHashMap` is a map indexed by `#hashtags`/`@mentions` pointing
 	// to a `tSourceList` instance.
-	tHashMap map[string]*tSourceList
+	tHashMap[ID cmp.Ordered] map[string]*tSourceList[ID]
+
+	// `tCtxReader` is a file reader failing once its context is done.
+	tCtxReader struct {
+		ctx context.Context // the context to watch
+		rs  io.ReadSeeker   // the actual reader
+		sum hash.Hash32     // optional checksum of the data read
+	}
 )
 
 const (
@@ -56,13 +65,56 @@ var (
 // to avoid repeated resizing and optimise memory usage.
 //
 // Returns:
-//   - `*tHashMap`: The new `tHashMap` instance.
-func newHashMap() *tHashMap {
-	hm := make(tHashMap, defaultListSize)
+//   - `*tHashMap[ID]`: The new `tHashMap` instance.
+func newHashMap[ID cmp.Ordered]() *tHashMap[ID] {
+	hm := make(tHashMap[ID], defaultListSize)
 
 	return &hm
 } // newHashMap()
 
+// -------------------------------------------------------------------------
+// methods of `tCtxReader`:
+
+// `Read()` implements the `io.Reader` interface.
+//
+// Parameters:
+//   - `aBuffer`: The buffer to fill.
+//
+// Returns:
+//   - `int`: The number of bytes read.
+//   - `error`: A possible I/O error or the context's error.
+func (cr *tCtxReader) Read(aBuffer []byte) (int, error) {
+	if err := cr.ctx.Err(); nil != err {
+		return 0, err
+	}
+
+	n, err := cr.rs.Read(aBuffer)
+	if nil != cr.sum {
+		cr.sum.Write(aBuffer[:n])
+	}
+
+	return n, err
+} // Read()
+
+// `Seek()` implements the `io.Seeker` interface.
+//
+// Seeking to the start restarts the checksum of the data read.
+//
+// Parameters:
+//   - `aOffset`: The offset to seek to.
+//   - `aWhence`: The position `aOffset` is relative to.
+//
+// Returns:
+//   - `int64`: The new offset.
+//   - `error`: A possible I/O error.
+func (cr *tCtxReader) Seek(aOffset int64, aWhence int) (int64, error) {
+	if (nil != cr.sum) && (0 == aOffset) && (io.SeekStart == aWhence) {
+		cr.sum.Reset()
+	}
+
+	return cr.rs.Seek(aOffset, aWhence)
+} // Seek()
+
 // --------------------------------------------------------------------------
 // helper for sorting the hash strings; used by `keys()`:
 
@@ -79,28 +131,31 @@ func newHashMap() *tHashMap {
 //
 // The function first checks if the first character of `a` and `b` is a hash
 // or mention mark. If so, it removes the leading character from both strings.
+// Strings differing only by their leading mark (e.g. `#go` and `@go`) are
+// ordered by the mark, so that the order is always the same.
 //
 // Returns:
 //   - `int`: The result of the comparison the two strings as described above.
 func cmp4sort(a, b string) int {
-	switch a[0] {
+	ta, tb := a, b
+	switch ta[0] {
 	case MarkHash, MarkMention:
-		a = a[1:]
+		ta = ta[1:]
 	}
 
-	switch b[0] {
+	switch tb[0] {
 	case MarkHash, MarkMention:
-		b = b[1:]
+		tb = tb[1:]
 	}
 
-	if a < b {
+	if ta < tb {
 		return -1
 	}
-	if a > b {
+	if ta > tb {
 		return 1
 	}
 
-	return 0
+	return strings.Compare(a, b)
 } // cmp4sort()
 
 // -------------------------------------------------------------------------
@@ -110,7 +165,7 @@ func cmp4sort(a, b string) int {
 //
 // Returns:
 //   - `uint32`: The computed checksum.
-func (hm *tHashMap) checksum() uint32 {
+func (hm *tHashMap[ID]) checksum() uint32 {
 	// We use `String()` because it sorts internally
 	// thus generating reproducible results
 	return crc32.Update(0, gCRCtable, []byte(hm.String()))
@@ -120,15 +175,15 @@ func (hm *tHashMap) checksum() uint32 {
 // all `#hashtags` and `@mentions` are deleted.
 //
 // Returns:
-//   - `*tHashMap`: The cleared hash map.
-func (hm *tHashMap) clear() *tHashMap {
+//   - `*tHashMap[ID]`: The cleared hash map.
+func (hm *tHashMap[ID]) clear() *tHashMap[ID] {
 	if 0 == len(*hm) {
 		return hm
 	}
 
 	var (
 		hash string
-		sl   *tSourceList
+		sl   *tSourceList[ID]
 	)
 
 	for hash, sl = range *hm {
@@ -148,7 +203,7 @@ func (hm *tHashMap) clear() *tHashMap {
 //
 // Returns:
 //   - `int`: The number of `#hashtags` and `@mentions`.
-func (hm *tHashMap) count(aDelim byte) int {
+func (hm *tHashMap[ID]) count(aDelim byte) int {
 	if 0 == len(*hm) {
 		return 0
 	}
@@ -171,19 +226,19 @@ func (hm *tHashMap) count(aDelim byte) int {
 //
 // Returns:
 //   - `TCountList`: A list of `#hashtags` and `@mentions` with their respective count of associated IDs.
-func (hm *tHashMap) countedList() TCountList {
+func (hm *tHashMap[ID]) countedList() TCountList {
 	if 0 == len(*hm) {
 		return nil
 	}
 
 	var (
 		tag string
-		sl  *tSourceList
+		sl  *tSourceList[ID]
 	)
 
 	result := TCountList{}
 	for tag, sl = range *hm {
-		result.Insert(TCountItem{len(*sl), tag})
+		result.Insert(TCountItem{Count: len(*sl), Tag: tag})
 	}
 
 	return result
@@ -197,15 +252,15 @@ func (hm *tHashMap) countedList() TCountList {
 //
 // Returns:
 //   - `bool`: Whether the hash maps are equal.
-func (hm *tHashMap) equals(aMap tHashMap) bool {
+func (hm *tHashMap[ID]) equals(aMap tHashMap[ID]) bool {
 	if len(*hm) != len(aMap) {
 		return false
 	}
 
 	var (
 		tag   string
-		other *tSourceList
-		sl    *tSourceList
+		other *tSourceList[ID]
+		sl    *tSourceList[ID]
 		ok    bool
 	)
 
@@ -221,6 +276,46 @@ func (hm *tHashMap) equals(aMap tHashMap) bool {
 	return true
 } // equals()
 
+// `filter()` deletes all `#hashtags` and `@mentions` for which
+// `aKeep` returns `false`.
+//
+// Parameters:
+//   - `aKeep`: The function deciding whether to keep a tag.
+//
+// Returns:
+//   - `int`: The number of deleted tags.
+func (hm *tHashMap[ID]) filter(aKeep func(aTag string) bool) int {
+	if (0 == len(*hm)) || (nil == aKeep) {
+		return 0
+	}
+
+	var (
+		result int
+		tag    string
+	)
+	for tag = range *hm {
+		if !aKeep(tag) {
+			delete(*hm, tag)
+			result++
+		}
+	}
+
+	return result
+} // filter()
+
+// `has()` reports whether `aKey` is present in the hash map.
+//
+// Parameters:
+//   - `aKey`: The normalised tag to lookup.
+//
+// Returns:
+//   - `bool`: `true` if `aKey` is present, or `false` otherwise.
+func (hm *tHashMap[ID]) has(aKey string) bool {
+	_, ok := (*hm)[aKey]
+
+	return ok
+} // has()
+
 // `idList()` returns a list of `#hashtags` and `@mentions` associated
 // with `aID`.
 //
@@ -229,11 +324,11 @@ func (hm *tHashMap) equals(aMap tHashMap) bool {
 //
 // Returns:
 //   - `[]string`: List of `#hashtags` and `@mentions` associated with `aID`.
-func (hm *tHashMap) idList(aID int64) []string {
+func (hm *tHashMap[ID]) idList(aID ID) []string {
 	var (
 		hash   string
 		result []string
-		sl     *tSourceList
+		sl     *tSourceList[ID]
 	)
 	hLen := len(*hm)
 	if 0 == hLen {
@@ -271,9 +366,9 @@ func (hm *tHashMap) idList(aID int64) []string {
 //
 // Returns:
 //   - `int: The number of references of `aTag`, or `-1` if not found.
-func (hm *tHashMap) idxLen(aDelim byte, aTag string) int {
+func (hm *tHashMap[ID]) idxLen(aDelim byte, aTag string) int {
 	// prepare for case-insensitive search:
-	if aTag = strings.ToLower(aTag); "" == aTag {
+	if aTag = normalise(aTag); "" == aTag {
 		return -1
 	}
 
@@ -296,9 +391,9 @@ func (hm *tHashMap) idxLen(aDelim byte, aTag string) int {
 //
 // Returns:
 //   - `bool`: `true` if `aID` was added, or `false` otherwise.
-func (hm *tHashMap) insert(aTag string, aID int64) bool {
+func (hm *tHashMap[ID]) insert(aTag string, aID ID) bool {
 	// prepare for case-insensitive search:
-	if aTag = strings.ToLower(aTag); "" == aTag {
+	if aTag = normalise(aTag); "" == aTag {
 		return false
 	}
 
@@ -307,7 +402,7 @@ func (hm *tHashMap) insert(aTag string, aID int64) bool {
 			return true
 		}
 	} else {
-		sl := newSourceList()
+		sl := newSourceList[ID]()
 		if sl.insert(aID) {
 			(*hm)[aTag] = sl // assign the ID list to the hash
 			return true
@@ -317,6 +412,36 @@ func (hm *tHashMap) insert(aTag string, aID int64) bool {
 	return false
 } // insert()
 
+// `intersect()` removes all IDs from the lists of this hash map which
+// are not present in the respective lists of `aOther`.
+//
+// Tags becoming empty are deleted.
+//
+// Parameters:
+//   - `aOther`: The hash map whose IDs are to be kept.
+//
+// Returns:
+//   - `bool`: `true` if at least one ID was removed, or `false` otherwise.
+func (hm *tHashMap[ID]) intersect(aOther tHashMap[ID]) bool {
+	var result bool
+	for key, sl := range *hm {
+		ol, ok := aOther[key]
+		if !ok || (nil == ol) {
+			delete(*hm, key)
+			result = true
+			continue
+		}
+		if sl.intersect(*ol) {
+			result = true
+			if 0 == len(*sl) {
+				delete(*hm, key)
+			}
+		}
+	}
+
+	return result
+} // intersect()
+
 // `keys()` returns a slice of all keys in the hash map.
 // If the hash map is empty, it returns an empty slice.
 //
@@ -324,7 +449,7 @@ func (hm *tHashMap) insert(aTag string, aID int64) bool {
 //
 // Returns:
 //   - `[]string`: A sorted slice of all keys in the hash map.
-func (hm *tHashMap) keys() []string {
+func (hm *tHashMap[ID]) keys() []string {
 	hLen := len(*hm)
 	if 0 == hLen {
 		return []string{}
@@ -350,11 +475,11 @@ func (hm *tHashMap) keys() []string {
 //
 // Returns:
 //   - `int`: The total length of all `#hashtags` and `@mentions` lists.
-func (hm *tHashMap) lenTotal() (rLen int) {
+func (hm *tHashMap[ID]) lenTotal() (rLen int) {
 	if rLen = len(*hm); 0 == rLen {
 		return
 	}
-	var sl *tSourceList
+	var sl *tSourceList[ID]
 
 	for _, sl = range *hm {
 		rLen += len(*sl)
@@ -363,7 +488,8 @@ func (hm *tHashMap) lenTotal() (rLen int) {
 	return
 } // lenTotal()
 
-// `list()` returns a list of object IDs associated with `aTag`.
+// `list()` returns a copy of the list of object IDs associated
+// with `aTag`.
 //
 // If `aTag` is empty it is silently ignored (i.e. this method
 // does nothing), returning an empty slice.
@@ -373,25 +499,9 @@ func (hm *tHashMap) lenTotal() (rLen int) {
 //   - `aTag`: The hash to lookup.
 //
 // Returns:
-//   - `[]int64`: The number of references of `aTag`.
-func (hm *tHashMap) list(aDelim byte, aTag string) (rList []int64) {
-	// prepare for case-insensitive search:
-	if aTag = strings.ToLower(aTag); "" == aTag {
-		return
-	}
-	if 0 == len(*hm) {
-		return
-	}
-
-	if aTag[0] != aDelim {
-		aTag = string(aDelim) + aTag
-	}
-
-	if sl, ok := (*hm)[aTag]; ok {
-		rList = []int64(*sl)
-	}
-
-	return
+//   - `[]ID`: The IDs referencing `aTag`.
+func (hm *tHashMap[ID]) list(aDelim byte, aTag string) []ID {
+	return slices.Clone(hm.view(aDelim, aTag))
 } // list()
 
 // `load()` reads the configured file returning the data structure
@@ -404,9 +514,68 @@ func (hm *tHashMap) list(aDelim byte, aTag string) (rList []int64) {
 //   - `aFilename`: Name of the file to load.
 //
 // Returns:
-//   - `*tHashMap`: The loaded hash map.
+//   - `*tHashMap[ID]`: The loaded hash map.
 //   - `error`: A possible I/O error.
-func (hm *tHashMap) load(aFilename string) (*tHashMap, error) {
+func (hm *tHashMap[ID]) load(aFilename string) (*tHashMap[ID], error) {
+	return hm.loadWith(aFilename, nil)
+} // load()
+
+// `loadWith()` reads the configured file returning the data structure
+// read from the file and a possible error condition.
+//
+// If `aExtras` is not `nil` it gets cleared and filled with the
+// optional data read from the file.
+//
+// NOTE: An empty filename or a non-existing hash file are not
+// considered an error.
+//
+// Parameters:
+//   - `aFilename`: Name of the file to load.
+//   - `aExtras`: Optional container for the additional data.
+//
+// Returns:
+//   - `*tHashMap[ID]`: The loaded hash map.
+//   - `error`: A possible I/O error.
+func (hm *tHashMap[ID]) loadWith(aFilename string, aExtras *tExtras[ID]) (*tHashMap[ID], error) {
+	return hm.loadWithContext(context.Background(), aFilename, aExtras)
+} // loadWith()
+
+// `loadWithContext()` works like `loadWith()` but stops reading
+// the file once `aCtx` is done.
+//
+// NOTE: If reading is stopped the hash map and `aExtras` hold only
+// part of the file's data.
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//   - `aFilename`: Name of the file to load.
+//   - `aExtras`: Optional container for the additional data.
+//
+// Returns:
+//   - `*tHashMap[ID]`: The loaded hash map.
+//   - `error`: A possible [TFileError] or the context's error.
+func (hm *tHashMap[ID]) loadWithContext(aCtx context.Context, aFilename string, aExtras *tExtras[ID]) (*tHashMap[ID], error) {
+	return hm.loadFile(aCtx, aFilename, aExtras, LoadLenient, nil, nil)
+} // loadWithContext()
+
+// `loadFile()` works like `loadWithContext()` but reads the file
+// in the given mode.
+//
+// The file is read while holding a shared (advisory) lock, i.e. no
+// other process can write the file meanwhile.
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//   - `aFilename`: Name of the file to load.
+//   - `aExtras`: Optional container for the additional data.
+//   - `aMode`: How to handle malformed data.
+//   - `aReport`: Optional report of the malformed data found.
+//   - `aStamp`: Optional state of the file read.
+//
+// Returns:
+//   - `*tHashMap[ID]`: The loaded hash map.
+//   - `error`: A possible [TFileError] or the context's error.
+func (hm *tHashMap[ID]) loadFile(aCtx context.Context, aFilename string, aExtras *tExtras[ID], aMode TLoadMode, aReport *TLoadReport, aStamp *tFileStamp) (*tHashMap[ID], error) {
 	if aFilename = strings.TrimSpace(aFilename); "" == aFilename {
 		return hm, nil
 	}
@@ -419,67 +588,129 @@ func (hm *tHashMap) load(aFilename string) (*tHashMap, error) {
 	file, err = os.OpenFile(aFilename, os.O_RDONLY, 0) //#nosec G304
 	if nil != err {
 		if os.IsNotExist(err) {
+			if nil != aStamp {
+				*aStamp = tFileStamp{}
+			}
 			return hm, nil
 		}
-		return nil, se.New(err, 5)
+		return nil, se.New(loadError(aFilename, err), 5)
 	}
 	defer file.Close()
+	if err = lockFile(file, false); nil != err {
+		return nil, se.New(loadError(aFilename, err), 1)
+	}
+	aExtras.clear()
 
+	reader := &tCtxReader{ctx: aCtx, rs: file}
+	if nil != aStamp {
+		reader.sum = crc32.New(gCRCtable)
+	}
 	if UseBinaryStorage {
-		return hm, hm.loadBinary(file)
+		err = hm.loadBinary(reader, aExtras, aMode, aReport)
+	} else {
+		err = hm.loadText(reader, aExtras, aMode, aReport)
+	}
+	if nil != aReport {
+		aReport.Filename, aReport.Tags = aFilename, len(*hm)
+		for _, issue := range aReport.Issues {
+			issue.Filename = aFilename
+		}
+	}
+	if nil != err {
+		return hm, se.New(loadError(aFilename, err), 1)
+	}
+	if nil != aStamp {
+		// include data not needed by the decoder
+		if _, err = io.Copy(io.Discard, reader); nil != err {
+			return hm, se.New(loadError(aFilename, err), 1)
+		}
+		if *aStamp, err = stampOf(file, reader.sum.Sum32()); nil != err {
+			return hm, se.New(loadError(aFilename, err), 1)
+		}
 	}
 
-	return hm, hm.loadText(file)
-} // load()
+	return hm, nil
+} // loadFile()
 
 // `loadBinary()` reads a file written by `store()` returning the modified
 // list and a possible error.
 //
 // NOTE: This method updates the list in place.
 //
+// In `LoadRepair` mode a hash map followed by damaged additional
+// data is kept while the additional data are dropped.
+//
 // Parameters:
 //   - `aFile`: The file to read from.
+//   - `aExtras`: Optional container for the additional data.
+//   - `aMode`: How to handle malformed data.
+//   - `aReport`: Optional report of the malformed data found.
 //
 // Returns:
-//   - `error`: A possible I/O error.
-func (hm *tHashMap) loadBinary(aFile *os.File) error {
-	iMap, iErr := loadBinaryInts(aFile)
+//   - `error`: `ErrCorruptFile`, `ErrFormatMismatch`, or the context's error.
+func (hm *tHashMap[ID]) loadBinary(aFile io.ReadSeeker, aExtras *tExtras[ID], aMode TLoadMode, aReport *TLoadReport) error {
+	iMap, iErr := loadBinaryIDs(aFile, aExtras)
 	if nil != iErr {
-		if sMap, err := loadBinaryStrings(aFile); nil == err {
+		if isCancelled(iErr) {
+			return iErr
+		}
+		if sMap, err := loadBinaryStrings(aFile, aExtras.codec()); nil == err {
 			*hm = *sMap
+			hm.renormaliseAll(aExtras)
 			return nil
 		}
-		return iErr
+		if (LoadRepair == aMode) && (nil != aExtras) {
+			if iMap, err := loadBinaryIDs[ID](aFile, nil); nil == err {
+				aExtras.clear()
+				*hm = *iMap
+				hm.renormaliseAll(aExtras)
+				if nil != aReport {
+					aReport.Issues = append(aReport.Issues,
+						&TFileError{Kind: ErrCorruptFile, Err: iErr})
+				}
+				return nil
+			}
+		}
+		if isTextFile(aFile) {
+			return &TFileError{Kind: ErrFormatMismatch}
+		}
+		return &TFileError{Kind: ErrCorruptFile, Err: iErr}
 	}
 	*hm = *iMap
+	// the file may have been written using another normalisation
+	hm.renormaliseAll(aExtras)
 
 	return nil
 } // loadBinary()
 
-// `loadBinaryInts()` reads a binary encoded integer map from `aFile`
+// `loadBinaryIDs()` reads a binary encoded map of IDs from `aFile`
 // and converts it into a `tHashMap`.
 //
+// If `aExtras` is not `nil` the optional data following the hash map
+// are decoded as well.
+//
 // Parameters:
 //   - `aFile`: The file handle to read from.
+//   - `aExtras`: Optional container for the additional data.
 //
 // Returns:
-//   - `*tHashMap`: The decoded and converted hash map.
+//   - `*tHashMap[ID]`: The decoded and converted hash map.
 //   - `error`: A possible decoding or conversion error.
-func loadBinaryInts(aFile *os.File) (*tHashMap, error) {
-	var decodedMap tHashMap
+func loadBinaryIDs[ID cmp.Ordered](aFile io.ReadSeeker, aExtras *tExtras[ID]) (*tHashMap[ID], error) {
+	var decodedMap tHashMap[ID]
 
 	_, _ = aFile.Seek(0, io.SeekStart)
 	decoder := gob.NewDecoder(aFile)
 
 	if err := decoder.Decode(&decodedMap); nil != err {
-		// `decoder.Decode()` returns `io.EOF` if the input
-		// is at EOF which we do not consider an error here.
-		if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
-			return nil, se.New(err, 4)
-		}
+		return nil, err
+	}
 
-		// some other error occurred
-		return nil, se.New(err, 8)
+	if nil != aExtras {
+		// Files written without additional data simply end here.
+		if err := decoder.Decode(aExtras); (nil != err) && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
 	}
 
 	// Only sort if needed
@@ -488,44 +719,38 @@ func loadBinaryInts(aFile *os.File) (*tHashMap, error) {
 	}
 
 	return &decodedMap, nil
-} // loadBinaryInts()
+} // loadBinaryIDs()
 
 // `loadBinaryStrings()` reads a binary encoded string map from `aFile`
 // and converts it into a `tHashMap`.
 //
 // Parameters:
 //   - `aFile`: The file handle to read from.
+//   - `aCodec`: The codec to decode the IDs with.
 //
 // Returns:
-//   - `*tHashMap`: The decoded and converted hash map.
+//   - `*tHashMap[ID]`: The decoded and converted hash map.
 //   - `error`: A possible decoding or conversion error.
-func loadBinaryStrings(aFile *os.File) (*tHashMap, error) {
+func loadBinaryStrings[ID cmp.Ordered](aFile io.ReadSeeker, aCodec TIDCodec[ID]) (*tHashMap[ID], error) {
 	var decodedMap map[string][]string
 
 	_, _ = aFile.Seek(0, io.SeekStart) //#nosec G104
 	decoder := gob.NewDecoder(aFile)
 	if err := decoder.Decode(&decodedMap); nil != err {
-		// `decoder.Decode()` returns `io.EOF` if the input
-		// is at EOF which we do not consider an error here.
-		if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
-			return nil, se.New(err, 4)
-		}
-
-		// some other error occurred
-		return nil, se.New(err, 8)
+		return nil, err
 	}
 
-	hm := newHashMap()
+	hm := newHashMap[ID]()
 	var (
 		key  string
 		sArr []string
-		i64  int64
+		id   ID
 		err  error
 	)
 	for key, sArr = range decodedMap {
 		for _, str := range sArr {
-			if i64, err = strconv.ParseInt(str, 16, 64); nil == err {
-				hm.insert(key, i64)
+			if id, err = aCodec.DecodeID(str); nil == err {
+				hm.insert(key, id)
 			}
 		}
 	}
@@ -536,27 +761,55 @@ func loadBinaryStrings(aFile *os.File) (*tHashMap, error) {
 // `loadText()` parses a text file written by `store()` returning
 // a possible error.
 //
-// This method reads one line of the file at a time.
+// This method reads one line of the file at a time. In `LoadLenient`
+// mode malformed lines are silently ignored; in the other modes they
+// are added to `aReport` and the IDs following a malformed tag header
+// are dropped instead of being added to the preceding tag.
 //
 // NOTE: This method updates the list in place.
 //
 // Parameters:
 //   - `aFile`: The file to read from.
+//   - `aExtras`: Optional container for the additional data.
+//   - `aMode`: How to handle malformed lines.
+//   - `aReport`: Optional report of the malformed lines found.
 //
 // Returns:
-//   - `error`: A possible I/O error.
-func (hm *tHashMap) loadText(aFile *os.File) error {
+//   - `error`: `ErrCorruptFile`, `ErrFormatMismatch`, or the context's error.
+func (hm *tHashMap[ID]) loadText(aFile io.ReadSeeker, aExtras *tExtras[ID], aMode TLoadMode, aReport *TLoadReport) error {
 	var (
-		err     error
-		hash    string
-		i64     int64
-		line    string
-		matches []string
+		end, next, offset int64 // byte offsets of the current line
+		err               error
+		hash              string
+		issues            []*TFileError
+		line              string
+		lineNo            int
+		scope             *tScope[ID]
+		scopeHash         string
 	)
 	hm.clear()
+	strict := (LoadLenient != aMode)
+	if strict && (nil == aExtras) {
+		aExtras = newExtras[ID]() // to tell extra data from garbage
+	}
 
 	scanner := bufio.NewScanner(aFile)
+	scanner.Split(func(aData []byte, aAtEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(aData, aAtEOF)
+		next += int64(advance)
+		return advance, token, err
+	})
 	for scanner.Scan() {
+		lineNo++
+		offset, end = end, next
+		if isBinaryLine(scanner.Bytes()) {
+			if !strict {
+				return &TFileError{Kind: ErrFormatMismatch, Line: lineNo, Offset: offset}
+			}
+			issues = append(issues,
+				&TFileError{Kind: ErrFormatMismatch, Line: lineNo, Offset: offset})
+			continue
+		}
 		if line = scanner.Text(); 0 == len(line) {
 			continue
 		}
@@ -568,22 +821,105 @@ func (hm *tHashMap) loadText(aFile *os.File) error {
 			}
 		}
 
-		// Fast path for hash headers: check first character before regex
-		if ('[' == line[0]) && (']' == line[len(line)-1]) {
-			if matches = htHashHeadRE.FindStringSubmatch(line); nil != matches {
-				hash = strings.ToLower(matches[1])
+		switch line[0] {
+		case textScopeMark:
+			// start of a scope's section
+			if '}' != line[len(line)-1] {
+				scope, err = nil, errBadScope
+			} else if nil != aExtras {
+				scope, scopeHash = aExtras.scope(line[1:len(line)-1]), ""
 			}
-		} else if i64, err = strconv.ParseInt(line, 16, 64); nil == err {
-			hm.insert(hash, i64)
+
+		case textScopeLine:
+			// a line of the current scope's section
+			if nil == scope {
+				err = errBadScope
+			} else if err = scope.Map.textLine(strings.TrimSpace(line[1:]), &scopeHash, scope.Extras); errors.Is(err, errBadHeader) {
+				scopeHash = ""
+			}
+
+		default:
+			if err = hm.textLine(line, &hash, aExtras); errors.Is(err, errBadHeader) && strict {
+				hash = ""
+			}
+		}
+		if (nil != err) && strict {
+			issues = append(issues,
+				&TFileError{Kind: ErrCorruptFile, Err: err, Line: lineNo, Offset: offset})
 		}
+		err = nil
 	}
 	if err = scanner.Err(); nil != err {
-		return se.New(err, 1)
+		if isCancelled(err) {
+			return err
+		}
+		fe := &TFileError{Kind: ErrCorruptFile, Err: err, Line: lineNo + 1, Offset: end}
+		if !strict {
+			return fe
+		}
+		issues = append(issues, fe)
+	}
+
+	if nil != aReport {
+		aReport.Issues, aReport.Lines = issues, lineNo
+	}
+	if (LoadStrict == aMode) && (0 < len(issues)) {
+		return &TFileError{
+			Kind:   issues[0].Kind,
+			Err:    fmt.Errorf("%d malformed line(s)", len(issues)),
+			Line:   issues[0].Line,
+			Offset: issues[0].Offset,
+		}
 	}
 
 	return nil
 } // loadText()
 
+// `mergeTags()` moves the IDs of all `aSources` lists into the list
+// of `aTarget`, deleting the source lists afterwards.
+//
+// The tags are expected to include their respective leading mark
+// (i.e. either '#' or '@'). Source tags not found in the map as well
+// as a source equal to `aTarget` are silently ignored.
+//
+// Parameters:
+//   - `aSources`: The tags whose IDs are to be merged.
+//   - `aTarget`: The tag to receive the merged IDs.
+//
+// Returns:
+//   - `bool`: `true` if at least one source was merged, or `false` otherwise.
+func (hm *tHashMap[ID]) mergeTags(aSources []string, aTarget string) bool {
+	// prepare for case-insensitive search:
+	if aTarget = normalise(aTarget); ("" == aTarget) || (0 == len(*hm)) {
+		return false
+	}
+
+	var (
+		ok, result bool
+		sl, tl     *tSourceList[ID]
+		source     string
+	)
+	for _, source = range aSources {
+		if source = normalise(source); ("" == source) || (source == aTarget) {
+			continue
+		}
+		if sl, ok = (*hm)[source]; !ok {
+			continue
+		}
+
+		if tl, ok = (*hm)[aTarget]; ok {
+			tl.merge(*sl)
+		} else {
+			// simply move the whole list to the new key
+			(*hm)[aTarget] = sl
+		}
+		delete(*hm, source)
+		result = true
+	}
+
+	return result
+} // mergeTags()
+
 // `removeID()` deletes all `#hashtags` and `@mentions` associated with `aID`.
 //
 // Parameters:
@@ -591,14 +927,14 @@ func (hm *tHashMap) loadText(aFile *os.File) error {
 //
 // Returns:
 //   - `bool`: `true` if `aID` was removed, or `false` otherwise.
-func (hm *tHashMap) removeID(aID int64) bool {
+func (hm *tHashMap[ID]) removeID(aID ID) bool {
 	if 0 == len(*hm) {
 		return false
 	}
 
 	var (
 		tag    string
-		sl     *tSourceList
+		sl     *tSourceList[ID]
 		result bool
 	)
 	for tag, sl = range *hm {
@@ -623,9 +959,9 @@ func (hm *tHashMap) removeID(aID int64) bool {
 //
 // Returns:
 //   - `bool`: `true` if `aID` was removed, or `false` otherwise.
-func (hm *tHashMap) removeHM(aDelim byte, aTag string, aID int64) bool {
+func (hm *tHashMap[ID]) removeHM(aDelim byte, aTag string, aID ID) bool {
 	// prepare for case-insensitive search:
-	if aTag = strings.ToLower(aTag); "" == aTag {
+	if aTag = normalise(aTag); "" == aTag {
 		return false
 	}
 
@@ -657,13 +993,13 @@ func (hm *tHashMap) removeHM(aDelim byte, aTag string, aID int64) bool {
 //
 // Returns:
 //   - `bool`: `true` if the the renaming was successful, or `false` otherwise.
-func (hm *tHashMap) renameID(aOldID, aNewID int64) bool {
+func (hm *tHashMap[ID]) renameID(aOldID, aNewID ID) bool {
 	if (aOldID == aNewID) || (0 == len(*hm)) {
 		return false
 	}
 
 	var (
-		sl         *tSourceList
+		sl         *tSourceList[ID]
 		ok, result bool
 	)
 	for _, sl = range *hm {
@@ -675,6 +1011,74 @@ func (hm *tHashMap) renameID(aOldID, aNewID int64) bool {
 	return result
 } // renameID()
 
+// `renameTag()` replaces the key `aOldTag` by `aNewTag`.
+//
+// If `aNewTag` already exists, the IDs of both lists are merged.
+// If `aOldTag` equals `aNewTag`, or `aOldTag` doesn't exist then
+// they are silently ignored (i.e. this method does nothing),
+// returning `false`.
+//
+// Parameters:
+//   - `aOldTag`: The tag to be replaced (including its leading mark).
+//   - `aNewTag`: The replacement tag (including its leading mark).
+//
+// Returns:
+//   - `bool`: `true` if the renaming was successful, or `false` otherwise.
+func (hm *tHashMap[ID]) renameTag(aOldTag, aNewTag string) bool {
+	return hm.mergeTags([]string{aOldTag}, aNewTag)
+} // renameTag()
+
+// `renormalise()` applies the current [Normalisation] to all keys
+// of the hash map.
+//
+// Keys which become equal after normalisation are merged into a
+// single list of IDs.
+//
+// Returns:
+//   - `bool`: `true` if at least one key was changed, or `false` otherwise.
+func (hm *tHashMap[ID]) renormalise() bool {
+	if 0 == len(*hm) {
+		return false
+	}
+
+	var (
+		key, tag string
+		ok       bool
+		sl, tl   *tSourceList[ID]
+		result   bool
+	)
+	nm := make(tHashMap[ID], max(len(*hm), defaultListSize))
+	for tag, sl = range *hm {
+		if key = normalise(tag); key != tag {
+			result = true
+		}
+		if tl, ok = nm[key]; ok {
+			tl.merge(*sl)
+		} else {
+			nm[key] = sl
+		}
+	}
+
+	if result {
+		*hm = nm
+	}
+
+	return result
+} // renormalise()
+
+// `renormaliseAll()` normalises the tags of the hash map along with
+// the ones of the additional data and their scopes (if any).
+//
+// Parameters:
+//   - `aExtras`: Optional additional data to normalise as well.
+func (hm *tHashMap[ID]) renormaliseAll(aExtras *tExtras[ID]) {
+	hm.renormalise()
+	if nil != aExtras {
+		aExtras.renormalise()
+		aExtras.renormaliseScopes()
+	}
+} // renormaliseAll()
+
 // `sort()` ensures that the hash map is sorted, which can improve the
 // performance of certain operations on the hash map, such as searching
 // for a specific key.
@@ -682,20 +1086,20 @@ func (hm *tHashMap) renameID(aOldID, aNewID int64) bool {
 // and understand, as it presents the keys in a consistent order.
 //
 // Returns:
-//   - `*tHashMap`: The sorted hash map.
-func (hm *tHashMap) sort() *tHashMap {
+//   - `*tHashMap[ID]`: The sorted hash map.
+func (hm *tHashMap[ID]) sort() *tHashMap[ID] {
 	if 0 == len(*hm) {
 		return hm
 	}
 	var (
 		key string
-		sl  *tSourceList
+		sl  *tSourceList[ID]
 	)
 
 	keys := hm.keys()
 	kLen := max(len(keys), defaultListSize)
 	// Create a new map to store sorted key-value pairs
-	sortedMap := make(tHashMap, kLen)
+	sortedMap := make(tHashMap[ID], kLen)
 	// Iterate through sorted keys and create a new sorted map
 	for _, key = range keys {
 		sl = (*hm)[key]
@@ -714,33 +1118,90 @@ func (hm *tHashMap) sort() *tHashMap {
 // Returns:
 //   - `int`: Number of bytes written to storage.
 //   - `error`: A possible I/O error.
-func (hm *tHashMap) store(aFilename string) (int, error) {
+func (hm *tHashMap[ID]) store(aFilename string) (int, error) {
+	return hm.storeWith(aFilename, nil)
+} // store()
+
+// `storeWith()` writes the whole hash/mention list along with the
+// optional additional data to `aFilename`.
+//
+// Parameters:
+//   - `aFileName`: Name of the file to use for storing the current hash map.
+//   - `aExtras`: Optional additional data to store (may be `nil`).
+//
+// Returns:
+//   - `int`: Number of bytes written to storage.
+//   - `error`: `ErrEmptyFilename` or a possible [TFileError].
+func (hm *tHashMap[ID]) storeWith(aFilename string, aExtras *tExtras[ID]) (int, error) {
 	if aFilename = strings.TrimSpace(aFilename); "" == aFilename {
-		return 0, se.New(errors.New("empty filename"), 1)
+		return 0, se.New(ErrEmptyFilename, 1)
 	}
 
-	file, err := os.OpenFile(aFilename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0660) //#nosec G302 #nosec G304
+	file, err := openLocked(aFilename)
 	if nil != err {
-		return 0, se.New(err, 3)
+		return 0, se.New(storeError(aFilename, err), 2)
 	}
 	defer file.Close()
 
+	size, err := hm.storeTo(file, aExtras, nil)
+	if nil != err {
+		return 0, se.New(storeError(aFilename, err), 2)
+	}
+
+	return size, nil
+} // storeWith()
+
+// `storeTo()` replaces the contents of `aFile` by the hash/mention
+// list along with the optional additional data.
+//
+// Parameters:
+//   - `aFile`: The file to write to.
+//   - `aExtras`: Optional additional data to store (may be `nil`).
+//   - `aStamp`: Optional state of the file written.
+//
+// Returns:
+//   - `int`: Number of bytes written to storage.
+//   - `error`: A possible I/O error.
+func (hm *tHashMap[ID]) storeTo(aFile *os.File, aExtras *tExtras[ID], aStamp *tFileStamp) (int, error) {
+	if err := aFile.Truncate(0); nil != err {
+		return 0, err
+	}
+	if _, err := aFile.Seek(0, io.SeekStart); nil != err {
+		return 0, err
+	}
+	sum := crc32.New(gCRCtable)
+	writer := io.MultiWriter(aFile, sum)
+
 	if !UseBinaryStorage {
 		// use plain text storage
-		return file.Write([]byte(hm.String()))
+		if _, err := writer.Write([]byte(hm.text(aExtras))); nil != err {
+			return 0, err
+		}
+	} else {
+		encoder := gob.NewEncoder(writer)
+		if err := encoder.Encode(hm); nil != err {
+			return 0, err
+		}
+		if !aExtras.isEmpty() {
+			// Older versions stop reading after the hash map.
+			if err := encoder.Encode(aExtras); nil != err {
+				return 0, err
+			}
+		}
 	}
 
-	encoder := gob.NewEncoder(file)
-	if err = encoder.Encode(hm); nil != err {
-		return 0, se.New(err, 1)
-	}
-	size, err := file.Seek(0, io.SeekEnd)
+	size, err := aFile.Seek(0, io.SeekEnd)
 	if nil != err {
-		return 0, se.New(err, 2)
+		return 0, err
+	}
+	if nil != aStamp {
+		if *aStamp, err = stampOf(aFile, sum.Sum32()); nil != err {
+			return 0, err
+		}
 	}
 
 	return int(size), nil
-} // store()
+} // storeTo()
 
 // `String()` is used to generate a footprint of the hash map.
 //
@@ -749,8 +1210,50 @@ func (hm *tHashMap) store(aFilename string) (int, error) {
 //
 // Returns:
 //   - `string`: The string representation of this hash map.
-func (hm *tHashMap) String() string {
-	if 0 == len(*hm) {
+func (hm *tHashMap[ID]) String() string {
+	return hm.text(nil)
+} // String()
+
+// `subtract()` removes all IDs of `aOther` from the respective lists
+// of this hash map.
+//
+// Tags becoming empty are deleted.
+//
+// Parameters:
+//   - `aOther`: The hash map whose IDs are to be removed.
+//
+// Returns:
+//   - `bool`: `true` if at least one ID was removed, or `false` otherwise.
+func (hm *tHashMap[ID]) subtract(aOther tHashMap[ID]) bool {
+	var result bool
+	for key, ol := range aOther {
+		sl, ok := (*hm)[key]
+		if !ok || (nil == ol) {
+			continue
+		}
+		if sl.subtract(*ol) {
+			result = true
+			if 0 == len(*sl) {
+				delete(*hm, key)
+			}
+		}
+	}
+
+	return result
+} // subtract()
+
+// `text()` returns the hash map in the plain text storage format.
+//
+// If `aExtras` is not `nil` the additional data are included, i.e.
+// the section headers use the tags' display spelling.
+//
+// Parameters:
+//   - `aExtras`: Optional additional data to include (may be `nil`).
+//
+// Returns:
+//   - `string`: The text representation of this hash map.
+func (hm *tHashMap[ID]) text(aExtras *tExtras[ID]) string {
+	if (0 == len(*hm)) && ((nil == aExtras) || (0 == len(aExtras.Scopes))) {
 		return ""
 	}
 
@@ -759,17 +1262,124 @@ func (hm *tHashMap) String() string {
 	buf.Grow(len(*hm) * 64) // Estimate average size
 
 	var (
-		hash string
-		sl   *tSourceList
+		hash, head string
+		sl         *tSourceList[ID]
 	)
 	keys := hm.keys()
 	// Iterate through sorted keys and create a new sorted string
 	for _, hash = range keys {
 		sl = (*hm)[hash]
-		buf.WriteString(fmt.Sprintf("[%s]\n%s", hash, sl.String()))
+		head = hash
+		if nil != aExtras {
+			head = aExtras.Info.display(hash)
+		}
+		buf.WriteString(fmt.Sprintf("[%s]\n%s", head, sl.text(aExtras.codec())))
+		if nil != aExtras {
+			aExtras.writeText(&buf, hash)
+		}
+	}
+	if nil != aExtras {
+		aExtras.writeScopes(&buf)
 	}
 
 	return buf.String()
-} // String()
+} // text()
+
+// `textLine()` processes a single (non-empty) line of a file in the
+// plain text format.
+//
+// Parameters:
+//   - `aLine`: The line to process.
+//   - `aHash`: The tag of the current section (updated by headers).
+//   - `aExtras`: Optional container for the additional data.
+//
+// Returns:
+//   - `error`: `nil` if `aLine` was handled, or the reason otherwise.
+func (hm *tHashMap[ID]) textLine(aLine string, aHash *string, aExtras *tExtras[ID]) error {
+	if 0 == len(aLine) {
+		return nil
+	}
+
+	// Fast path for hash headers: check first character before regex
+	if ('[' == aLine[0]) && (']' == aLine[len(aLine)-1]) {
+		matches := htHashHeadRE.FindStringSubmatch(aLine)
+		if nil == matches {
+			return errBadHeader
+		}
+		*aHash = normalise(matches[1])
+		if nil != aExtras {
+			// the header holds the tag's display spelling
+			aExtras.Info.note(matches[1])
+		}
+	} else if id, err := aExtras.codec().DecodeID(aLine); nil == err {
+		if "" == *aHash {
+			return errNoHeader
+		}
+		hm.insert(*aHash, id)
+	} else if nil != aExtras {
+		// additional data of the current tag (if any)
+		if !aExtras.parseText(*aHash, aLine) {
+			return errBadLine
+		}
+	}
+
+	return nil
+} // textLine()
+
+// `union()` adds all IDs of `aOther` to the respective lists of this
+// hash map.
+//
+// Parameters:
+//   - `aOther`: The hash map whose IDs are to be added.
+//
+// Returns:
+//   - `bool`: `true` if at least one ID was added, or `false` otherwise.
+func (hm *tHashMap[ID]) union(aOther tHashMap[ID]) bool {
+	var result bool
+	for key, sl := range aOther {
+		if (nil == sl) || (0 == len(*sl)) {
+			continue
+		}
+		if tl, ok := (*hm)[key]; ok {
+			if tl.merge(*sl) {
+				result = true
+			}
+			continue
+		}
+		list := slices.Clone(*sl)
+		(*hm)[key] = &list
+		result = true
+	}
+
+	return result
+} // union()
+
+// `view()` returns the list of object IDs associated with `aTag`.
+//
+// NOTE: The result is the list's live storage, i.e. it must not be
+// modified and becomes invalid with the next change of the map.
+//
+// Parameters:
+//   - `aDelim`: The start of words to search (i.e. either '@' or '#').
+//   - `aTag`: The hash to lookup.
+//
+// Returns:
+//   - `[]ID`: The IDs referencing `aTag`.
+func (hm *tHashMap[ID]) view(aDelim byte, aTag string) []ID {
+	// prepare for case-insensitive search:
+	if aTag = normalise(aTag); ("" == aTag) || (0 == len(*hm)) {
+		return nil
+	}
+
+	if aTag[0] != aDelim {
+		aTag = string(aDelim) + aTag
+	}
+
+	if sl, ok := (*hm)[aTag]; ok {
+		return *sl
+	}
+
+	return nil
+} // view()
 
 /* EoF */
@@ -11,10 +11,12 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"slices"
 	"sort"
 	"strconv"
 	"testing"
+	"time"
 )
 
 //lint:file-ignore ST1017 - I prefer Yoda conditions
@@ -34,8 +36,8 @@ func hmFilename(useBinary bool) string {
 
 const baseListLen = 64
 
-func prepHashMap() *tHashMap {
-	hm := make(tHashMap, baseListLen*2)
+func prepHashMap() *tHashMap[int64] {
+	hm := make(tHashMap[int64], baseListLen*2)
 	for i := range baseListLen {
 		for j := range baseListLen {
 			h, m := "#hash"+strconv.Itoa(j), "@mention"+strconv.Itoa(j)
@@ -48,7 +50,7 @@ func prepHashMap() *tHashMap {
 } // prepHashMap()
 
 func Test_tHashMap_checksum(t *testing.T) {
-	hm1 := newHashMap()
+	hm1 := newHashMap[int64]()
 	w1 := hm1.checksum()
 
 	hm2 := prepHashMap()
@@ -60,7 +62,7 @@ func Test_tHashMap_checksum(t *testing.T) {
 
 	tests1 := []struct {
 		name string
-		hm   *tHashMap
+		hm   *tHashMap[int64]
 		want uint32
 	}{
 		{"1", hm1, w1},
@@ -103,12 +105,12 @@ func Test_tHashMap_checksum(t *testing.T) {
 
 func Test_tHashMap_clear(t *testing.T) {
 	hm1 := prepHashMap()
-	wm1 := newHashMap()
+	wm1 := newHashMap[int64]()
 
 	tests := []struct {
 		name string
-		hm   *tHashMap
-		want *tHashMap
+		hm   *tHashMap[int64]
+		want *tHashMap[int64]
 	}{
 		{"1", hm1, wm1},
 
@@ -136,7 +138,7 @@ func Test_tHashMap_count(t *testing.T) {
 
 	tests := []struct {
 		name    string
-		hm      *tHashMap
+		hm      *tHashMap[int64]
 		delim   byte
 		wantInt int
 	}{
@@ -159,7 +161,7 @@ func Test_tHashMap_count(t *testing.T) {
 
 func Test_tHashMap_countedList(t *testing.T) {
 	// Create a small, controlled hashmap for testing
-	hm1 := &tHashMap{}
+	hm1 := &tHashMap[int64]{}
 	hm1.insert("#hash1", 111)
 	hm1.insert("#hash2", 222)
 	hm1.insert("#hash3", 333)
@@ -167,11 +169,11 @@ func Test_tHashMap_countedList(t *testing.T) {
 	hm1.insert("@mention2", 222)
 
 	// Empty hashmap for nil test
-	hm2 := &tHashMap{}
+	hm2 := &tHashMap[int64]{}
 	// var wc2 TCountList = nil
 
 	// Hashmap with multiple IDs per hash
-	hm3 := &tHashMap{}
+	hm3 := &tHashMap[int64]{}
 	hm3.insert("#hash1", 111)
 	hm3.insert("#hash1", 222) // Same hash, different ID
 	hm3.insert("@mention1", 111)
@@ -179,7 +181,7 @@ func Test_tHashMap_countedList(t *testing.T) {
 
 	tests := []struct {
 		name    string
-		hm      *tHashMap
+		hm      *tHashMap[int64]
 		wantInt int // Just check the length
 	}{
 		{"1", hm1, 5},                         // 5 entries
@@ -226,8 +228,8 @@ func Test_tHashMap_equals(t *testing.T) {
 
 	tests := []struct {
 		name string
-		hm   *tHashMap
-		oMap *tHashMap
+		hm   *tHashMap[int64]
+		oMap *tHashMap[int64]
 		want bool
 	}{
 		{"1", hm1, om1, true},
@@ -245,9 +247,47 @@ func Test_tHashMap_equals(t *testing.T) {
 	}
 } // Test_tHashMap_equals()
 
+func Test_tHashMap_filter(t *testing.T) {
+	hm1 := prepHashMap()
+	hm1.insert("#2024", 1)
+	hm1.insert("#1", 1)
+
+	numeric := regexp.MustCompile(`^[#@][0-9]+$`)
+	keepFunc := func(aTag string) bool {
+		return !numeric.MatchString(aTag)
+	}
+
+	tests := []struct {
+		name    string
+		hm      *tHashMap[int64]
+		keep    func(string) bool
+		want    int
+		wantLen int
+	}{
+		{"0", newHashMap[int64](), keepFunc, 0, 0},
+		{"1", hm1, nil, 0, baseListLen*2 + 2},
+		{"2", hm1, keepFunc, 2, baseListLen * 2},
+		{"3", hm1, keepFunc, 0, baseListLen * 2},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hm.filter(tt.keep); got != tt.want {
+				t.Errorf("%q: tHashMap.filter() = %d, want %d",
+					tt.name, got, tt.want)
+			}
+			if got := len(*tt.hm); got != tt.wantLen {
+				t.Errorf("%q: tHashMap.filter() length = %d, want %d",
+					tt.name, got, tt.wantLen)
+			}
+		})
+	}
+} // Test_tHashMap_filter()
+
 func Test_tHashMap_idList(t *testing.T) {
 	// Small controlled hashmap
-	hm1 := newHashMap()
+	hm1 := newHashMap[int64]()
 	hm1.insert("#Hash1", 111)
 	hm1.insert("@Mention1", 111)
 	hm1.insert("#Hash2", 222)
@@ -261,12 +301,12 @@ func Test_tHashMap_idList(t *testing.T) {
 
 	tests := []struct {
 		name         string
-		hm           *tHashMap
+		hm           *tHashMap[int64]
 		id           int64
 		wantLen      int
 		wantContains []string
 	}{
-		{"empty", newHashMap(), 111, 0, nil},
+		{"empty", newHashMap[int64](), 111, 0, nil},
 		{"single ID", hm1, 111, 2, []string{"#hash1", "@mention1"}}, // lowercase!
 		{"not found", hm1, 999, 0, nil},
 		{"unique ID in large map", hm2, 999999, 2, []string{"#uniquehash", "@uniquemention"}}, // lowercase!
@@ -348,7 +388,7 @@ func Test_tHashMap_insert(t *testing.T) {
 	}
 	tests := []struct {
 		name string
-		hm   *tHashMap
+		hm   *tHashMap[int64]
 		args tArgs
 		want bool
 	}{
@@ -369,16 +409,16 @@ func Test_tHashMap_insert(t *testing.T) {
 } // Test_tHashMap_insert()
 
 func Test_tHashMap_keys(t *testing.T) {
-	hm0 := newHashMap()
+	hm0 := newHashMap[int64]()
 
-	hm1 := newHashMap()
+	hm1 := newHashMap[int64]()
 	hm1.insert("#hash1", 111)
 	hm1.insert("#hash2", 222)
 	hm1.insert("@mention1", 333)
 
 	tests := []struct {
 		name     string
-		hm       *tHashMap
+		hm       *tHashMap[int64]
 		wantKeys []string
 		wantLen  int
 	}{
@@ -424,13 +464,13 @@ func Test_tHashMap_lenTotal(t *testing.T) {
 	hash1, hash2, hash3 := "#Hash1", "#Hash2", "#UndHash3"
 	id1, id2, id3 := int64(987), int64(654), int64(321)
 
-	hm1 := newHashMap()
+	hm1 := newHashMap[int64]()
 	hm1.insert(hash1, id1)
 	hm1.insert(hash2, id2)
 	hm1.insert(hash2, id1)
 	hm1.insert(hash1, id2)
 
-	hm2 := newHashMap()
+	hm2 := newHashMap[int64]()
 	hm2.insert(hash1, id1)
 	hm2.insert(hash2, id2)
 	hm2.insert(hash2, id1)
@@ -441,7 +481,7 @@ func Test_tHashMap_lenTotal(t *testing.T) {
 
 	tests := []struct {
 		name string
-		hm   *tHashMap
+		hm   *tHashMap[int64]
 		want int
 	}{
 		{" 1", hm1, 6},
@@ -461,7 +501,7 @@ func Test_tHashMap_lenTotal(t *testing.T) {
 
 func Test_tHashMap_list(t *testing.T) {
 	// Create a small, controlled hashmap for testing
-	hm1 := newHashMap()
+	hm1 := newHashMap[int64]()
 	hm1.insert("#hash1", 111)
 	hm1.insert("#hash2", 222)
 	hm1.insert("#hash3", 333)
@@ -485,7 +525,7 @@ func Test_tHashMap_list(t *testing.T) {
 	}
 	tests := []struct {
 		name     string
-		hm       *tHashMap
+		hm       *tHashMap[int64]
 		args     tArgs
 		wantList []int64
 	}{
@@ -542,9 +582,9 @@ func Test_tHashMap_load(t *testing.T) {
 
 	tests := []struct {
 		name    string
-		hm      *tHashMap
+		hm      *tHashMap[int64]
 		binary  bool
-		want    *tHashMap
+		want    *tHashMap[int64]
 		wantErr bool
 	}{
 		{"1", hm1, false, wm1, false},
@@ -576,6 +616,138 @@ func Test_tHashMap_load(t *testing.T) {
 	}
 } // Test_tHashMap_load()
 
+func Test_tHashMap_loadWith(t *testing.T) {
+	saveBinary := UseBinaryStorage
+	defer func() {
+		UseBinaryStorage = saveBinary
+	}()
+
+	hm1 := prepHashMap()
+	hm1.insert("#OpenSource", 1)
+	xt1 := newExtras[int64]()
+	xt1.Info.note("#OpenSource")
+
+	tests := []struct {
+		name   string
+		binary bool
+	}{
+		{"text", false},
+		{"binary", true},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := hmFilename(tt.binary)
+			if _, err := hm1.storeWith(fn, xt1); nil != err {
+				t.Errorf("%q: tHashMap.storeWith() error = %v", tt.name, err)
+				return
+			}
+
+			xt2 := newExtras[int64]()
+			got, err := newHashMap[int64]().loadWith(fn, xt2)
+			if nil != err {
+				t.Errorf("%q: tHashMap.loadWith() error = %v", tt.name, err)
+				return
+			}
+			if !hm1.equals(*got) {
+				t.Errorf("%q: tHashMap.loadWith() =\n%d\n>>>> want >>>>\n%d",
+					tt.name, len(*got), len(*hm1))
+			}
+			if display := xt2.Info.display("#opensource"); "#OpenSource" != display {
+				t.Errorf("%q: tHashMap.loadWith() display = %q, want %q",
+					tt.name, display, "#OpenSource")
+			}
+
+			// files with additional data must be readable without them
+			if _, err = newHashMap[int64]().load(fn); nil != err {
+				t.Errorf("%q: tHashMap.load() error = %v", tt.name, err)
+			}
+		})
+	}
+} // Test_tHashMap_loadWith()
+
+func Test_tHashMap_loadWith_normalise(t *testing.T) {
+	saveBinary := UseBinaryStorage
+	defer func() {
+		UseBinaryStorage = saveBinary
+	}()
+
+	// data written using another normalisation
+	hm1 := newHashMap[int64]()
+	hm1.insert("#golang", 1)
+	(*hm1)["#GoLang"] = (*hm1)["#golang"]
+	delete(*hm1, "#golang")
+	xt1 := newExtras[int64]()
+	xt1.Times.set("#GoLang", 1, time.Now())
+
+	for _, binary := range []bool{false, true} {
+		fn := hmFilename(binary)
+		if _, err := hm1.storeWith(fn, xt1); nil != err {
+			t.Fatalf("tHashMap.storeWith() error = %v", err)
+		}
+
+		xt2 := newExtras[int64]()
+		got, err := newHashMap[int64]().loadWith(fn, xt2)
+		if nil != err {
+			t.Fatalf("tHashMap.loadWith() error = %v", err)
+		}
+		if ids := got.list(MarkHash, "#golang"); 1 != len(ids) {
+			t.Errorf("binary %v: tHashMap.loadWith() = %v", binary, got.String())
+		}
+		if _, ok := xt2.Times.get("#golang", 1); !ok {
+			t.Errorf("binary %v: tHashMap.loadWith() times = %v", binary, xt2.Times)
+		}
+	}
+} // Test_tHashMap_loadWith_normalise()
+
+func Test_tHashMap_mergeTags(t *testing.T) {
+	hm1 := newHashMap[int64]()
+	hm1.insert("#hash1", 1)
+	hm1.insert("#hash1", 3)
+	hm1.insert("#hash2", 2)
+	hm1.insert("#hash2", 3)
+	hm1.insert("@mention1", 4)
+
+	type tArgs struct {
+		aSources []string
+		aTarget  string
+	}
+	tests := []struct {
+		name     string
+		args     tArgs
+		want     bool
+		wantList []int64
+	}{
+		{"0", tArgs{}, false, nil},
+		{"1", tArgs{[]string{"#hash9"}, "#hash1"}, false, []int64{1, 3}},
+		{"2", tArgs{[]string{"#hash1"}, "#hash1"}, false, []int64{1, 3}},
+		{"3", tArgs{[]string{"#HASH2", "@mention1"}, "#hash1"}, true, []int64{1, 2, 3, 4}},
+		{"4", tArgs{[]string{"#hash1"}, "#hash3"}, true, []int64{1, 2, 3, 4}},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hm1.mergeTags(tt.args.aSources, tt.args.aTarget); got != tt.want {
+				t.Errorf("%q: tHashMap.mergeTags() = '%v', want '%v'",
+					tt.name, got, tt.want)
+			}
+			if "" == tt.args.aTarget {
+				return
+			}
+			gotList := hm1.list(tt.args.aTarget[0], tt.args.aTarget)
+			if !slices.Equal(gotList, tt.wantList) {
+				t.Errorf("%q: tHashMap.mergeTags() =\n%v\n>>>> want: >>>>\n%v",
+					tt.name, gotList, tt.wantList)
+			}
+		})
+	}
+	if 1 != len(*hm1) {
+		t.Errorf("tHashMap.mergeTags() left %d tags, want 1", len(*hm1))
+	}
+} // Test_tHashMap_mergeTags()
+
 func Test_tHashMap_remove(t *testing.T) {
 	hm := prepHashMap()
 	hm.insert("#nameX", 999)
@@ -620,7 +792,7 @@ func Test_tHashMap_removeHM(t *testing.T) {
 	}
 	tests := []struct {
 		name string
-		hm   *tHashMap
+		hm   *tHashMap[int64]
 		args tArgs
 		want bool
 	}{
@@ -647,7 +819,7 @@ func Test_tHashMap_removeHM(t *testing.T) {
 } // Test_tHashMap_removeHM()
 
 func Test_tHashMap_removeID(t *testing.T) {
-	hm0 := newHashMap()
+	hm0 := newHashMap[int64]()
 
 	hm1 := prepHashMap()
 	hm1.insert("#hash2", 999)
@@ -656,7 +828,7 @@ func Test_tHashMap_removeID(t *testing.T) {
 
 	tests := []struct {
 		name string
-		hm   *tHashMap
+		hm   *tHashMap[int64]
 		id   int64
 		want bool
 	}{
@@ -693,14 +865,14 @@ func Test_tHashMap_renameID(t *testing.T) {
 	}
 	tests := []struct {
 		name string
-		hm   *tHashMap
+		hm   *tHashMap[int64]
 		args tArgs
 		want bool
 	}{
 		{"0", hm1, tArgs{}, false}, // no change
 		{"1", hm1, tArgs{id1, id2}, true},
 		{"2", hm2, tArgs{id2, id3}, true},
-		{"3", newHashMap(), tArgs{id1, id2}, false},
+		{"3", newHashMap[int64](), tArgs{id1, id2}, false},
 
 		// TODO: Add test cases.
 	}
@@ -714,39 +886,117 @@ func Test_tHashMap_renameID(t *testing.T) {
 	}
 } // Test_tHashMap_renameID
 
+func Test_tHashMap_renameTag(t *testing.T) {
+	hm1 := prepHashMap()
+	hm1.insert("#hash1", 999)
+
+	type tArgs struct {
+		aOldTag, aNewTag string
+	}
+	tests := []struct {
+		name    string
+		args    tArgs
+		want    bool
+		wantLen int
+	}{
+		{"0", tArgs{}, false, -1},
+		{"1", tArgs{"#hash1", "#hash1"}, false, baseListLen + 1},
+		{"2", tArgs{"#unknown", "#hash1"}, false, baseListLen + 1},
+		{"3", tArgs{"#hash1", "#renamed"}, true, baseListLen + 1},
+		{"4", tArgs{"#hash2", "#renamed"}, true, baseListLen + 1},
+		{"5", tArgs{"#hash1", "#renamed"}, false, baseListLen + 1},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hm1.renameTag(tt.args.aOldTag, tt.args.aNewTag); got != tt.want {
+				t.Errorf("%q: tHashMap.renameTag() = '%v', want '%v'",
+					tt.name, got, tt.want)
+			}
+			if got := hm1.idxLen(MarkHash, tt.args.aNewTag); got != tt.wantLen {
+				t.Errorf("%q: tHashMap.renameTag() length = '%d', want '%d'",
+					tt.name, got, tt.wantLen)
+			}
+		})
+	}
+} // Test_tHashMap_renameTag()
+
+func Test_tHashMap_renormalise(t *testing.T) {
+	saveNorm := Normalisation()
+	defer func() {
+		SetNormalisation(saveNorm)
+	}()
+
+	hm1 := newHashMap[int64]()
+	hm1.insert("#straße", 1)
+	hm1.insert("#strasse", 2)
+	hm1.insert("#cafe\u0301", 3)
+
+	tests := []struct {
+		name    string
+		norm    TNormalisation
+		want    bool
+		wantLen int
+	}{
+		{"0", saveNorm, false, 3},
+		{"1", TNormalisation{Form: NormNFC, CaseFolding: FoldFull}, true, 2},
+		{"2", TNormalisation{Form: NormNFC, CaseFolding: FoldFull}, false, 2},
+		{"3", TNormalisation{Form: NormNFC, CaseFolding: FoldFull, StripMarks: true}, true, 2},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetNormalisation(tt.norm)
+			if got := hm1.renormalise(); got != tt.want {
+				t.Errorf("%q: tHashMap.renormalise() = '%v', want '%v'",
+					tt.name, got, tt.want)
+			}
+			if got := len(*hm1); got != tt.wantLen {
+				t.Errorf("%q: tHashMap.renormalise() length = '%d', want '%d'",
+					tt.name, got, tt.wantLen)
+			}
+		})
+	}
+	if got := hm1.list(MarkHash, "#STRASSE"); 2 != len(got) {
+		t.Errorf("tHashMap.renormalise() merged list = %v, want 2 IDs", got)
+	}
+} // Test_tHashMap_renormalise()
+
 func Test_tHashMap_sort(t *testing.T) {
-	hm1 := &tHashMap{
-		"#hash1": &tSourceList{
+	hm1 := &tHashMap[int64]{
+		"#hash1": &tSourceList[int64]{
 			int64(111),
 		},
-		"@mention1": &tSourceList{
+		"@mention1": &tSourceList[int64]{
 			int64(111),
 		},
-		"#hash2": &tSourceList{
+		"#hash2": &tSourceList[int64]{
 			int64(222),
 		},
-		"@mention2": &tSourceList{
+		"@mention2": &tSourceList[int64]{
 			int64(333),
 			int64(222),
 		},
-		"#hash3": &tSourceList{
+		"#hash3": &tSourceList[int64]{
 			int64(333),
 		},
 	}
-	wm1 := &tHashMap{
-		"#hash1": &tSourceList{
+	wm1 := &tHashMap[int64]{
+		"#hash1": &tSourceList[int64]{
 			int64(111),
 		},
-		"#hash2": &tSourceList{
+		"#hash2": &tSourceList[int64]{
 			int64(222),
 		},
-		"#hash3": &tSourceList{
+		"#hash3": &tSourceList[int64]{
 			int64(333),
 		},
-		"@mention1": &tSourceList{
+		"@mention1": &tSourceList[int64]{
 			int64(111),
 		},
-		"@mention2": &tSourceList{
+		"@mention2": &tSourceList[int64]{
 			int64(222),
 			int64(333),
 		},
@@ -754,8 +1004,8 @@ func Test_tHashMap_sort(t *testing.T) {
 
 	tests := []struct {
 		name string
-		hm   *tHashMap
-		want *tHashMap
+		hm   *tHashMap[int64]
+		want *tHashMap[int64]
 	}{
 		{"1", hm1, wm1},
 
@@ -782,13 +1032,13 @@ func Test_tHashMap_store(t *testing.T) {
 
 	tests := []struct {
 		name    string
-		hm      *tHashMap
+		hm      *tHashMap[int64]
 		binary  bool
 		wantInt int
 		wantErr bool
 	}{
 		{"1", hm1, false, 140744, false}, // expected file size
-		{"2", hm1, true, 23653, false},
+		{"2", hm1, true, 23660, false},   // incl. gob type name `tHashMap[int64]`
 
 		// TODO: Add test cases.
 	}
@@ -812,11 +1062,11 @@ func Test_tHashMap_store(t *testing.T) {
 
 func Test_tHashMap_String(t *testing.T) {
 	// Empty hashmap
-	hm1 := newHashMap()
+	hm1 := newHashMap[int64]()
 	want1 := ""
 
 	// Hashmap with single entry
-	hm2 := newHashMap()
+	hm2 := newHashMap[int64]()
 	hm2.insert("#test", 123)
 
 	// Hashmap with multiple entries from prepHashMap()
@@ -824,7 +1074,7 @@ func Test_tHashMap_String(t *testing.T) {
 
 	tests := []struct {
 		name string
-		hm   *tHashMap
+		hm   *tHashMap[int64]
 		want string
 	}{
 		{"empty", hm1, want1},
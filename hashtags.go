@@ -8,13 +8,16 @@ package hashtags
 
 import (
 	"bytes"
-	"errors"
+	"cmp"
+	"context"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	se "github.com/mwat56/sourceerror"
 )
@@ -32,21 +35,51 @@ const (
 type (
 	// `tCountCache` is a data cache for `CountedList()`.
 	tCountCache struct {
-		crc uint32     // current CRC
-		cl  TCountList // last list of counted items
+		crc  uint32                   // current CRC
+		cl   TCountList               // last list of counted items
+		keys atomic.Pointer[[]string] // sorted tags for `ListPage()`
 	}
 
-	// `THashTags` is a list of `#hashtags` and `@mentions`
-	// pointing to sources (i.e. IDs).
-	THashTags struct {
-		mtx     sync.RWMutex // safeguard against concurrent accesses
-		hm      *tHashMap    // the actual map list of sources/IDs
-		fn      string       // the filename to use
-		cc      tCountCache  // cache for `CountedList()`
-		changed uint32       // internal change flag
-		safe    bool         // flag for optional thread safety
+	// `TDiff` lists the changes of the tags associated with an ID.
+	TDiff struct {
+		Added   []string // normalised tags new for the ID
+		Removed []string // normalised tags no longer used by the ID
+	}
+
+	// `TIndex` is a list of `#hashtags` and `@mentions`
+	// pointing to sources (i.e. IDs of type `ID`).
+	TIndex[ID cmp.Ordered] struct {
+		mtx     *sync.RWMutex          // safeguard against concurrent accesses
+		hm      *tHashMap[ID]          // the actual map list of sources/IDs
+		xt      *tExtras[ID]           // optional data stored along with `hm`
+		vr      *tValidator            // optional tag validation rules
+		fn      string                 // the filename to use
+		root    *TIndex[ID]            // the list a scope belongs to (if any)
+		scope   string                 // the name of this scope (if any)
+		sh      map[string]*TIndex[ID] // scopes handed out by `Scope()`
+		cc      tCountCache            // cache for `CountedList()`
+		fst     tFileStamp             // file state at the last load/store
+		changed uint32                 // internal change flag
+		scrc    uint32                 // checksum at the last load/store
+		cp      TConflictPolicy        // handling of external file changes
+		ws      tWatchers              // subscribers of `Watch()` reloads
+		hs      tHistory[ID]           // optional record of modifications
+		rb      tRebuildState          // progress of `Rebuild()`
+		rt      time.Duration          // retention period of association times
+		auto    bool                   // flag for storing after each change
+		ro      bool                   // flag for rejecting modifications
+		pos     bool                   // flag for recording tag positions
+		safe    bool                   // flag for optional thread safety
+		stamp   bool                   // flag for recording association times
 	}
 
+	// `THashTags` is a list of `#hashtags` and `@mentions`
+	// pointing to sources (i.e. `int64` IDs).
+	//
+	// This is the type used by all former versions of this package;
+	// its files are compatible with those versions.
+	THashTags = TIndex[int64]
+
 	// `THashTagError` is a custom error.
 	// Deprecated: Use [sourceerror.ErrSource] instead.
 	THashTagError = se.ErrSource
@@ -65,8 +98,8 @@ var (
 
 	// match: #hashtag|@mention
 	htHashMentionRE = regexp.MustCompile(
-		`(?ims)(?:^|\s|[^\p{L}\d_])?([@#][\p{L}’'\d_§-]+)(?:[^\p{L}\d_]|$)`)
-	//	                             1111111111111111111  222222222222222
+		`(?ims)(?:^|\s|[^\p{L}\p{M}\d_])?([@#][\p{L}\p{M}’'\d_§-]+)(?:[^\p{L}\p{M}\d_]|$)`)
+	//	                                   111111111111111111111111  22222222222222222222
 
 	// RegEx to match texts like `#----`.
 	htHyphenRE = regexp.MustCompile(`#[^-]*--`)
@@ -88,20 +121,48 @@ var (
 //   - `*THashTags`: The new `THashTags` instance.
 //   - `error`: `nil` in case of success, otherwise an error.
 func New(aFilename string) (*THashTags, error) {
-	ht := &THashTags{
-		hm:   newHashMap(),
+	return NewIndex[int64](aFilename, nil)
+} // New()
+
+// `NewIndex()` returns a new `TIndex` instance using IDs of type `ID`
+// after reading the given file.
+//
+// The IDs are written to files in the plain text format (see
+// [UseBinaryStorage]) by `aCodec`; the binary format handles all
+// `ID` types natively. A file must always be read with the same ID
+// type (and codec) it was written with.
+//
+// NOTE: An empty filename or if the hash file doesn't exist is not
+// considered an error.
+//
+// Parameters:
+//   - `aFilename`: The name of the file to use for loading and storing.
+//   - `aCodec`: The codec for the text format (`nil` = [DefaultCodec]).
+//
+// Returns:
+//   - `*TIndex[ID]`: The new `TIndex` instance.
+//   - `error`: `nil` in case of success, otherwise an error.
+func NewIndex[ID cmp.Ordered](aFilename string, aCodec TIDCodec[ID]) (*TIndex[ID], error) {
+	ht := &TIndex[ID]{
+		mtx:  new(sync.RWMutex),
+		hm:   newHashMap[ID](),
+		xt:   newExtras[ID](),
 		safe: true,
 	}
+	ht.xt.ic = aCodec
 
 	if aFilename = strings.TrimSpace(aFilename); "" == aFilename {
 		return ht, nil
 	}
 	ht.fn = aFilename
 
-	_, err := ht.hm.load(aFilename) // err already wrapped
+	var stamp tFileStamp
+	_, err := ht.hm.loadFile(context.Background(),
+		aFilename, ht.xt, LoadLenient, nil, &stamp) // err already wrapped
+	ht.synced(stamp)
 
 	return ht, err
-} // New()
+} // NewIndex()
 
 // `HashMentionRE()` returns a compiled regular expression used to
 // identify `#hashtags` and `@mentions` in a text.
@@ -115,8 +176,34 @@ func HashMentionRE() *regexp.Regexp {
 	return htHashMentionRE
 } // HashMentionRE()
 
+// `tagName()` prepares `aTag` for use as a list key.
+//
+// Leading and trailing whitespace is removed and a `#hashtag` mark
+// is prepended if `aTag` starts with neither '#' nor '@'.
+//
+// Parameters:
+//   - `aTag`: The `#hashtag` or `@mention` to prepare.
+//
+// Returns:
+//   - `string`: The prepared tag, or an empty string if `aTag` is empty.
+func tagName(aTag string) string {
+	if aTag = strings.TrimSpace(aTag); "" == aTag {
+		return ""
+	}
+
+	switch aTag[0] {
+	case MarkHash, MarkMention:
+		if 1 == len(aTag) {
+			return "" // a mark without a name
+		}
+		return aTag
+	}
+
+	return string(MarkHash) + aTag
+} // tagName()
+
 // -------------------------------------------------------------------------
-// methods of `THashTags`:
+// methods of `TIndex`:
 
 // `checksum()` returns the list's CRC32 checksum.
 //
@@ -125,7 +212,7 @@ func HashMentionRE() *regexp.Regexp {
 //
 // Returns:
 //   - `uint32`: The computed checksum.
-func (ht *THashTags) checksum() uint32 {
+func (ht *TIndex[ID]) checksum() uint32 {
 	if 0 == atomic.LoadUint32(&ht.changed) {
 		atomic.StoreUint32(&ht.changed, ht.hm.checksum())
 	}
@@ -140,7 +227,7 @@ func (ht *THashTags) checksum() uint32 {
 //
 // Returns:
 //   - `uint32`: The computed checksum.
-func (ht *THashTags) Checksum() uint32 {
+func (ht *TIndex[ID]) Checksum() uint32 {
 	if ht.safe {
 		ht.mtx.RLock()
 		defer ht.mtx.RUnlock()
@@ -153,43 +240,143 @@ func (ht *THashTags) Checksum() uint32 {
 // all `#hashtags` and `@mentions` are deleted.
 //
 // Returns:
-//   - `*THashTags`: This cleared list.
-func (ht *THashTags) Clear() *THashTags {
+//   - `*TIndex[ID]`: This cleared list.
+func (ht *TIndex[ID]) Clear() *TIndex[ID] {
+	if ht.readOnly() {
+		return ht
+	}
+
 	if ht.safe {
 		ht.mtx.Lock()
 		defer ht.mtx.Unlock()
 	}
+	defer ht.deferredStore()()
+
+	var (
+		diff TIndexDiff[ID]
+		undo *tExtras[ID]
+	)
+	if 0 < ht.hs.size {
+		diff = diffMaps(*ht.hm, tHashMap[ID]{})
+		undo = newExtras[ID]()
+		undo.ic = ht.xt.ic
+		undo.union(ht.xt)
+	}
 
 	ht.hm.clear()
-	atomic.StoreUint32(&ht.changed, 0)
+	ht.xt.clear()
+	ht.rebindScopes()
+	ht.record(HistoryClear, diff, undo, nil)
+	ht.invalidate()
 
 	return ht
 } // Clear()
 
 // `deferredStore()` returns a closure that, when executed, checks whether
-// the list's contents have changed and if so, asynchronously stores
-// the current state to the configured file.
+// the list's contents have changed and if so, stores the list if the
+// autosave mode is enabled (see [SetAutoSave]).
+//
+// This method is meant to be used internally with the `defer` statement
+// (i.e. `defer ht.deferredStore()()`) after acquiring the list's write
+// lock. Since the methods using it can't report errors, storing the list
+// may fail silently; use the context-aware methods to get such errors.
 //
-// This method is meant to be used internally with the `defer` statement.
+// NOTE: This method expects the caller to hold the list's write lock.
 //
 // Returns:
 //   - `func()`: A closure that handles deferred storage operations.
-func (ht *THashTags) deferredStore() func() {
-	oldCRC := ht.hm.checksum()
+func (ht *TIndex[ID]) deferredStore() func() {
+	root := ht
+	if nil != ht.root {
+		root = ht.root
+	}
+	if ht.readOnly() || !root.auto || ("" == root.fn) {
+		return func() {}
+	}
+	oldCRC := ht.checksum()
 
 	return func() {
-		if oldCRC != atomic.LoadUint32(&ht.changed) {
-			go ht.hm.store(ht.fn)
+		if oldCRC != ht.checksum() {
+			_ = ht.autoSave()
 		}
 	}
 } // deferredStore()
 
+// `DisplayName()` returns the original spelling of `aTag`.
+//
+// While all tags are stored normalised (see [Normalisation]) to allow
+// for case-insensitive lookups, the spelling first seen for each tag
+// is kept for displaying purposes.
+//
+// Tags without a leading mark are considered `#hashtags`.
+//
+// Parameters:
+//   - `aTag`: The `#hashtag` or `@mention` to lookup.
+//
+// Returns:
+//   - `string`: The display spelling of `aTag`, or an empty string if `aTag` is unknown.
+func (ht *TIndex[ID]) DisplayName(aTag string) string {
+	if aTag = tagName(aTag); "" == aTag {
+		return ""
+	}
+
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	key := normalise(aTag)
+	if _, ok := (*ht.hm)[key]; !ok {
+		return ""
+	}
+
+	return ht.xt.Info.display(key)
+} // DisplayName()
+
+// `Expire()` deletes all association times older than the retention
+// period (see [SetRetention]).
+//
+// The associations themselves are kept; only their times are deleted
+// so that they're no longer considered by [Trending]. This method is
+// called automatically by [Store].
+//
+// Returns:
+//   - `int`: The number of deleted association times.
+func (ht *TIndex[ID]) Expire() int {
+	if ht.readOnly() {
+		return 0
+	}
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+
+	if (0 >= ht.rt) || (0 == len(ht.xt.Times)) {
+		return 0
+	}
+
+	limit := time.Now().Add(-ht.rt)
+	result := ht.xt.Times.dropIf(func(aTime time.Time) bool {
+		return aTime.Before(limit)
+	})
+	if 0 < result {
+		ht.invalidate()
+	}
+
+	return result
+} // Expire()
+
 // `Filename()` returns the configured filename for reading/storing
 // this list's contents.
 //
 // Returns:
 //   - `string`: The filename for reading/storing this list.
-func (ht *THashTags) Filename() string {
+func (ht *TIndex[ID]) Filename() string {
+	if nil != ht.root {
+		return ht.root.Filename()
+	}
+
 	if ht.safe {
 		ht.mtx.RLock()
 		defer ht.mtx.RUnlock()
@@ -209,7 +396,11 @@ func (ht *THashTags) Filename() string {
 //
 // Returns:
 //   - `bool`: `true` if `aID` was added, or `false` otherwise.
-func (ht *THashTags) HashAdd(aHash string, aID int64) bool {
+func (ht *TIndex[ID]) HashAdd(aHash string, aID ID) bool {
+	if ht.readOnly() {
+		return false
+	}
+
 	if aHash = strings.TrimSpace(aHash); "" == aHash {
 		return false
 	}
@@ -218,15 +409,44 @@ func (ht *THashTags) HashAdd(aHash string, aID int64) bool {
 		ht.mtx.Lock()
 		defer ht.mtx.Unlock()
 	}
+	defer ht.deferredStore()()
 
-	return ht.insert(MarkHash, aHash, aID)
+	return ht.insert(MarkHash, aHash, aID, time.Time{})
 } // HashAdd()
 
+// `HashAddAt()` works like [HashAdd] but records `aTime` as the time
+// `aID` was associated with `aHash` (see [SetTimestamps]).
+//
+// Parameters:
+//   - `aHash`: The hash list index to use.
+//   - `aID`: The object to be added to the hash list.
+//   - `aTime`: The time of the association (zero value: now).
+//
+// Returns:
+//   - `bool`: `true` if `aID` was added, or `false` otherwise.
+func (ht *TIndex[ID]) HashAddAt(aHash string, aID ID, aTime time.Time) bool {
+	if ht.readOnly() {
+		return false
+	}
+
+	if aHash = strings.TrimSpace(aHash); "" == aHash {
+		return false
+	}
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+	defer ht.deferredStore()()
+
+	return ht.insert(MarkHash, aHash, aID, aTime)
+} // HashAddAt()
+
 // `HashCount()` counts the number of hashtags in the list.
 //
 // Returns:
 //   - `int`: The number of hashes in the list.
-func (ht *THashTags) HashCount() int {
+func (ht *TIndex[ID]) HashCount() int {
 	if ht.safe {
 		ht.mtx.RLock()
 		defer ht.mtx.RUnlock()
@@ -245,7 +465,7 @@ func (ht *THashTags) HashCount() int {
 //
 // Returns:
 //   - `int`: The number of `aHash` in the list.
-func (ht *THashTags) HashLen(aHash string) int {
+func (ht *TIndex[ID]) HashLen(aHash string) int {
 	if aHash = strings.TrimSpace(aHash); "" == aHash {
 		return 0
 	}
@@ -260,6 +480,9 @@ func (ht *THashTags) HashLen(aHash string) int {
 
 // `HashList()` returns a list of IDs associated with `aHash`.
 //
+// The result is a copy which the caller may modify at will; see
+// [TIndex.HashView] for a read-only variant avoiding the copy.
+//
 // If `aHash` is empty it is silently ignored (i.e. this method
 // does nothing), returning an empty slice.
 //
@@ -267,10 +490,10 @@ func (ht *THashTags) HashLen(aHash string) int {
 //   - `aHash`: The hash to lookup.
 //
 // Returns:
-//   - `[]int64`: The number of references of `aHash`.
-func (ht *THashTags) HashList(aHash string) []int64 {
+//   - `[]ID`: The number of references of `aHash`.
+func (ht *TIndex[ID]) HashList(aHash string) []ID {
 	if aHash = strings.TrimSpace(aHash); "" == aHash {
-		return []int64{}
+		return []ID{}
 	}
 
 	if ht.safe {
@@ -289,7 +512,11 @@ func (ht *THashTags) HashList(aHash string) []int64 {
 //
 // Returns:
 //   - `bool`: `true` if `aID` was removed, or `false` otherwise.
-func (ht *THashTags) HashRemove(aHash string, aID int64) bool {
+func (ht *TIndex[ID]) HashRemove(aHash string, aID ID) bool {
+	if ht.readOnly() {
+		return false
+	}
+
 	if aHash = strings.TrimSpace(aHash); "" == aHash {
 		return false
 	}
@@ -298,10 +525,82 @@ func (ht *THashTags) HashRemove(aHash string, aID int64) bool {
 		ht.mtx.Lock()
 		defer ht.mtx.Unlock()
 	}
+	defer ht.deferredStore()()
 
 	return ht.removeHM(MarkHash, aHash, aID)
 } // HashRemove()
 
+// `IDdiff()` returns the changes [IDupdate] would apply for `aID`
+// and `aText` without actually applying them.
+//
+// Parameters:
+//   - `aID`: The ID to check.
+//   - `aText`: The new text to use.
+//
+// Returns:
+//   - `TDiff`: The tags to be added and removed.
+func (ht *TIndex[ID]) IDdiff(aID ID, aText []byte) TDiff {
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	result, _ := ht.idDiff(aID, aText)
+
+	return result
+} // IDdiff()
+
+// `idDiff()` compares the tags currently associated with `aID` with
+// those found in `aText`.
+//
+// Parameters:
+//   - `aID`: The ID to check.
+//   - `aText`: The new text to use.
+//
+// Returns:
+//   - `rDiff`: The tags to be added and removed.
+//   - `rTags`: All valid tags found in `aText`.
+func (ht *TIndex[ID]) idDiff(aID ID, aText []byte) (rDiff TDiff, rTags []TTag) {
+	ex := &tExtractor{vr: ht.vr}
+	ex.extract(aText, 0, func(aTag TTag) bool {
+		rTags = append(rTags, aTag)
+		return true
+	})
+
+	return ht.tagsDiff(aID, rTags), rTags
+} // idDiff()
+
+// `tagsDiff()` compares the tags currently associated with `aID` with
+// `aTags`.
+//
+// Parameters:
+//   - `aID`: The ID to check.
+//   - `aTags`: The valid tags found in the new text.
+//
+// Returns:
+//   - `rDiff`: The tags to be added and removed.
+func (ht *TIndex[ID]) tagsDiff(aID ID, aTags []TTag) (rDiff TDiff) {
+	found := make(map[string]struct{}, len(aTags))
+	for _, tag := range aTags {
+		found[tag.Key] = struct{}{}
+	}
+
+	current := ht.hm.idList(aID)
+	for _, key := range current {
+		if _, ok := found[key]; ok {
+			delete(found, key) // unchanged
+		} else {
+			rDiff.Removed = append(rDiff.Removed, key)
+		}
+	}
+	for key := range found {
+		rDiff.Added = append(rDiff.Added, key)
+	}
+	slices.Sort(rDiff.Added)
+
+	return
+} // tagsDiff()
+
 // `IDlist()` returns a list of `#hashtags` and `@mentions` associated
 // with `aID`.
 //
@@ -310,7 +609,7 @@ func (ht *THashTags) HashRemove(aHash string, aID int64) bool {
 //
 // Returns:
 //   - `[]string`: The list of `#hashtags` and `@mentions` associated with `aID`.
-func (ht *THashTags) IDlist(aID int64) []string {
+func (ht *TIndex[ID]) IDlist(aID ID) []string {
 	if ht.safe {
 		ht.mtx.RLock()
 		defer ht.mtx.RUnlock()
@@ -332,8 +631,26 @@ func (ht *THashTags) IDlist(aID int64) []string {
 //
 // Returns:
 //   - `bool`: `true` if `aID` was updated from `aText`, or `false` otherwise.
-func (ht *THashTags) IDparse(aID int64, aText []byte) bool {
-	if aText = bytes.TrimSpace(aText); 0 == len(aText) {
+func (ht *TIndex[ID]) IDparse(aID ID, aText []byte) bool {
+	return ht.IDparseAt(aID, aText, time.Time{})
+} // IDparse()
+
+// `IDparseAt()` works like [IDparse] but records `aTime` as the time
+// `aID` was associated with the tags found (see [SetTimestamps]).
+//
+// Parameters:
+//   - `aID`: The ID to add to the list.
+//   - `aText:` The text to search.
+//   - `aTime`: The time of the associations (zero value: now).
+//
+// Returns:
+//   - `bool`: `true` if `aID` was updated from `aText`, or `false` otherwise.
+func (ht *TIndex[ID]) IDparseAt(aID ID, aText []byte, aTime time.Time) bool {
+	if ht.readOnly() {
+		return false
+	}
+
+	if 0 == len(bytes.TrimSpace(aText)) {
 		return false
 	}
 
@@ -341,15 +658,15 @@ func (ht *THashTags) IDparse(aID int64, aText []byte) bool {
 		ht.mtx.Lock()
 		defer ht.mtx.Unlock()
 	}
-	defer ht.deferredStore()
+	defer ht.deferredStore()()
 
-	if ht.parseID(aID, aText) {
-		atomic.StoreUint32(&ht.changed, 0)
+	if ht.parseID(aID, aText, aTime) {
+		ht.invalidate()
 		return true
 	}
 
 	return false
-} // IDparse()
+} // IDparseAt()
 
 // `IDremove()` deletes all `#hashtags` and `@mentions` associated with `aID`.
 //
@@ -358,19 +675,18 @@ func (ht *THashTags) IDparse(aID int64, aText []byte) bool {
 //
 // Returns:
 //   - `bool`: `true` if `aID` was removed, or `false` otherwise.
-func (ht *THashTags) IDremove(aID int64) bool {
+func (ht *TIndex[ID]) IDremove(aID ID) bool {
+	if ht.readOnly() {
+		return false
+	}
+
 	if ht.safe {
 		ht.mtx.Lock()
 		defer ht.mtx.Unlock()
 	}
-	defer ht.deferredStore()
-
-	if ht.hm.removeID(aID) {
-		atomic.StoreUint32(&ht.changed, 0)
-		return true
-	}
+	defer ht.deferredStore()()
 
-	return false
+	return ht.removeID(aID)
 } // IDremove()
 
 // `IDrename()` replaces all occurrences of `aOldID` by `aNewID`.
@@ -387,7 +703,11 @@ func (ht *THashTags) IDremove(aID int64) bool {
 //
 // Returns:
 //   - `bool`: `true` if `aOldID` was renamed, or `false` otherwise.
-func (ht *THashTags) IDrename(aOldID, aNewID int64) bool {
+func (ht *TIndex[ID]) IDrename(aOldID, aNewID ID) bool {
+	if ht.readOnly() {
+		return false
+	}
+
 	if (aOldID == aNewID) || (0 == len(*ht.hm)) {
 		return false
 	}
@@ -396,14 +716,9 @@ func (ht *THashTags) IDrename(aOldID, aNewID int64) bool {
 		ht.mtx.Lock()
 		defer ht.mtx.Unlock()
 	}
-	defer ht.deferredStore()
-
-	if ht.hm.renameID(aOldID, aNewID) {
-		atomic.StoreUint32(&ht.changed, 0)
-		return true
-	}
+	defer ht.deferredStore()()
 
-	return false
+	return ht.renameID(aOldID, aNewID)
 } // IDrename()
 
 // `IDupdate()` checks `aText` removing all `#hashtags` and `@mentions`
@@ -415,27 +730,95 @@ func (ht *THashTags) IDrename(aOldID, aNewID int64) bool {
 //
 // Returns:
 //   - `bool`: `true` if `aID` was updated, or `false` otherwise.
-func (ht *THashTags) IDupdate(aID int64, aText []byte) bool {
-	if aText = bytes.TrimSpace(aText); 0 == len(aText) {
-		return ht.IDremove(aID)
+func (ht *TIndex[ID]) IDupdate(aID ID, aText []byte) bool {
+	diff := ht.IDupdateDiff(aID, aText)
+
+	return (0 < len(diff.Added)) || (0 < len(diff.Removed))
+} // IDupdate()
+
+// `IDupdateDiff()` works like [IDupdate] but returns the changes
+// applied.
+//
+// Only the tags actually added or removed are touched, i.e. the tags
+// found both in the list and in `aText` are kept as they are.
+//
+// Parameters:
+//   - `aID`: The ID to update.
+//   - `aText`: The new text to use.
+//
+// Returns:
+//   - `TDiff`: The tags added and removed.
+func (ht *TIndex[ID]) IDupdateDiff(aID ID, aText []byte) TDiff {
+	if ht.readOnly() {
+		return TDiff{}
 	}
 
 	if ht.safe {
 		ht.mtx.Lock()
 		defer ht.mtx.Unlock()
 	}
-	defer ht.deferredStore()
+	defer ht.deferredStore()()
 
-	rr := ht.hm.removeID(aID)
-	rp := ht.parseID(aID, aText)
+	diff, tags := ht.idDiff(aID, aText)
+	ht.update(aID, diff, tags)
 
-	if rr || rp {
-		atomic.StoreUint32(&ht.changed, 0)
-		return true
+	return diff
+} // IDupdateDiff()
+
+// `update()` applies `aDiff` to the tags associated with `aID`.
+//
+// NOTE: This method expects the caller to hold the list's write lock.
+//
+// Parameters:
+//   - `aID`: The ID to update.
+//   - `aDiff`: The tags to add and remove.
+//   - `aTags`: All valid tags found in the new text.
+func (ht *TIndex[ID]) update(aID ID, aDiff TDiff, aTags []TTag) {
+	var (
+		diff TIndexDiff[ID]
+		undo *tExtras[ID]
+	)
+	if 0 < ht.hs.size {
+		diff = ht.hm.updateDiff(aDiff, aID)
+		undo = ht.xt.saved(diff, false)
 	}
 
-	return false
-} // IDupdate()
+	for _, key := range aDiff.Removed {
+		if !ht.hm.removeHM(key[0], key, aID) {
+			continue
+		}
+		ht.xt.dropPair(key, aID)
+		if 0 > ht.hm.idxLen(key[0], key) {
+			// the tag's last ID was removed
+			ht.xt.Info.drop(key)
+		} else {
+			ht.xt.Info.touch(key)
+		}
+	}
+	for _, tag := range aTags {
+		if _, ok := slices.BinarySearch(aDiff.Added, tag.Key); ok {
+			ht.xt.Info.note(tag.Text)
+			if ht.hm.insert(tag.Text, aID) {
+				ht.stampPair(tag.Key, aID, time.Time{})
+			}
+		}
+	}
+
+	ht.xt.Pos.dropID(aID)
+	if ht.pos {
+		for _, tag := range aTags {
+			offsets, _ := ht.xt.Pos.get(tag.Key, aID)
+			ht.xt.Pos.set(tag.Key, aID, append(offsets, tag.Start))
+		}
+	}
+
+	if (0 < len(aDiff.Added)) || (0 < len(aDiff.Removed)) {
+		if 0 < ht.hs.size {
+			ht.record(HistoryUpdate, diff, undo, ht.xt.saved(diff, true))
+		}
+		ht.invalidate()
+	}
+} // update()
 
 // `insert()` appends `aID` to the list associated with `aName`.
 //
@@ -446,10 +829,11 @@ func (ht *THashTags) IDupdate(aID int64, aText []byte) bool {
 //   - `aDelim`: The start character of words to use (i.e. either '@' or '#').
 //   - `aName`: The `#hashtag` or `@mention` to lookup.
 //   - `aID`: The referencing object to be added to the hash list.
+//   - `aTime`: The time of the association (zero value: now).
 //
 // Returns:
 //   - `bool`: `true` if `aID` was added, or `false` otherwise.
-func (ht *THashTags) insert(aDelim byte, aName string, aID int64) bool {
+func (ht *TIndex[ID]) insert(aDelim byte, aName string, aID ID, aTime time.Time) bool {
 	if aName = strings.TrimSpace(aName); "" == aName {
 		return false
 	}
@@ -457,22 +841,41 @@ func (ht *THashTags) insert(aDelim byte, aName string, aID int64) bool {
 	if aName[0] != aDelim {
 		aName = string(aDelim) + aName
 	}
-	defer ht.deferredStore()
+	if !ht.vr.isValid(normalise(aName)) {
+		return false
+	}
+	ht.xt.Info.note(aName)
+	key := normalise(aName)
+	isNew := !ht.hm.has(key)
 
 	if ht.hm.insert(aName, aID) {
-		atomic.StoreUint32(&ht.changed, 0)
+		ht.stampPair(key, aID, aTime)
+		if 0 < ht.hs.size {
+			diff := pairDiff(key, aID, true, isNew)
+			ht.record(HistoryInsert, diff, nil, ht.xt.saved(diff, true))
+		}
+		ht.invalidate()
 		return true
 	}
 
 	return false
 } // insert()
 
+// `invalidate()` marks the list's data as changed, dropping the
+// cached sorted tags.
+//
+// NOTE: This method expects the caller to hold the list's write lock.
+func (ht *TIndex[ID]) invalidate() {
+	ht.cc.keys.Store(nil)
+	atomic.StoreUint32(&ht.changed, 0)
+} // invalidate()
+
 // `Len()` returns the current length of the list i.e. how many
 // `#hashtags` and `@mentions` are currently stored in the list.
 //
 // Returns:
 //   - `int`: The number of all `#hashtag` and `@mention` lists.
-func (ht *THashTags) Len() int {
+func (ht *TIndex[ID]) Len() int {
 	if ht.safe {
 		ht.mtx.RLock()
 		defer ht.mtx.RUnlock()
@@ -486,7 +889,7 @@ func (ht *THashTags) Len() int {
 //
 // Returns:
 //   - `int`: The total length of all `#hashtag` and `@mention` lists.
-func (ht *THashTags) LenTotal() int {
+func (ht *TIndex[ID]) LenTotal() int {
 	if ht.safe {
 		ht.mtx.RLock()
 		defer ht.mtx.RUnlock()
@@ -500,12 +903,11 @@ func (ht *THashTags) LenTotal() int {
 //
 // Returns:
 //   - `TCountList`: A list of `#hashtags` and `@mentions` with their counts of IDs.
-func (ht *THashTags) List() TCountList {
+func (ht *TIndex[ID]) List() TCountList {
 	if ht.safe {
 		ht.mtx.RLock()
 		defer ht.mtx.RUnlock()
 	}
-	defer ht.deferredStore()
 
 	currentCRC := ht.hm.checksum()
 	if (0 < len(ht.cc.cl)) && (currentCRC == ht.cc.crc) {
@@ -516,6 +918,9 @@ func (ht *THashTags) List() TCountList {
 	atomic.StoreUint32(&ht.changed, currentCRC)
 	ht.cc.crc = atomic.LoadUint32(&ht.changed)
 	ht.cc.cl = ht.hm.countedList()
+	for idx, ci := range ht.cc.cl {
+		ht.cc.cl[idx] = ht.xt.Info.countItem(ci.Tag, ci.Count)
+	}
 
 	return ht.cc.cl
 } // List()
@@ -530,19 +935,29 @@ func (ht *THashTags) List() TCountList {
 // considered an error but keeps all data strictly in memory.
 //
 // Returns:
-//   - `*THashTags`: The updated list.
+//   - `*TIndex[ID]`: The updated list.
 //   - `error`: `nil` in case of success, otherwise an error.
-func (ht *THashTags) Load() (*THashTags, error) {
+func (ht *TIndex[ID]) Load() (*TIndex[ID], error) {
+	if nil != ht.root {
+		_, err := ht.root.Load()
+		return ht, err
+	}
+
 	if ht.safe {
 		ht.mtx.Lock()
 		defer ht.mtx.Unlock()
 	}
-	defer ht.deferredStore()
 
-	if _, err := ht.hm.load(ht.fn); nil != err {
+	var stamp tFileStamp
+	if _, err := ht.hm.loadFile(context.Background(),
+		ht.fn, ht.xt, LoadLenient, nil, &stamp); nil != err {
 		return ht, err
 	}
-	atomic.StoreUint32(&ht.changed, 0)
+	ht.rebindScopes()
+	ht.cc.cl = nil
+	ht.invalidate()
+	ht.clearHistory()
+	ht.synced(stamp)
 
 	return ht, nil
 } // Load()
@@ -558,7 +973,11 @@ func (ht *THashTags) Load() (*THashTags, error) {
 //
 // Returns:
 //   - `bool`: `true` if `aID` was added, or `false` otherwise.
-func (ht *THashTags) MentionAdd(aMention string, aID int64) bool {
+func (ht *TIndex[ID]) MentionAdd(aMention string, aID ID) bool {
+	if ht.readOnly() {
+		return false
+	}
+
 	if aMention = strings.TrimSpace(aMention); "" == aMention {
 		return false
 	}
@@ -567,15 +986,44 @@ func (ht *THashTags) MentionAdd(aMention string, aID int64) bool {
 		ht.mtx.Lock()
 		defer ht.mtx.Unlock()
 	}
+	defer ht.deferredStore()()
 
-	return ht.insert(MarkMention, aMention, aID)
+	return ht.insert(MarkMention, aMention, aID, time.Time{})
 } // MentionAdd()
 
+// `MentionAddAt()` works like [MentionAdd] but records `aTime` as the
+// time `aID` was associated with `aMention` (see [SetTimestamps]).
+//
+// Parameters:
+//   - `aMention`: The list index to lookup.
+//   - `aID`: The ID to be added to the hash list.
+//   - `aTime`: The time of the association (zero value: now).
+//
+// Returns:
+//   - `bool`: `true` if `aID` was added, or `false` otherwise.
+func (ht *TIndex[ID]) MentionAddAt(aMention string, aID ID, aTime time.Time) bool {
+	if ht.readOnly() {
+		return false
+	}
+
+	if aMention = strings.TrimSpace(aMention); "" == aMention {
+		return false
+	}
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+	defer ht.deferredStore()()
+
+	return ht.insert(MarkMention, aMention, aID, aTime)
+} // MentionAddAt()
+
 // `MentionCount()` returns the number of mentions in the list.
 //
 // Returns:
 //   - `int`: The number of mentions in the list.
-func (ht *THashTags) MentionCount() int {
+func (ht *TIndex[ID]) MentionCount() int {
 	if ht.safe {
 		ht.mtx.RLock()
 		defer ht.mtx.RUnlock()
@@ -594,7 +1042,7 @@ func (ht *THashTags) MentionCount() int {
 //
 // Returns:
 //   - `int`: The number of `aMention` in the list.
-func (ht *THashTags) MentionLen(aMention string) int {
+func (ht *TIndex[ID]) MentionLen(aMention string) int {
 	if aMention = strings.TrimSpace(aMention); "" == aMention {
 		return 0
 	}
@@ -609,6 +1057,9 @@ func (ht *THashTags) MentionLen(aMention string) int {
 
 // `MentionList()` returns a list of IDs associated with `aMention`.
 //
+// The result is a copy which the caller may modify at will; see
+// [TIndex.MentionView] for a read-only variant avoiding the copy.
+//
 // If `aMention` is empty it is silently ignored (i.e. this method
 // does nothing), returning an empty slice.
 //
@@ -616,10 +1067,10 @@ func (ht *THashTags) MentionLen(aMention string) int {
 //   - `aMention`: The mention to lookup.
 //
 // Returns:
-//   - `[]int64`: The number of references of `aMention`.
-func (ht *THashTags) MentionList(aMention string) []int64 {
+//   - `[]ID`: The number of references of `aMention`.
+func (ht *TIndex[ID]) MentionList(aMention string) []ID {
 	if aMention = strings.TrimSpace(aMention); "" == aMention {
-		return []int64{}
+		return []ID{}
 	}
 
 	if ht.safe {
@@ -641,7 +1092,11 @@ func (ht *THashTags) MentionList(aMention string) []int64 {
 //
 // Returns:
 //   - `bool`: `true` if `aID` was removed, or `false` otherwise.
-func (ht *THashTags) MentionRemove(aMention string, aID int64) bool {
+func (ht *TIndex[ID]) MentionRemove(aMention string, aID ID) bool {
+	if ht.readOnly() {
+		return false
+	}
+
 	if aMention = strings.TrimSpace(aMention); "" == aMention {
 		return false
 	}
@@ -650,10 +1105,43 @@ func (ht *THashTags) MentionRemove(aMention string, aID int64) bool {
 		ht.mtx.Lock()
 		defer ht.mtx.Unlock()
 	}
+	defer ht.deferredStore()()
 
 	return ht.removeHM(MarkMention, aMention, aID)
 } // MentionRemove()
 
+// `Occurrences()` returns the byte offsets of `aTag` in the text
+// last parsed for `aID`.
+//
+// The offsets are recorded only while the positional mode is enabled
+// (see [SetPositional]) and point to the tag's leading mark in the
+// text given to [IDparse] or [IDupdate]. The number of occurrences
+// is the length of the returned list. Tags without a leading mark
+// are considered `#hashtags`.
+//
+// Parameters:
+//   - `aTag`: The `#hashtag` or `@mention` to lookup.
+//   - `aID`: The ID to lookup.
+//
+// Returns:
+//   - `[]int`: The sorted byte offsets of `aTag` (or `nil`).
+func (ht *TIndex[ID]) Occurrences(aTag string, aID ID) []int {
+	if aTag = tagName(aTag); "" == aTag {
+		return nil
+	}
+
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	if offsets, ok := ht.xt.Pos.get(normalise(aTag), aID); ok {
+		return slices.Clone(offsets)
+	}
+
+	return nil
+} // Occurrences()
+
 // `parseID()` checks whether `aText` contains strings starting with
 // `[@|#]` and - if found - adds them to the respective lists with `aID`.
 //
@@ -663,77 +1151,85 @@ func (ht *THashTags) MentionRemove(aMention string, aID int64) bool {
 // Parameters:
 //   - `aID`: The ID to add to the list of hashes/mention.
 //   - `aText`: The text to parse for hashtags and mentions.
+//   - `aTime`: The time of the associations (zero value: now).
 //
 // Returns:
 //   - `rOK`: `true` if `aID` was updated from `aText`, or `false` otherwise.
-func (ht *THashTags) parseID(aID int64, aText []byte) (rOK bool) {
-	matches := htHashMentionRE.FindAllSubmatch(aText, -1)
-	if (nil == matches) || (0 == len(matches)) {
+func (ht *TIndex[ID]) parseID(aID ID, aText []byte, aTime time.Time) (rOK bool) {
+	matches := findTags(aText)
+	if 0 == len(matches) {
 		return
 	}
 
 	var (
-		match0 []byte
-		sub    [][]byte
-		tag    []byte
+		key     string
+		offsets map[string][]int
+		tag     string
 	)
-	for _, sub = range matches {
-		match0 = sub[0]
-		tag = sub[1]
-
-		if '_' == tag[len(tag)-1] {
-			// '_' can be both, part of the hashtag and italic
-			// markup so we must remove it if it's at the end:
-			tag = tag[:len(tag)-1]
+	if ht.pos {
+		offsets = make(map[string][]int, len(matches))
+	}
+	for _, match := range matches {
+		tag = string(aText[match.start:match.end])
+		if ht.insert(tag[0], tag, aID, aTime) {
+			rOK = true // at least one change
 		}
-		if MarkHash == tag[0] {
-			// `match0` is the match including prefix and postfix
-			switch match0[len(match0)-1] {
-			case '"':
-				// Double quote following a possible hashtag:
-				// most probably an URL#fragment, so check
-				// whether it's a quoted string:
-				if '"' != match0[0] {
-					continue // URL#fragment
-				}
-
-			case ')':
-				// This is a tricky one: It can either be a
-				// normal right round bracket or the end of
-				// a Markdown link. Here we assume that it's
-				// the latter one and ignore this match:
-				continue
-
-			case '-':
-				// A hyphen at the end of a hashtag:
-				// that's not part of an acceptable tag.
-				continue
-
-			case ';':
-				if htEntityRE.MatchString(string(match0)) {
-					// leave HTML entities as is
-					continue
-				}
-			} // switch
-
-			if htHyphenRE.MatchString(string(tag)) {
-				continue
+		if nil != offsets {
+			if key = normalise(tag); ht.vr.isValid(key) {
+				offsets[key] = append(offsets[key], match.start)
 			}
-		} else if MarkMention == tag[0] {
-			if '.' == match0[len(match0)-1] {
-				// we assume that it's an email address
-				continue
-			}
-		} // if
-
-		if ht.insert(tag[0], string(tag), aID) {
-			rOK = true // at least one change
 		}
 	} // for
 
-	return
+	for key, offs := range offsets {
+		ht.xt.Pos.set(key, aID, offs)
+	}
+
+	return
 } // parseID()
 
+// `Positional()` reports whether the positions of tags are recorded.
+//
+// Returns:
+//   - `bool`: `true` if the positional mode is enabled.
+func (ht *TIndex[ID]) Positional() bool {
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	return ht.pos
+} // Positional()
+
+// `Prune()` deletes all `#hashtags` and `@mentions` (along with their
+// IDs) which don't satisfy the current validation rules.
+//
+// This method is meant to clean up a list after the validation rules
+// were changed by [SetValidation].
+//
+// Returns:
+//   - `int`: The number of deleted tags.
+func (ht *TIndex[ID]) Prune() int {
+	if ht.readOnly() {
+		return 0
+	}
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+	defer ht.deferredStore()()
+
+	result := ht.hm.filter(ht.vr.isValid)
+	if 0 < result {
+		ht.xt.prune(*ht.hm)
+		ht.invalidate()
+		ht.hs.reset()
+	}
+
+	return result
+} // Prune()
+
 // `removeHM()` deletes `aID` from the list of `aName`.
 //
 // If `aName` is empty it is silently ignored (i.e. this method
@@ -746,21 +1242,246 @@ func (ht *THashTags) parseID(aID int64, aText []byte) (rOK bool) {
 //
 // Returns:
 //   - `bool`: `true` if `aID` was updated, or `false` otherwise.
-func (ht *THashTags) removeHM(aDelim byte, aName string, aID int64) bool {
+func (ht *TIndex[ID]) removeHM(aDelim byte, aName string, aID ID) bool {
 	if aName = strings.TrimSpace(aName); "" == aName {
 		return false
 	}
 
-	defer ht.deferredStore()
+	if aName[0] != aDelim {
+		aName = string(aDelim) + aName
+	}
+
+	var (
+		diff TIndexDiff[ID]
+		undo *tExtras[ID]
+	)
+	if 0 < ht.hs.size {
+		key := normalise(aName)
+		diff = pairDiff(key, aID, false, 1 == ht.hm.idxLen(aDelim, key))
+		undo = ht.xt.saved(diff, false)
+	}
 
 	if ht.hm.removeHM(aDelim, aName, aID) {
-		atomic.StoreUint32(&ht.changed, 0)
+		ht.xt.dropPair(aName, aID)
+		if 0 > ht.hm.idxLen(aDelim, aName) {
+			// the tag's last ID was removed
+			ht.xt.Info.drop(aName)
+		} else {
+			ht.xt.Info.touch(normalise(aName))
+		}
+		ht.record(HistoryRemove, diff, undo, nil)
+		ht.invalidate()
 		return true
 	}
 
 	return false
 } // removeHM()
 
+// `removeID()` deletes all `#hashtags` and `@mentions` associated
+// with `aID`.
+//
+// Parameters:
+//   - `aID`: The ID to be deleted from all lists.
+//
+// Returns:
+//   - `bool`: `true` if `aID` was removed, or `false` otherwise.
+func (ht *TIndex[ID]) removeID(aID ID) bool {
+	tags := ht.hm.idList(aID)
+	var (
+		diff TIndexDiff[ID]
+		undo *tExtras[ID]
+	)
+	if 0 < ht.hs.size {
+		diff = ht.hm.removalDiff(tags, aID)
+		undo = ht.xt.saved(diff, false)
+	}
+
+	if ht.hm.removeID(aID) {
+		for _, tag := range tags {
+			ht.xt.Info.touch(tag)
+		}
+		ht.xt.Info.prune(ht.hm.has)
+		ht.xt.dropID(aID)
+		ht.record(HistoryRemoveID, diff, undo, nil)
+		ht.invalidate()
+		return true
+	}
+
+	return false
+} // removeID()
+
+// `renameID()` replaces all occurrences of `aOldID` by `aNewID`.
+//
+// Parameters:
+//   - `aOldID`: The ID to be replaced in all lists.
+//   - `aNewID`: The replacement in all lists.
+//
+// Returns:
+//   - `bool`: `true` if `aOldID` was renamed, or `false` otherwise.
+func (ht *TIndex[ID]) renameID(aOldID, aNewID ID) bool {
+	var (
+		diff TIndexDiff[ID]
+		undo *tExtras[ID]
+	)
+	if 0 < ht.hs.size {
+		diff = ht.hm.renameDiff(aOldID, aNewID)
+		undo = ht.xt.saved(diff, false)
+	}
+
+	if ht.hm.renameID(aOldID, aNewID) {
+		ht.xt.renameID(aOldID, aNewID)
+		ht.record(HistoryRenameID, diff, undo, ht.xt.saved(diff, true))
+		ht.invalidate()
+		return true
+	}
+
+	return false
+} // renameID()
+
+// `Renormalise()` applies the current [Normalisation] setting to all
+// `#hashtags` and `@mentions` already stored in the list.
+//
+// This method is meant to migrate a list loaded from a file after
+// the [Normalisation] setting was changed. Tags which become equal
+// after normalisation are merged.
+//
+// Returns:
+//   - `bool`: `true` if at least one tag was changed, or `false` otherwise.
+func (ht *TIndex[ID]) Renormalise() bool {
+	if ht.readOnly() {
+		return false
+	}
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+	defer ht.deferredStore()()
+
+	result := ht.hm.renormalise()
+	if result {
+		ht.xt.renormalise()
+	}
+	if ht.xt.renormaliseScopes() {
+		result = true
+	}
+	if result {
+		ht.invalidate()
+		ht.clearHistory()
+	}
+
+	return result
+} // Renormalise()
+
+// `Retention()` returns the retention period of association times.
+//
+// Returns:
+//   - `time.Duration`: The current retention period (`0` = unlimited).
+func (ht *TIndex[ID]) Retention() time.Duration {
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	return ht.rt
+} // Retention()
+
+// `SetDescription()` sets a free-text description of `aTag`.
+//
+// The description is returned in the `Description` field of the
+// items returned by [List] and [TagItem]. An empty `aDescription`
+// removes the current description. Tags without a leading mark are
+// considered `#hashtags`.
+//
+// NOTE: The metadata of a tag is deleted when the tag's last ID
+// is removed.
+//
+// Parameters:
+//   - `aTag`: The `#hashtag` or `@mention` to update.
+//   - `aDescription`: The new description of `aTag`.
+//
+// Returns:
+//   - `bool`: `true` if the description was changed, or `false` otherwise.
+func (ht *TIndex[ID]) SetDescription(aTag, aDescription string) bool {
+	if ht.readOnly() {
+		return false
+	}
+
+	if aTag = tagName(aTag); "" == aTag {
+		return false
+	}
+	aDescription = strings.TrimSpace(aDescription)
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+
+	key := normalise(aTag)
+	if _, ok := (*ht.hm)[key]; !ok {
+		return false
+	}
+
+	ti := ht.xt.Info.info(key)
+	if ti.Description == aDescription {
+		return false
+	}
+	ti.Description = aDescription
+	ht.cc.cl = nil // invalidate `List()` cache
+
+	return true
+} // SetDescription()
+
+// `SetDisplayName()` sets the spelling used to display `aTag`.
+//
+// The new spelling must match `aTag` after normalisation (see
+// [Normalisation]), i.e. it may differ only in e.g. upper/lower case.
+// Tags without a leading mark are considered `#hashtags`.
+//
+// Parameters:
+//   - `aTag`: The `#hashtag` or `@mention` to update.
+//   - `aDisplay`: The new display spelling of `aTag`.
+//
+// Returns:
+//   - `bool`: `true` if the spelling was changed, or `false` otherwise.
+func (ht *TIndex[ID]) SetDisplayName(aTag, aDisplay string) bool {
+	if ht.readOnly() {
+		return false
+	}
+
+	if aTag = tagName(aTag); "" == aTag {
+		return false
+	}
+	if aDisplay = strings.TrimSpace(aDisplay); "" == aDisplay {
+		return false
+	}
+	if aDisplay[0] != aTag[0] {
+		aDisplay = string(aTag[0]) + aDisplay
+	}
+
+	key := normalise(aTag)
+	if normalise(aDisplay) != key {
+		return false
+	}
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+	if _, ok := (*ht.hm)[key]; !ok {
+		return false
+	}
+
+	ti := ht.xt.Info.info(key)
+	if ti.Display == aDisplay {
+		return false
+	}
+	ti.Display = aDisplay
+	ht.cc.cl = nil // invalidate `List()` cache
+
+	return true
+} // SetDisplayName()
+
 // `SetFilename()` sets `aFilename` to be used by this list.
 //
 // Parameters:
@@ -768,9 +1489,13 @@ func (ht *THashTags) removeHM(aDelim byte, aName string, aID int64) bool {
 //
 // Returns:
 //   - `error`: `nil` in case of success, otherwise an error.
-func (ht *THashTags) SetFilename(aFilename string) error {
+func (ht *TIndex[ID]) SetFilename(aFilename string) error {
 	if aFilename = strings.TrimSpace(aFilename); "" == aFilename {
-		return se.New(errors.New("empty filename not allowed"), 1)
+		return se.New(ErrEmptyFilename, 1)
+	}
+
+	if nil != ht.root {
+		return ht.root.SetFilename(aFilename)
 	}
 
 	// Check if directory exists and is writeable
@@ -783,34 +1508,205 @@ func (ht *THashTags) SetFilename(aFilename string) error {
 		ht.mtx.Lock()
 		defer ht.mtx.Unlock()
 	}
-	ht.fn = aFilename
+	if aFilename != ht.fn {
+		ht.fn = aFilename
+		ht.fst, ht.scrc = tFileStamp{}, 0
+	}
 
 	return nil
 } // SetFilename()
 
+// `SetPinned()` marks `aTag` as pinned (curated) or not.
+//
+// The flag is returned in the `Pinned` field of the items returned
+// by [List] and [TagItem]. Tags without a leading mark are considered
+// `#hashtags`.
+//
+// Parameters:
+//   - `aTag`: The `#hashtag` or `@mention` to update.
+//   - `aPinned`: Whether `aTag` is pinned.
+//
+// Returns:
+//   - `bool`: `true` if the flag was changed, or `false` otherwise.
+func (ht *TIndex[ID]) SetPinned(aTag string, aPinned bool) bool {
+	if ht.readOnly() {
+		return false
+	}
+
+	if aTag = tagName(aTag); "" == aTag {
+		return false
+	}
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+
+	key := normalise(aTag)
+	if _, ok := (*ht.hm)[key]; !ok {
+		return false
+	}
+
+	ti := ht.xt.Info.info(key)
+	if ti.Pinned == aPinned {
+		return false
+	}
+	ti.Pinned = aPinned
+	ht.cc.cl = nil // invalidate `List()` cache
+
+	return true
+} // SetPinned()
+
+// `SetPositional()` enables or disables recording the positions of
+// `#hashtags` and `@mentions` found by [IDparse] and [IDupdate].
+//
+// The recorded positions are available by [Occurrences] and stored
+// along with the list. Disabling the positional mode deletes all
+// positions recorded so far.
+//
+// Parameters:
+//   - `aPositional`: Whether to record the positions of tags.
+//
+// Returns:
+//   - `*TIndex[ID]`: The updated list.
+func (ht *TIndex[ID]) SetPositional(aPositional bool) *TIndex[ID] {
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+
+	if ht.pos = aPositional; !aPositional && (0 < len(ht.xt.Pos)) {
+		ht.xt.Pos.clear()
+		ht.invalidate()
+	}
+
+	return ht
+} // SetPositional()
+
+// `SetRetention()` sets the period for which association times are
+// kept (see [Expire]).
+//
+// Parameters:
+//   - `aRetention`: The retention period (`0` = unlimited).
+//
+// Returns:
+//   - `*TIndex[ID]`: The updated list.
+func (ht *TIndex[ID]) SetRetention(aRetention time.Duration) *TIndex[ID] {
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+	ht.rt = max(aRetention, 0)
+
+	return ht
+} // SetRetention()
+
+// `SetTimestamps()` enables or disables recording the time each ID
+// gets associated with a `#hashtag` or `@mention`.
+//
+// The times are used by [Trending] and stored along with the list.
+// Disabling this mode deletes all times recorded so far.
+//
+// Parameters:
+//   - `aTimestamps`: Whether to record the association times.
+//
+// Returns:
+//   - `*TIndex[ID]`: The updated list.
+func (ht *TIndex[ID]) SetTimestamps(aTimestamps bool) *TIndex[ID] {
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+
+	if ht.stamp = aTimestamps; !aTimestamps && (0 < len(ht.xt.Times)) {
+		ht.xt.Times.clear()
+		ht.invalidate()
+	}
+
+	return ht
+} // SetTimestamps()
+
+// `SetValidation()` sets the rules `#hashtags` and `@mentions` have to
+// satisfy to be added to the list.
+//
+// The rules are checked by [IDparse], [IDupdate], [HashAdd],
+// [MentionAdd], and [TagMerge]; tags already in the list are not
+// affected (see [Prune]). The zero value of [TValidation] accepts
+// all tags.
+//
+// NOTE: The allow- and deny-lists are normalised using the current
+// [Normalisation] setting.
+//
+// Parameters:
+//   - `aRules`: The validation rules to use.
+//
+// Returns:
+//   - `*TIndex[ID]`: The updated list.
+func (ht *TIndex[ID]) SetValidation(aRules TValidation) *TIndex[ID] {
+	vr := newValidator(aRules)
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+	ht.vr = vr
+
+	return ht
+} // SetValidation()
+
+// `stampPair()` records the change of the association of `aKey`
+// and `aID`.
+//
+// Parameters:
+//   - `aKey`: The normalised tag whose list of IDs was changed.
+//   - `aID`: The ID added to the list of `aKey`.
+//   - `aTime`: The time of the association (zero value: now).
+func (ht *TIndex[ID]) stampPair(aKey string, aID ID, aTime time.Time) {
+	ht.xt.Info.touch(aKey)
+	if ht.stamp {
+		if aTime.IsZero() {
+			aTime = time.Now()
+		}
+		ht.xt.Times.set(aKey, aID, aTime)
+	}
+} // stampPair()
+
 // `Store()` writes the whole list to the configured file
 // returning the number of bytes written and a possible error.
 //
 // The filename to use has to be given to the constructor [New] or
 // given with a call to [SetFilename].
 //
+// The file is written while holding an exclusive (advisory) file
+// lock. If the file was changed by another process since the list
+// last read or wrote it, the list's conflict policy applies (see
+// [TIndex.SetConflictPolicy]).
+//
 // Returns:
 //   - `int`: Number of bytes written to storage.
 //   - `error`: A possible storage error, or `nil` in case of success.
-func (ht *THashTags) Store() (int, error) {
+func (ht *TIndex[ID]) Store() (int, error) {
+	if nil != ht.root {
+		return ht.root.Store()
+	}
+	if ht.ro {
+		return 0, se.New(&TFileError{Kind: ErrReadOnly, Filename: ht.fn}, 1)
+	}
+	ht.Expire()
+
 	if ht.safe {
-		ht.mtx.RLock()
-		defer ht.mtx.RUnlock()
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
 	}
 
-	return ht.hm.store(ht.fn)
+	return ht.storeFile()
 } // Store()
 
 // `String()` returns the whole list as a linefeed separated string.
 //
 // Returns:
 //   - `string`: The string representation of this hash list.
-func (ht *THashTags) String() string {
+func (ht *TIndex[ID]) String() string {
 	if ht.safe {
 		ht.mtx.RLock()
 		defer ht.mtx.RUnlock()
@@ -819,4 +1715,132 @@ func (ht *THashTags) String() string {
 	return ht.hm.String()
 } // String()
 
+// `TagItem()` returns the data of a single `#hashtag` or `@mention`.
+//
+// Tags without a leading mark are considered `#hashtags`.
+//
+// Parameters:
+//   - `aTag`: The `#hashtag` or `@mention` to lookup.
+//
+// Returns:
+//   - `TCountItem`: The number of IDs and the metadata of `aTag`.
+//   - `bool`: `true` if `aTag` was found, or `false` otherwise.
+func (ht *TIndex[ID]) TagItem(aTag string) (TCountItem, bool) {
+	if aTag = tagName(aTag); "" == aTag {
+		return TCountItem{}, false
+	}
+
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	key := normalise(aTag)
+	sl, ok := (*ht.hm)[key]
+	if !ok {
+		return TCountItem{}, false
+	}
+
+	return ht.xt.Info.countItem(key, len(*sl)), true
+} // TagItem()
+
+// `TagMerge()` moves the IDs of all `aSources` tags to `aTarget`
+// and deletes the source tags afterwards.
+//
+// Tags without a leading mark are considered `#hashtags`. Merging
+// `#hashtags` into a `@mention` (and vice versa) is allowed.
+//
+// All sources are merged in one step while holding the list's lock,
+// so concurrent readers see either the old or the merged state.
+//
+// Parameters:
+//   - `aSources`: The tags whose IDs are to be moved.
+//   - `aTarget`: The tag to receive the IDs.
+//
+// Returns:
+//   - `bool`: `true` if at least one source tag was merged, or `false` otherwise.
+func (ht *TIndex[ID]) TagMerge(aSources []string, aTarget string) bool {
+	if ht.readOnly() {
+		return false
+	}
+
+	if aTarget = tagName(aTarget); ("" == aTarget) || (0 == len(aSources)) {
+		return false
+	}
+	if !ht.vr.isValid(normalise(aTarget)) {
+		return false
+	}
+
+	sources := make([]string, 0, len(aSources))
+	for _, source := range aSources {
+		if source = tagName(source); "" != source {
+			sources = append(sources, source)
+		}
+	}
+	if 0 == len(sources) {
+		return false
+	}
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+	defer ht.deferredStore()()
+
+	if ht.hm.mergeTags(sources, aTarget) {
+		ht.xt.merge(sources, aTarget)
+		ht.xt.Info.touch(normalise(aTarget))
+		ht.invalidate()
+		ht.hs.reset()
+		return true
+	}
+
+	return false
+} // TagMerge()
+
+// `TagRename()` replaces the tag `aOldTag` by `aNewTag`.
+//
+// Tags without a leading mark are considered `#hashtags`.
+// If `aNewTag` already exists, the IDs of both tags are merged.
+// If `aOldTag` equals `aNewTag`, or `aOldTag` doesn't exist then
+// they are silently ignored (i.e. this method does nothing),
+// returning `false`.
+//
+// Parameters:
+//   - `aOldTag`: The tag to be replaced.
+//   - `aNewTag`: The replacement tag.
+//
+// Returns:
+//   - `bool`: `true` if `aOldTag` was renamed, or `false` otherwise.
+func (ht *TIndex[ID]) TagRename(aOldTag, aNewTag string) bool {
+	return ht.TagMerge([]string{aOldTag}, aNewTag)
+} // TagRename()
+
+// `Timestamps()` reports whether association times are recorded.
+//
+// Returns:
+//   - `bool`: `true` if the timestamp mode is enabled.
+func (ht *TIndex[ID]) Timestamps() bool {
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	return ht.stamp
+} // Timestamps()
+
+// `Validation()` returns the rules `#hashtags` and `@mentions` have
+// to satisfy to be added to the list.
+//
+// Returns:
+//   - `TValidation`: The current validation rules.
+func (ht *TIndex[ID]) Validation() TValidation {
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	return ht.vr.ruleSet()
+} // Validation()
+
 /* EoF */
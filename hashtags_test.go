@@ -10,8 +10,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"testing"
+	"time"
 )
 
 //lint:file-ignore ST1017 - I prefer Yoda conditions
@@ -22,7 +24,7 @@ func prepHT() *THashTags {
 
 	ht, _ := New(fn)
 	ht.safe = false // no locking wanted while testing
-	(*ht.hm) = make(tHashMap, baseListLen*2)
+	(*ht.hm) = make(tHashMap[int64], baseListLen*2)
 	for j := range baseListLen {
 		h, m := "#hash"+strconv.Itoa(j), "@mention"+strconv.Itoa(j)
 		ht.hm.insert(h, int64(j*11))
@@ -64,6 +66,78 @@ func Test_New(t *testing.T) {
 	}
 } // Test_New()
 
+func Test_THashTags_DisplayName(t *testing.T) {
+	ht := prepHT()
+	ht.IDparse(1, []byte("Working on #OpenSource with @MWat"))
+	ht.IDparse(2, []byte("More #opensource stuff"))
+	ht.HashAdd("GoLang", 3)
+
+	tests := []struct {
+		name string
+		tag  string
+		want string
+	}{
+		{"empty", " ", ""},
+		{"unknown", "#unknown", ""},
+		{"first spelling", "#OPENSOURCE", "#OpenSource"},
+		{"mention", "@mwat", "@MWat"},
+		{"no mark", "golang", "#GoLang"},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ht.DisplayName(tt.tag); got != tt.want {
+				t.Errorf("%q: THashTags.DisplayName() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+
+	for _, ci := range ht.List() {
+		if "#opensource" == ci.Tag && "#OpenSource" != ci.Display {
+			t.Errorf("THashTags.List() display = %q, want %q",
+				ci.Display, "#OpenSource")
+		}
+	}
+} // Test_THashTags_DisplayName()
+
+func Test_THashTags_IDdiff(t *testing.T) {
+	ht := prepHT()
+	ht.IDparse(1, []byte("Hello @World, this is #Go and #test"))
+
+	tests := []struct {
+		name string
+		id   int64
+		text string
+		want TDiff
+	}{
+		{"unchanged", 1, "#go #test @world", TDiff{}},
+		{"added", 1, "#go #test @world #new", TDiff{Added: []string{"#new"}}},
+		{"removed", 1, "#go", TDiff{Removed: []string{"#test", "@world"}}},
+		{"both", 1, "#GO @bob", TDiff{Added: []string{"@bob"}, Removed: []string{"#test", "@world"}}},
+		{"empty", 1, "", TDiff{Removed: []string{"#go", "#test", "@world"}}},
+		{"new ID", 2, "#go", TDiff{Added: []string{"#go"}}},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ht.IDdiff(tt.id, []byte(tt.text))
+			if !slices.Equal(got.Added, tt.want.Added) ||
+				!slices.Equal(got.Removed, tt.want.Removed) {
+				t.Errorf("%q: THashTags.IDdiff() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+
+	// `IDdiff()` must not change the list
+	if got := ht.IDlist(1); !slices.Equal(got, []string{"#go", "#test", "@world"}) {
+		t.Errorf("THashTags.IDdiff() changed the list: %v", got)
+	}
+} // Test_THashTags_IDdiff()
+
 func Test_THashTags_IDparse(t *testing.T) {
 	ht := prepHT()
 
@@ -174,6 +248,36 @@ func Test_THashTags_IDupdate(t *testing.T) {
 	}
 } // Test_THashTags_IDupdate()
 
+func Test_THashTags_IDupdateDiff(t *testing.T) {
+	ht := prepHT()
+	ht.SetPositional(true)
+	ht.IDparse(1, []byte("Hello @World, this is #Go"))
+	ht.IDparse(2, []byte("More #Go"))
+
+	diff := ht.IDupdateDiff(1, []byte("#go and #new #go"))
+	if !slices.Equal(diff.Added, []string{"#new"}) ||
+		!slices.Equal(diff.Removed, []string{"@world"}) {
+		t.Errorf("THashTags.IDupdateDiff() = %v", diff)
+	}
+	if got := ht.IDlist(1); !slices.Equal(got, []string{"#go", "#new"}) {
+		t.Errorf("THashTags.IDupdateDiff() list = %v", got)
+	}
+	if got := ht.MentionLen("@world"); -1 != got {
+		t.Errorf("THashTags.IDupdateDiff() kept @world: %d", got)
+	}
+	if got := ht.HashLen("#go"); 2 != got {
+		t.Errorf("THashTags.IDupdateDiff() #go count = %d, want 2", got)
+	}
+	if got := ht.Occurrences("#go", 1); !slices.Equal(got, []int{0, 13}) {
+		t.Errorf("THashTags.IDupdateDiff() offsets = %v, want [0 13]", got)
+	}
+
+	diff = ht.IDupdateDiff(1, []byte("#go and #new #go"))
+	if (0 != len(diff.Added)) || (0 != len(diff.Removed)) {
+		t.Errorf("THashTags.IDupdateDiff() unchanged = %v", diff)
+	}
+} // Test_THashTags_IDupdateDiff()
+
 func Test_THashTags_List(t *testing.T) {
 	ht := prepHT() // a list with 128 entries
 
@@ -283,6 +387,81 @@ func Test_THashTags_Load(t *testing.T) {
 	}
 } // Test_THashTags_Load()
 
+func Test_THashTags_Occurrences(t *testing.T) {
+	saveBinary := UseBinaryStorage
+	defer func() {
+		UseBinaryStorage = saveBinary
+	}()
+	UseBinaryStorage = false
+
+	ht := prepHT()
+	ht.IDparse(1, []byte("#Go is fun; #go is fast"))
+	if got := ht.Occurrences("#go", 1); nil != got {
+		t.Errorf("THashTags.Occurrences() without positional mode = %v, want nil", got)
+	}
+
+	ht.SetPositional(true)
+	ht.IDparse(1, []byte("  #Go is fun; #go is fast, ask @Bob"))
+	ht.IDparse(2, []byte("Learning #Go"))
+
+	tests := []struct {
+		name string
+		tag  string
+		id   int64
+		want []int
+	}{
+		{"1", "#go", 1, []int{2, 14}},
+		{"2", "GO", 1, []int{2, 14}},
+		{"3", "@bob", 1, []int{31}},
+		{"4", "#go", 2, []int{9}},
+		{"5", "#go", 3, nil},
+		{"6", "#unknown", 1, nil},
+		{"7", "", 1, nil},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ht.Occurrences(tt.tag, tt.id); !slices.Equal(got, tt.want) {
+				t.Errorf("%q: THashTags.Occurrences() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+
+	// positions must survive storing and reloading
+	if _, err := ht.Store(); nil != err {
+		t.Errorf("THashTags.Store() error = %v", err)
+		return
+	}
+	ht2, err := New(ht.Filename())
+	if nil != err {
+		t.Errorf("New() error = %v", err)
+		return
+	}
+	if got := ht2.Occurrences("#go", 1); !slices.Equal(got, []int{2, 14}) {
+		t.Errorf("THashTags.Occurrences() after loading = %v", got)
+	}
+
+	ht.IDupdate(1, []byte("only #go"))
+	if got := ht.Occurrences("#go", 1); !slices.Equal(got, []int{5}) {
+		t.Errorf("THashTags.Occurrences() after IDupdate = %v", got)
+	}
+	if got := ht.Occurrences("@bob", 1); nil != got {
+		t.Errorf("THashTags.Occurrences() kept stale positions: %v", got)
+	}
+
+	ht.IDrename(2, 22)
+	if got := ht.Occurrences("#go", 22); !slices.Equal(got, []int{9}) {
+		t.Errorf("THashTags.Occurrences() after IDrename = %v", got)
+	}
+
+	ht.SetPositional(false)
+	if got := ht.Occurrences("#go", 22); nil != got {
+		t.Errorf("THashTags.Occurrences() after disabling = %v", got)
+	}
+} // Test_THashTags_Occurrences()
+
 func Test_THashTags_parseID(t *testing.T) {
 	hash1, hash2, hash3, hash4 := "#HÄSCH1", "#hash2", "#hash3", "#hash4"
 	hyphTx1, hyphTx2, hyphTx3 := `#--------------`, `#---text ---`, `#-text-`
@@ -353,7 +532,7 @@ func Test_THashTags_parseID(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.ht.parseID(tt.args.aID, tt.args.aText); got != tt.want {
+			if got := tt.ht.parseID(tt.args.aID, tt.args.aText, time.Time{}); got != tt.want {
 				t.Errorf("%q: THashTags.parseID() = \n%v\n>>>> want >>>>\n%v\n{%s}",
 					tt.name, got, tt.want, tt.ht)
 			}
@@ -361,6 +540,29 @@ func Test_THashTags_parseID(t *testing.T) {
 	}
 } // Test_THashList_parseID()
 
+func Test_THashTags_Prune(t *testing.T) {
+	ht := prepHT()
+	ht.IDparse(1, []byte("Released in #2024 with #Go and #fff colours"))
+
+	if got := ht.Prune(); 0 != got {
+		t.Errorf("THashTags.Prune() without rules = %d, want 0", got)
+	}
+
+	ht.SetValidation(TValidation{
+		NoNumeric: true,
+		Deny:      []string{"fff"},
+	})
+	if got := ht.Prune(); 2 != got {
+		t.Errorf("THashTags.Prune() = %d, want 2", got)
+	}
+	if got := ht.HashLen("#go"); 1 != got {
+		t.Errorf("THashTags.Prune() removed valid tag: %d", got)
+	}
+	if got := ht.HashLen("#2024"); -1 != got {
+		t.Errorf("THashTags.Prune() kept invalid tag: %d", got)
+	}
+} // Test_THashTags_Prune()
+
 func Test_THashTags_removeHM(t *testing.T) {
 	ht := prepHT()
 
@@ -399,6 +601,182 @@ func Test_THashTags_removeHM(t *testing.T) {
 	}
 } // Test_THashTags_removeHM()
 
+func Test_THashTags_Renormalise(t *testing.T) {
+	saveNorm := Normalisation()
+	defer func() {
+		SetNormalisation(saveNorm)
+	}()
+
+	ht := prepHT()
+	ht.IDparse(1, []byte("Ein #Café in der #Straße"))
+	ht.IDparse(2, []byte("Ein #cafe\u0301 in der #STRASSE"))
+
+	if got := ht.HashList("#CAFÉ"); 2 != len(got) {
+		t.Errorf("THashTags.IDparse() NFD list = %v, want 2 IDs", got)
+	}
+	if ht.Renormalise() {
+		t.Error("THashTags.Renormalise() = true, want false")
+	}
+
+	norm := saveNorm
+	norm.CaseFolding = FoldFull
+	SetNormalisation(norm)
+	if !ht.Renormalise() {
+		t.Error("THashTags.Renormalise() = false, want true")
+	}
+	if got := ht.HashList("#Straße"); 2 != len(got) {
+		t.Errorf("THashTags.Renormalise() list = %v, want 2 IDs", got)
+	}
+} // Test_THashTags_Renormalise()
+
+func Test_THashTags_SetDescription(t *testing.T) {
+	ht := prepHT()
+	ht.IDparse(1, []byte("Working on #GoLang"))
+
+	tests := []struct {
+		name string
+		tag  string
+		desc string
+		want bool
+	}{
+		{"empty tag", "", "text", false},
+		{"unknown", "#unknown", "text", false},
+		{"valid", "golang", " The Go language ", true},
+		{"unchanged", "#GoLang", "The Go language", false},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ht.SetDescription(tt.tag, tt.desc); got != tt.want {
+				t.Errorf("%q: THashTags.SetDescription() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+
+	for _, item := range ht.List() {
+		if ("#golang" == item.Tag) && ("The Go language" != item.Description) {
+			t.Errorf("THashTags.List() description = %q", item.Description)
+		}
+	}
+} // Test_THashTags_SetDescription()
+
+func Test_THashTags_SetDisplayName(t *testing.T) {
+	saveBinary := UseBinaryStorage
+	defer func() {
+		UseBinaryStorage = saveBinary
+	}()
+	UseBinaryStorage = false
+
+	ht := prepHT()
+	ht.IDparse(1, []byte("Working on #opensource"))
+
+	tests := []struct {
+		name    string
+		tag     string
+		display string
+		want    bool
+	}{
+		{"empty", "", "#OpenSource", false},
+		{"unknown", "#unknown", "#Unknown", false},
+		{"mismatch", "#opensource", "#ClosedSource", false},
+		{"valid", "opensource", "OpenSource", true},
+		{"unchanged", "#opensource", "#OpenSource", false},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ht.SetDisplayName(tt.tag, tt.display); got != tt.want {
+				t.Errorf("%q: THashTags.SetDisplayName() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ht.Store(); nil != err {
+		t.Errorf("THashTags.Store() error = %v", err)
+		return
+	}
+	ht2, err := New(ht.Filename())
+	if nil != err {
+		t.Errorf("New() error = %v", err)
+		return
+	}
+	if got := ht2.DisplayName("#opensource"); "#OpenSource" != got {
+		t.Errorf("THashTags.DisplayName() after reload = %q, want %q",
+			got, "#OpenSource")
+	}
+} // Test_THashTags_SetDisplayName()
+
+func Test_THashTags_SetPinned(t *testing.T) {
+	ht := prepHT()
+	ht.IDparse(1, []byte("Working on #GoLang"))
+
+	tests := []struct {
+		name   string
+		tag    string
+		pinned bool
+		want   bool
+	}{
+		{"empty tag", "", true, false},
+		{"unknown", "#unknown", true, false},
+		{"unchanged", "#golang", false, false},
+		{"pin", "#golang", true, true},
+		{"again", "#golang", true, false},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ht.SetPinned(tt.tag, tt.pinned); got != tt.want {
+				t.Errorf("%q: THashTags.SetPinned() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_THashTags_SetPinned()
+
+func Test_THashTags_SetValidation(t *testing.T) {
+	ht := prepHT()
+	ht.SetValidation(TValidation{
+		MinLength:     2,
+		NoNumeric:     true,
+		RequireLetter: true,
+		Deny:          []string{"#fff", "@admin"},
+	})
+
+	tests := []struct {
+		name string
+		add  func() bool
+		want bool
+	}{
+		{"valid hash", func() bool { return ht.HashAdd("golang", 1) }, true},
+		{"numeric hash", func() bool { return ht.HashAdd("#1", 1) }, false},
+		{"short hash", func() bool { return ht.HashAdd("#a", 1) }, false},
+		{"denied hash", func() bool { return ht.HashAdd("#FFF", 1) }, false},
+		{"valid mention", func() bool { return ht.MentionAdd("@alice", 1) }, true},
+		{"denied mention", func() bool { return ht.MentionAdd("@Admin", 1) }, false},
+		{"parse invalid", func() bool { return ht.IDparse(2, []byte("#2024 #___ #fff")) }, false},
+		{"parse mixed", func() bool { return ht.IDparse(2, []byte("#2024 #Go")) }, true},
+		{"merge to invalid", func() bool { return ht.TagMerge([]string{"#go"}, "#999") }, false},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.add(); got != tt.want {
+				t.Errorf("%q: got %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	if got := ht.Validation(); 2 != len(got.Deny) || 2 != got.MinLength {
+		t.Errorf("THashTags.Validation() = %v", got)
+	}
+} // Test_THashTags_SetValidation()
+
 func Test_THashTags_SetFilename(t *testing.T) {
 	ht := prepHT()
 	tmpDir := t.TempDir() // Creates a temporary directory for testing
@@ -429,6 +807,133 @@ func Test_THashTags_SetFilename(t *testing.T) {
 	}
 } // Test_THashTags_SetFilename()
 
+func Test_THashTags_TagItem(t *testing.T) {
+	saveBinary := UseBinaryStorage
+	defer func() {
+		UseBinaryStorage = saveBinary
+	}()
+
+	for _, binary := range []bool{false, true} {
+		UseBinaryStorage = binary
+
+		ht := prepHT()
+		before := time.Now()
+		ht.IDparse(1, []byte("Working on #GoLang"))
+		item, ok := ht.TagItem("golang")
+		if !ok || (1 != item.Count) || ("#GoLang" != item.Display) {
+			t.Errorf("THashTags.TagItem() = %v, %v", item, ok)
+		}
+		if item.Created.Before(before) || !item.Modified.Equal(item.Created) {
+			t.Errorf("THashTags.TagItem() times = %v / %v",
+				item.Created, item.Modified)
+		}
+
+		ht.IDparse(2, []byte("More #golang"))
+		item2, _ := ht.TagItem("#golang")
+		if !item2.Created.Equal(item.Created) || item2.Modified.Before(item.Modified) {
+			t.Errorf("THashTags.TagItem() times after update = %v / %v",
+				item2.Created, item2.Modified)
+		}
+		ht.SetDescription("#golang", "The \"Go\" language\nand more")
+		ht.SetPinned("#golang", true)
+
+		if _, err := ht.Store(); nil != err {
+			t.Errorf("THashTags.Store() error = %v", err)
+			return
+		}
+		ht2, err := New(ht.Filename())
+		if nil != err {
+			t.Errorf("New() error = %v", err)
+			return
+		}
+		got, _ := ht2.TagItem("#golang")
+		want, _ := ht.TagItem("#golang")
+		if !got.Created.Equal(want.Created) || !got.Modified.Equal(want.Modified) ||
+			(got.Description != want.Description) || !got.Pinned ||
+			(got.Display != want.Display) {
+			t.Errorf("THashTags.TagItem() after loading (binary: %v) = %v, want %v",
+				binary, got, want)
+		}
+
+		if _, ok = ht.TagItem("#unknown"); ok {
+			t.Error("THashTags.TagItem() found unknown tag")
+		}
+	}
+} // Test_THashTags_TagItem()
+
+func Test_THashTags_TagMerge(t *testing.T) {
+	ht := prepHT()
+	ht.IDparse(1, []byte("This is a #Go and #golang text"))
+	ht.IDparse(2, []byte("Another #GoLang text"))
+	ht.IDparse(3, []byte("A text by @gopher"))
+
+	tests := []struct {
+		name     string
+		sources  []string
+		target   string
+		want     bool
+		wantList []int64
+	}{
+		{"empty target", []string{"golang"}, " ", false, nil},
+		{"no sources", nil, "go", false, []int64{1}},
+		{"unknown source", []string{"#rust"}, "go", false, []int64{1}},
+		{"merge hashtags", []string{"golang"}, "#go", true, []int64{1, 2}},
+		{"merge mention", []string{"@gopher", "#go"}, "gophers", true, []int64{1, 2, 3}},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ht.TagMerge(tt.sources, tt.target); got != tt.want {
+				t.Errorf("%q: THashTags.TagMerge() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+			if nil == tt.wantList {
+				return
+			}
+			if got := ht.HashList(tt.target); !slices.Equal(got, tt.wantList) {
+				t.Errorf("%q: THashTags.TagMerge() list = %v, want %v",
+					tt.name, got, tt.wantList)
+			}
+		})
+	}
+	if got := ht.HashLen("golang"); -1 != got {
+		t.Errorf("THashTags.TagMerge() left source tag with %d IDs", got)
+	}
+} // Test_THashTags_TagMerge()
+
+func Test_THashTags_TagRename(t *testing.T) {
+	ht := prepHT()
+	ht.IDparse(1, []byte("This is a #Golang text"))
+
+	tests := []struct {
+		name    string
+		oldTag  string
+		newTag  string
+		want    bool
+		wantLen int
+	}{
+		{"same tags", "#golang", "golang", false, 1},
+		{"unknown tag", "#rust", "#golang", false, -1},
+		{"valid rename", "golang", "#go", true, -1},
+		{"already renamed", "golang", "#go", false, -1},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ht.TagRename(tt.oldTag, tt.newTag); got != tt.want {
+				t.Errorf("%q: THashTags.TagRename() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+			if got := ht.HashLen(tt.oldTag); got != tt.wantLen {
+				t.Errorf("%q: THashTags.TagRename() old length = %d, want %d",
+					tt.name, got, tt.wantLen)
+			}
+		})
+	}
+} // Test_THashTags_TagRename()
+
 func funcHashMentionRE(aText string) int {
 	matches := htHashMentionRE.FindAllStringSubmatch(aText, -1)
 
@@ -471,4 +976,46 @@ func Test_htHashMentionRE(t *testing.T) {
 	}
 } // Test_htHashMentionRE()
 
+func Test_NewIndex_string(t *testing.T) {
+	saveBinary := UseBinaryStorage
+	defer func() {
+		UseBinaryStorage = saveBinary
+	}()
+
+	for _, binary := range []bool{false, true} {
+		UseBinaryStorage = binary
+		fn := filepath.Join(t.TempDir(), "slugs.db")
+
+		ix, err := NewIndex[string](fn, nil)
+		if nil != err {
+			t.Fatalf("NewIndex() error = %v", err)
+		}
+		ix.SetPositional(true).SetTimestamps(true)
+		ix.IDparse("posts/hello world", []byte("Hello #World and @Bob"))
+		ix.IDparse("posts/again", []byte("#world again"))
+		ix.Scope("blog").HashAdd("#scoped", "about")
+		if _, err = ix.Store(); nil != err {
+			t.Fatalf("TIndex.Store() error = %v", err)
+		}
+
+		got, err := NewIndex[string](fn, nil)
+		if nil != err {
+			t.Fatalf("NewIndex() error = %v", err)
+		}
+		want := []string{"posts/again", "posts/hello world"}
+		if ids := got.HashList("#world"); !slices.Equal(ids, want) {
+			t.Errorf("binary=%v: TIndex.HashList() = %q, want %q",
+				binary, ids, want)
+		}
+		if offs := got.Occurrences("#world", "posts/hello world"); !slices.Equal(offs, []int{6}) {
+			t.Errorf("binary=%v: TIndex.Occurrences() = %v, want [6]",
+				binary, offs)
+		}
+		if ids := got.Scope("blog").HashList("#scoped"); !slices.Equal(ids, []string{"about"}) {
+			t.Errorf("binary=%v: TIndex.Scope().HashList() = %q, want [about]",
+				binary, ids)
+		}
+	}
+} // Test_NewIndex_string()
+
 /* EoF */
@@ -0,0 +1,532 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"cmp"
+	"strings"
+	"time"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `THistoryOp` identifies the kind of a recorded modification.
+	THistoryOp uint8
+
+	// `THistoryEntry` describes a single modification recorded in
+	// a list's history (see [TIndex.SetHistory]).
+	THistoryEntry[ID cmp.Ordered] struct {
+		Op    THistoryOp     // the kind of modification
+		Actor string         // the caller's label (see [TIndex.SetActor])
+		Time  time.Time      // the time of the modification
+		Diff  TIndexDiff[ID] // the associations added and removed
+	}
+
+	// `tHistoryItem` is an entry of a list's history along with
+	// the additional data needed to undo or redo it.
+	tHistoryItem[ID cmp.Ordered] struct {
+		THistoryEntry[ID]
+		undo *tExtras[ID] // data of the removed tags and associations
+		redo *tExtras[ID] // data of the added tags and associations
+	}
+
+	// `tHistory` is the bounded list of a list's modifications.
+	tHistory[ID cmp.Ordered] struct {
+		done   []tHistoryItem[ID] // modifications to undo, oldest first
+		undone []tHistoryItem[ID] // modifications to redo, latest undone last
+		actor  string             // label of the recorded modifications
+		size   int                // maximal number of entries (0 = off)
+	}
+)
+
+const (
+	// `HistoryInsert` records an ID added to a tag.
+	HistoryInsert THistoryOp = iota
+
+	// `HistoryRemove` records an ID removed from a tag.
+	HistoryRemove
+
+	// `HistoryRemoveID` records an ID removed from all tags.
+	HistoryRemoveID
+
+	// `HistoryRenameID` records an ID replaced by another one.
+	HistoryRenameID
+
+	// `HistoryClear` records the removal of all tags.
+	HistoryClear
+
+	// `HistoryUpdate` records the tags of an ID replaced by the ones
+	// of a new text.
+	HistoryUpdate
+)
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `pairDiff()` returns the change of the association of `aKey`
+// and `aID`.
+//
+// Parameters:
+//   - `aKey`: The normalised tag.
+//   - `aID`: The ID added to or removed from `aKey`.
+//   - `aAdded`: Flag whether `aID` was added (or removed).
+//   - `aWhole`: Flag whether `aKey` itself was added (or removed).
+//
+// Returns:
+//   - `TIndexDiff[ID]`: The change of the association.
+func pairDiff[ID cmp.Ordered](aKey string, aID ID, aAdded, aWhole bool) TIndexDiff[ID] {
+	var (
+		result TIndexDiff[ID]
+		tags   []string
+	)
+	if aWhole {
+		tags = []string{aKey}
+	}
+
+	if aAdded {
+		result.AddedTags = tags
+		result.Tags = []TTagDiff[ID]{{Tag: aKey, Added: []ID{aID}}}
+	} else {
+		result.RemovedTags = tags
+		result.Tags = []TTagDiff[ID]{{Tag: aKey, Removed: []ID{aID}}}
+	}
+
+	return result
+} // pairDiff()
+
+// -------------------------------------------------------------------------
+// methods of `THistoryOp`:
+
+// `String()` implements the `fmt.Stringer` interface returning the
+// modification's name.
+//
+// Returns:
+//   - `string`: The name of the modification.
+func (op THistoryOp) String() string {
+	switch op {
+	case HistoryInsert:
+		return "insert"
+	case HistoryRemove:
+		return "remove"
+	case HistoryRemoveID:
+		return "removeID"
+	case HistoryRenameID:
+		return "renameID"
+	case HistoryClear:
+		return "clear"
+	case HistoryUpdate:
+		return "update"
+	}
+
+	return "unknown"
+} // String()
+
+// -------------------------------------------------------------------------
+// methods of `tExtras`:
+
+// `saved()` returns copies of the data of the tags and associations
+// added (or removed) by `aDiff`.
+//
+// Parameters:
+//   - `aDiff`: The changes whose data is to be copied.
+//   - `aAdded`: Flag whether to copy the data of the added (or removed) items.
+//
+// Returns:
+//   - `*tExtras[ID]`: The copied data, or `nil` if `aDiff` is empty.
+func (xt *tExtras[ID]) saved(aDiff TIndexDiff[ID], aAdded bool) *tExtras[ID] {
+	if aDiff.Empty() {
+		return nil
+	}
+
+	result := newExtras[ID]()
+	tags := aDiff.RemovedTags
+	if aAdded {
+		tags = aDiff.AddedTags
+	}
+	for _, key := range tags {
+		if ti, ok := xt.Info[key]; ok {
+			info := *ti
+			result.Info[key] = &info
+		}
+	}
+
+	for _, td := range aDiff.Tags {
+		ids := td.Removed
+		if aAdded {
+			ids = td.Added
+		}
+		for _, id := range ids {
+			if offsets, ok := xt.Pos.get(td.Tag, id); ok {
+				result.Pos.set(td.Tag, id, offsets)
+			}
+			if at, ok := xt.Times.get(td.Tag, id); ok {
+				result.Times.set(td.Tag, id, at)
+			}
+		}
+	}
+
+	return result
+} // saved()
+
+// -------------------------------------------------------------------------
+// methods of `tHashMap`:
+
+// `removalDiff()` returns the changes of removing `aID` from `aTags`.
+//
+// Parameters:
+//   - `aTags`: The normalised tags associated with `aID`.
+//   - `aID`: The ID to be removed.
+//
+// Returns:
+//   - `TIndexDiff[ID]`: The changes of the removal.
+func (hm *tHashMap[ID]) removalDiff(aTags []string, aID ID) TIndexDiff[ID] {
+	var result TIndexDiff[ID]
+
+	for _, key := range aTags {
+		if sl, ok := (*hm)[key]; ok && (1 == len(*sl)) {
+			result.RemovedTags = append(result.RemovedTags, key)
+		}
+		result.Tags = append(result.Tags,
+			TTagDiff[ID]{Tag: key, Removed: []ID{aID}})
+	}
+
+	return result
+} // removalDiff()
+
+// `renameDiff()` returns the changes of replacing `aOldID` by `aNewID`.
+//
+// Parameters:
+//   - `aOldID`: The ID to be replaced.
+//   - `aNewID`: The replacement ID.
+//
+// Returns:
+//   - `TIndexDiff[ID]`: The changes of the replacement.
+func (hm *tHashMap[ID]) renameDiff(aOldID, aNewID ID) TIndexDiff[ID] {
+	var result TIndexDiff[ID]
+
+	for _, key := range hm.idList(aOldID) {
+		td := TTagDiff[ID]{Tag: key, Removed: []ID{aOldID}}
+		if 0 > (*hm)[key].findIndex(aNewID) {
+			td.Added = []ID{aNewID}
+		}
+		result.Tags = append(result.Tags, td)
+	}
+
+	return result
+} // renameDiff()
+
+// `updateDiff()` returns the changes of applying `aDiff` to the tags
+// associated with `aID`.
+//
+// Parameters:
+//   - `aDiff`: The normalised tags to add and remove.
+//   - `aID`: The ID to be updated.
+//
+// Returns:
+//   - `TIndexDiff[ID]`: The changes of the update.
+func (hm *tHashMap[ID]) updateDiff(aDiff TDiff, aID ID) TIndexDiff[ID] {
+	result := hm.removalDiff(aDiff.Removed, aID)
+
+	for _, key := range aDiff.Added {
+		if !hm.has(key) {
+			result.AddedTags = append(result.AddedTags, key)
+		}
+		result.Tags = append(result.Tags,
+			TTagDiff[ID]{Tag: key, Added: []ID{aID}})
+	}
+
+	return result
+} // updateDiff()
+
+// -------------------------------------------------------------------------
+// methods of `tHistory`:
+
+// `reset()` discards all recorded modifications.
+func (hs *tHistory[ID]) reset() {
+	hs.done, hs.undone = nil, nil
+} // reset()
+
+// -------------------------------------------------------------------------
+// methods of `TIndex`:
+
+// `Actor()` returns the label recorded with the list's modifications
+// (see [SetActor]).
+//
+// Returns:
+//   - `string`: The current actor's label.
+func (ht *TIndex[ID]) Actor() string {
+	if nil != ht.root {
+		return ht.root.Actor()
+	}
+
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	return ht.hs.actor
+} // Actor()
+
+// `clearHistory()` discards the histories of the list and of all
+// its scopes after their data were replaced.
+//
+// NOTE: This method expects the caller to hold the list's write lock.
+func (ht *TIndex[ID]) clearHistory() {
+	ht.hs.reset()
+	for _, child := range ht.sh {
+		child.hs.reset()
+	}
+} // clearHistory()
+
+// `History()` returns the latest modifications recorded in the list's
+// history, the most recent one first.
+//
+// Parameters:
+//   - `aCount`: The maximal number of entries to return (`0` = all).
+//
+// Returns:
+//   - `[]THistoryEntry[ID]`: The recorded modifications.
+func (ht *TIndex[ID]) History(aCount int) []THistoryEntry[ID] {
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	hLen := len(ht.hs.done)
+	if (0 >= aCount) || (aCount > hLen) {
+		aCount = hLen
+	}
+
+	result := make([]THistoryEntry[ID], 0, aCount)
+	for idx := hLen - 1; idx >= hLen-aCount; idx-- {
+		entry := ht.hs.done[idx].THistoryEntry
+		entry.Diff = entry.Diff.clone()
+		result = append(result, entry)
+	}
+
+	return result
+} // History()
+
+// `HistorySize()` returns the maximal number of modifications kept
+// in the list's history.
+//
+// Returns:
+//   - `int`: The history's size (`0` = disabled).
+func (ht *TIndex[ID]) HistorySize() int {
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	return ht.hs.size
+} // HistorySize()
+
+// `record()` adds a modification to the list's history (if enabled)
+// discarding all undone modifications.
+//
+// NOTE: This method expects the caller to hold the list's write lock.
+//
+// Parameters:
+//   - `aOp`: The kind of modification.
+//   - `aDiff`: The associations added and removed.
+//   - `aUndo`: The data to restore when undoing the modification.
+//   - `aRedo`: The data to restore when redoing the modification.
+func (ht *TIndex[ID]) record(aOp THistoryOp, aDiff TIndexDiff[ID], aUndo, aRedo *tExtras[ID]) {
+	if (0 == ht.hs.size) || aDiff.Empty() {
+		return
+	}
+
+	actor := ht.hs.actor
+	if nil != ht.root {
+		actor = ht.root.hs.actor
+	}
+	if len(ht.hs.done) == ht.hs.size {
+		clear(ht.hs.done[:1]) // release the oldest entry for GC
+		ht.hs.done = ht.hs.done[1:]
+	}
+	ht.hs.done = append(ht.hs.done, tHistoryItem[ID]{
+		THistoryEntry: THistoryEntry[ID]{
+			Op:    aOp,
+			Actor: actor,
+			Time:  time.Now(),
+			Diff:  aDiff,
+		},
+		undo: aUndo,
+		redo: aRedo,
+	})
+	ht.hs.undone = nil
+} // record()
+
+// `Redo()` re-applies the modification last reverted by [Undo].
+//
+// Any modification recorded after calling [Undo] discards all
+// modifications to redo.
+//
+// Returns:
+//   - `bool`: `true` if a modification was re-applied, or `false` otherwise.
+func (ht *TIndex[ID]) Redo() bool {
+	if ht.readOnly() {
+		return false
+	}
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+
+	uLen := len(ht.hs.undone)
+	if 0 == uLen {
+		return false
+	}
+	defer ht.deferredStore()()
+
+	item := ht.hs.undone[uLen-1]
+	ht.hs.undone = ht.hs.undone[:uLen-1]
+	if HistoryClear == item.Op {
+		ht.hm.clear()
+		ht.xt.clear()
+		ht.updated()
+	} else {
+		ht.replay(item.Diff, item.redo)
+	}
+	ht.hs.done = append(ht.hs.done, item)
+
+	return true
+} // Redo()
+
+// `replay()` applies `aDiff` restoring the data saved for the tags
+// and associations added.
+//
+// NOTE: This method expects the caller to hold the list's write lock.
+//
+// Parameters:
+//   - `aDiff`: The changes to apply.
+//   - `aSaved`: The saved data of the tags and associations to add.
+func (ht *TIndex[ID]) replay(aDiff TIndexDiff[ID], aSaved *tExtras[ID]) {
+	if nil != aSaved {
+		// the saved spelling takes precedence over the normalised tags
+		ht.xt.Info.union(aSaved.Info)
+	}
+	ht.applyDiff(aDiff)
+	if nil != aSaved {
+		ht.xt.union(aSaved)
+		ht.xt.prune(*ht.hm) // tags rejected by the validation rules
+	}
+	ht.updated()
+} // replay()
+
+// `SetActor()` sets the label recorded with all following
+// modifications in the list's history, e.g. the name of the user
+// changing the list.
+//
+// The label applies to the list and all its scopes. Programs serving
+// several users concurrently should use separate lists (or guard the
+// sequence of `SetActor()` and the modification by a lock of their own).
+//
+// Parameters:
+//   - `aActor`: The label to record.
+//
+// Returns:
+//   - `*TIndex[ID]`: The list itself, allowing for method chaining.
+func (ht *TIndex[ID]) SetActor(aActor string) *TIndex[ID] {
+	if nil != ht.root {
+		ht.root.SetActor(aActor)
+		return ht
+	}
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+	ht.hs.actor = strings.TrimSpace(aActor)
+
+	return ht
+} // SetActor()
+
+// `SetHistory()` sets the maximal number of modifications kept in the
+// list's history (`0` disables the history, the default).
+//
+// While enabled, the history records each `#hashtag` or `@mention`
+// added to or removed from an ID, each ID removed or renamed, each
+// update of an ID's tags by [IDupdate], [IDupdateDiff] or [Update],
+// and each call of [Clear]. Those modifications can then be reverted by
+// [Undo] and re-applied by [Redo]. The history is kept in memory
+// only, and a scope keeps a history of its own (see [Scope]).
+//
+// Bulk changes aren't recorded but discard the history since the
+// recorded modifications may no longer match the list's data: this
+// applies to [Load], [LoadContext], [LoadStrict], [Repair], [Reload]
+// (including the reloads by [Watch]), [Rebuild], [Merge], [Subtract],
+// [Intersect], [ApplyDiff], [TagMerge], [TagRename], [Prune],
+// [Renormalise], and [Store] adding external changes (see
+// [ConflictMerge]). Loading, reloading, merging, subtracting,
+// intersecting and renormalising discard the scopes' histories, too.
+//
+// Reducing the size discards the oldest entries.
+//
+// Parameters:
+//   - `aSize`: The maximal number of entries.
+//
+// Returns:
+//   - `*TIndex[ID]`: The list itself, allowing for method chaining.
+func (ht *TIndex[ID]) SetHistory(aSize int) *TIndex[ID] {
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+
+	if 0 >= aSize {
+		ht.hs.reset()
+		ht.hs.size = 0
+		return ht
+	}
+
+	if dLen := len(ht.hs.done); dLen > aSize {
+		ht.hs.done = append([]tHistoryItem[ID](nil), ht.hs.done[dLen-aSize:]...)
+	}
+	if uLen := len(ht.hs.undone); uLen > aSize {
+		ht.hs.undone = ht.hs.undone[uLen-aSize:]
+	}
+	ht.hs.size = aSize
+
+	return ht
+} // SetHistory()
+
+// `Undo()` reverts the latest modification recorded in the list's
+// history (see [SetHistory]).
+//
+// The associations removed by the modification are restored along
+// with their tags' metadata and association times.
+//
+// Returns:
+//   - `bool`: `true` if a modification was reverted, or `false` otherwise.
+func (ht *TIndex[ID]) Undo() bool {
+	if ht.readOnly() {
+		return false
+	}
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+
+	dLen := len(ht.hs.done)
+	if 0 == dLen {
+		return false
+	}
+	defer ht.deferredStore()()
+
+	item := ht.hs.done[dLen-1]
+	ht.hs.done = ht.hs.done[:dLen-1]
+	ht.replay(item.Diff.reverse(), item.undo)
+	ht.hs.undone = append(ht.hs.undone, item)
+
+	return true
+} // Undo()
+
+/* EoF */
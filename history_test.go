@@ -0,0 +1,232 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func Test_THashTags_Undo(t *testing.T) {
+	ht, _ := New("")
+	ht.SetHistory(10).SetTimestamps(true)
+	ht.IDparse(1, []byte("#Go and @bob"))
+	ht.IDparse(2, []byte("#go"))
+	ht.SetDescription("#go", "a language")
+	want := setList(nil)
+	want.Merge(ht)
+
+	ht.SetActor("moderator")
+	ht.HashRemove("#go", 1)
+	ht.HashRemove("#go", 2)
+	if -1 != ht.HashLen("#go") {
+		t.Fatalf("THashTags.HashRemove() = %v", ht.String())
+	}
+
+	if !ht.Undo() || !ht.Undo() {
+		t.Fatal("THashTags.Undo() = false, want true")
+	}
+	if !ht.hm.equals(*want.hm) {
+		t.Errorf("THashTags.Undo() =\n%v\n>>>> want >>>>\n%v", ht.String(), want.String())
+	}
+	item, _ := ht.TagItem("#go")
+	if ("#Go" != item.Display) || ("a language" != item.Description) {
+		t.Errorf("THashTags.Undo() #go = %+v", item)
+	}
+	if _, ok := ht.xt.Times.get("#go", 1); !ok {
+		t.Error("THashTags.Undo() didn't restore the association time")
+	}
+
+	// the undone removals can be redone
+	if !ht.Redo() || (1 != ht.HashLen("#go")) {
+		t.Errorf("THashTags.Redo() = %v", ht.String())
+	}
+	if !ht.Redo() || ht.Redo() || (-1 != ht.HashLen("#go")) {
+		t.Errorf("THashTags.Redo() = %v", ht.String())
+	}
+
+	// a new modification discards the modifications to redo
+	ht.Undo()
+	ht.MentionAdd("@alice", 3)
+	if ht.Redo() {
+		t.Error("THashTags.Redo() = true after a new modification")
+	}
+
+	ht.SetHistory(0)
+	if ht.Undo() || (0 != len(ht.History(0))) {
+		t.Error("THashTags.Undo() = true for a disabled history")
+	}
+} // Test_THashTags_Undo()
+
+func Test_THashTags_Undo_update(t *testing.T) {
+	ht, _ := New("")
+	ht.SetHistory(10)
+	ht.HashAdd("#a", 1)
+	ht.HashAdd("#c", 1)
+	ht.SetDescription("#a", "first")
+
+	if !ht.IDupdate(1, []byte("now #b only and #c")) {
+		t.Fatal("THashTags.IDupdate() = false, want true")
+	}
+	if got := ht.History(1); (1 != len(got)) || (HistoryUpdate != got[0].Op) {
+		t.Errorf("THashTags.History() = %v", got)
+	}
+
+	if !ht.Undo() {
+		t.Fatal("THashTags.Undo() = false, want true")
+	}
+	if (1 != ht.HashLen("#a")) || (-1 != ht.HashLen("#b")) || (1 != ht.HashLen("#c")) {
+		t.Errorf("THashTags.Undo() = %v", ht.String())
+	}
+	if item, _ := ht.TagItem("#a"); "first" != item.Description {
+		t.Errorf("THashTags.Undo() #a = %+v", item)
+	}
+
+	if !ht.Redo() {
+		t.Fatal("THashTags.Redo() = false, want true")
+	}
+	if got := ht.IDlist(1); !slices.Equal(got, []string{"#b", "#c"}) {
+		t.Errorf("THashTags.Redo() = %v", got)
+	}
+} // Test_THashTags_Undo_update()
+
+func Test_THashTags_Undo_bulk(t *testing.T) {
+	ht := setList(map[string][]int64{"#go": {1, 2, 3}, "#c": {1}, "#rust": {4}})
+	ht.SetHistory(10)
+	ht.Scope("blog").HashAdd("#go", 7)
+	want := setList(nil)
+	want.Merge(ht)
+
+	tests := []struct {
+		name   string
+		modify func() bool
+		op     THistoryOp
+	}{
+		{"removeID", func() bool { return ht.IDremove(1) }, HistoryRemoveID},
+		{"renameID", func() bool { return ht.IDrename(2, 3) }, HistoryRenameID},
+		{"renameID new", func() bool { return ht.IDrename(4, 5) }, HistoryRenameID},
+		{"clear", func() bool { return 0 == ht.Clear().Len() }, HistoryClear},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.modify() {
+				t.Fatalf("%s() = false, want true", tt.name)
+			}
+			if got := ht.History(1); (1 != len(got)) || (tt.op != got[0].Op) {
+				t.Errorf("THashTags.History() = %v, want %v", got, tt.op)
+			}
+			if !ht.Undo() {
+				t.Fatal("THashTags.Undo() = false, want true")
+			}
+			if !ht.hm.equals(*want.hm) {
+				t.Errorf("THashTags.Undo() =\n%v\n>>>> want >>>>\n%v", ht.String(), want.String())
+			}
+			if ids := ht.Scope("blog").HashList("#go"); !slices.Equal(ids, []int64{7}) {
+				t.Errorf("THashTags.Undo() scope = %v", ids)
+			}
+		})
+	}
+} // Test_THashTags_Undo_bulk()
+
+func Test_THashTags_History(t *testing.T) {
+	ht, _ := New("")
+	ht.SetHistory(2)
+	ht.SetActor(" alice ").HashAdd("#go", 1)
+	ht.SetActor("bob").HashAdd("#go", 2)
+	ht.HashAdd("#c", 2)
+
+	got := ht.History(0)
+	if 2 != len(got) {
+		t.Fatalf("THashTags.History() = %v", got)
+	}
+	// the oldest entry was discarded
+	if ("#c" != got[0].Diff.Tags[0].Tag) || ("#go" != got[1].Diff.Tags[0].Tag) {
+		t.Errorf("THashTags.History() = %v", got)
+	}
+	if ("bob" != got[0].Actor) || (HistoryInsert != got[0].Op) || got[0].Time.IsZero() {
+		t.Errorf("THashTags.History() = %+v", got[0])
+	}
+	if !slices.Equal(got[0].Diff.AddedTags, []string{"#c"}) || (0 < len(got[1].Diff.AddedTags)) {
+		t.Errorf("THashTags.History() = %v", got)
+	}
+	if 1 != len(ht.History(1)) {
+		t.Errorf("THashTags.History(1) = %v", ht.History(1))
+	}
+
+	// the returned entries are copies
+	got[0].Diff.Tags[0].Added[0] = 9
+	if ht.Undo(); 1 != ht.Len() {
+		t.Errorf("THashTags.Undo() = %v", ht.String())
+	}
+
+	ht.SetHistory(1)
+	if (1 != ht.HistorySize()) || (1 != len(ht.History(0))) || ("bob" != ht.Actor()) {
+		t.Errorf("THashTags.SetHistory() = %v", ht.History(0))
+	}
+} // Test_THashTags_History()
+
+func Test_THashTags_History_reset(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "history.db")
+	other := setList(map[string][]int64{"#go": {1, 5}, "#rust": {4}})
+	texts := sliceSource([]string{"#go", "#c"})
+
+	tests := []struct {
+		name   string
+		modify func(aList *THashTags) bool
+		scopes bool // flag whether the scopes' histories are discarded
+	}{
+		{"Load", func(aList *THashTags) bool { _, err := aList.Load(); return nil == err }, true},
+		{"LoadContext", func(aList *THashTags) bool { return nil == aList.LoadContext(context.Background()) }, true},
+		{"Reload", func(aList *THashTags) bool {
+			writer, _ := New(fn)
+			writer.HashAdd("#zig", 9)
+			_, _ = writer.Store()
+			ok, err := aList.Reload()
+			return ok && (nil == err)
+		}, true},
+		{"Rebuild", func(aList *THashTags) bool { return nil == aList.Rebuild(context.Background(), texts, 1) }, false},
+		{"Merge", func(aList *THashTags) bool { return aList.Merge(other) }, true},
+		{"Subtract", func(aList *THashTags) bool { return aList.Subtract(other) }, true},
+		{"Intersect", func(aList *THashTags) bool { return aList.Intersect(other) }, true},
+		{"ApplyDiff", func(aList *THashTags) bool { return aList.ApplyDiff(Diff(aList, other)) }, false},
+		{"TagMerge", func(aList *THashTags) bool { return aList.TagMerge([]string{"#c"}, "#go") }, false},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ht, _ := New(fn)
+			ht.HashAdd("#go", 1)
+			ht.HashAdd("#c", 2)
+			_, _ = ht.Store()
+			ht.SetHistory(10)
+			ht.HashAdd("#go", 2)
+			ht.HashRemove("#c", 2)
+			ht.Scope("blog").HashAdd("#go", 7)
+			ht.Undo()
+
+			if !tt.modify(ht) {
+				t.Fatalf("%s() = false, want true", tt.name)
+			}
+			if got := ht.History(0); (0 != len(got)) || ht.Undo() || ht.Redo() {
+				t.Errorf("%s() kept the history %v", tt.name, got)
+			}
+			if 10 != ht.HistorySize() {
+				t.Errorf("%s() changed the history's size", tt.name)
+			}
+			if got := ht.Scope("blog").History(0); tt.scopes == (0 < len(got)) {
+				t.Errorf("%s() scope history = %v", tt.name, got)
+			}
+		})
+	}
+} // Test_THashTags_History_reset()
+
+/* EoF */
@@ -0,0 +1,134 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"iter"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+// -------------------------------------------------------------------------
+// methods of `TIndex`:
+
+// NOTE: The iterators below hold the list's read lock while running,
+// i.e. the body of a `range` loop using them must neither modify the
+// list nor call any other of its methods (which might deadlock).
+// Break the loop or collect the data needed first.
+
+// `All()` returns an iterator over all `#hashtags` and `@mentions`
+// and their respective IDs.
+//
+// The tags are yielded in no particular order, each with its IDs in
+// ascending order. The slice of IDs must not be modified.
+//
+// Returns:
+//   - `iter.Seq2[string, []ID]`: The iterator over tags and IDs.
+func (ht *TIndex[ID]) All() iter.Seq2[string, []ID] {
+	return func(aYield func(string, []ID) bool) {
+		if ht.safe {
+			ht.mtx.RLock()
+			defer ht.mtx.RUnlock()
+		}
+
+		for tag, sl := range *ht.hm {
+			if !aYield(tag, *sl) {
+				return
+			}
+		}
+	}
+} // All()
+
+// `IDs()` returns an iterator over the IDs associated with `aTag`.
+//
+// The IDs are yielded in ascending order. Tags without a leading
+// mark are considered `#hashtags`.
+//
+// Parameters:
+//   - `aTag`: The `#hashtag` or `@mention` to lookup.
+//
+// Returns:
+//   - `iter.Seq[ID]`: The iterator over the tag's IDs.
+func (ht *TIndex[ID]) IDs(aTag string) iter.Seq[ID] {
+	key := normalise(tagName(aTag))
+
+	return func(aYield func(ID) bool) {
+		if "" == key {
+			return
+		}
+		if ht.safe {
+			ht.mtx.RLock()
+			defer ht.mtx.RUnlock()
+		}
+
+		sl, ok := (*ht.hm)[key]
+		if !ok {
+			return
+		}
+		for _, id := range *sl {
+			if !aYield(id) {
+				return
+			}
+		}
+	}
+} // IDs()
+
+// `Tags()` returns an iterator over all tags of kind `aKind`.
+//
+// The tags are yielded in no particular order.
+//
+// Parameters:
+//   - `aKind`: Either `MarkHash`, `MarkMention`, or `0` for both.
+//
+// Returns:
+//   - `iter.Seq[string]`: The iterator over the tags.
+func (ht *TIndex[ID]) Tags(aKind byte) iter.Seq[string] {
+	return func(aYield func(string) bool) {
+		if ht.safe {
+			ht.mtx.RLock()
+			defer ht.mtx.RUnlock()
+		}
+
+		for tag := range *ht.hm {
+			if (0 != aKind) && (tag[0] != aKind) {
+				continue
+			}
+			if !aYield(tag) {
+				return
+			}
+		}
+	}
+} // Tags()
+
+// `TagsOf()` returns an iterator over all tags associated with `aID`.
+//
+// The tags are yielded in no particular order.
+//
+// Parameters:
+//   - `aID`: The ID to lookup.
+//
+// Returns:
+//   - `iter.Seq[string]`: The iterator over the ID's tags.
+func (ht *TIndex[ID]) TagsOf(aID ID) iter.Seq[string] {
+	return func(aYield func(string) bool) {
+		if ht.safe {
+			ht.mtx.RLock()
+			defer ht.mtx.RUnlock()
+		}
+
+		for tag, sl := range *ht.hm {
+			if 0 > sl.findIndex(aID) {
+				continue
+			}
+			if !aYield(tag) {
+				return
+			}
+		}
+	}
+} // TagsOf()
+
+/* EoF */
@@ -0,0 +1,140 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"slices"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func prepIter() *THashTags {
+	ht, _ := New("")
+	ht.IDparse(1, []byte("#Go and @Bob"))
+	ht.IDparse(2, []byte("#go and #rust"))
+	ht.IDparse(3, []byte("@alice likes #rust"))
+
+	return ht
+} // prepIter()
+
+func Test_THashTags_All(t *testing.T) {
+	ht := prepIter()
+
+	got := make(map[string][]int64)
+	for tag, ids := range ht.All() {
+		got[tag] = slices.Clone(ids)
+	}
+	want := map[string][]int64{
+		"#go":    {1, 2},
+		"#rust":  {2, 3},
+		"@alice": {3},
+		"@bob":   {1},
+	}
+	if len(got) != len(want) {
+		t.Errorf("THashTags.All() = %v, want %v", got, want)
+	}
+	for tag, ids := range want {
+		if !slices.Equal(got[tag], ids) {
+			t.Errorf("THashTags.All()[%q] = %v, want %v", tag, got[tag], ids)
+		}
+	}
+
+	count := 0
+	for range ht.All() {
+		count++
+		break
+	}
+	if 1 != count {
+		t.Errorf("THashTags.All() didn't stop: %d", count)
+	}
+} // Test_THashTags_All()
+
+func Test_THashTags_IDs(t *testing.T) {
+	ht := prepIter()
+
+	tests := []struct {
+		name string
+		tag  string
+		want []int64
+	}{
+		{"hash", "#GO", []int64{1, 2}},
+		{"no mark", "rust", []int64{2, 3}},
+		{"mention", "@Bob", []int64{1}},
+		{"unknown", "#java", nil},
+		{"empty", "", nil},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slices.Collect(ht.IDs(tt.tag)); !slices.Equal(got, tt.want) {
+				t.Errorf("%q: THashTags.IDs() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+
+	var got []int64
+	for id := range ht.IDs("#rust") {
+		got = append(got, id)
+		break
+	}
+	if !slices.Equal(got, []int64{2}) {
+		t.Errorf("THashTags.IDs() didn't stop: %v", got)
+	}
+} // Test_THashTags_IDs()
+
+func Test_THashTags_Tags(t *testing.T) {
+	ht := prepIter()
+
+	tests := []struct {
+		name string
+		kind byte
+		want []string
+	}{
+		{"hash", MarkHash, []string{"#go", "#rust"}},
+		{"mention", MarkMention, []string{"@alice", "@bob"}},
+		{"all", 0, []string{"#go", "#rust", "@alice", "@bob"}},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slices.Sorted(ht.Tags(tt.kind)); !slices.Equal(got, tt.want) {
+				t.Errorf("%q: THashTags.Tags() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_THashTags_Tags()
+
+func Test_THashTags_TagsOf(t *testing.T) {
+	ht := prepIter()
+
+	tests := []struct {
+		name string
+		id   int64
+		want []string
+	}{
+		{"1", 1, []string{"#go", "@bob"}},
+		{"3", 3, []string{"#rust", "@alice"}},
+		{"unknown", 9, nil},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slices.Sorted(ht.TagsOf(tt.id)); !slices.Equal(got, tt.want) {
+				t.Errorf("%q: THashTags.TagsOf() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_THashTags_TagsOf()
+
+/* EoF */
@@ -0,0 +1,144 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"html"
+	"net/url"
+	"strings"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `TLinkFunc` returns the replacement of a `#hashtag` or `@mention`
+	// found in a text.
+	//
+	// The function is called with the tag's kind (i.e. either
+	// [MarkHash] or [MarkMention]) and the tag as found in the text
+	// including its leading mark.
+	TLinkFunc func(aKind byte, aTag string) []byte
+)
+
+// --------------------------------------------------------------------------
+// link functions:
+
+// `HTMLLinker()` returns a [TLinkFunc] producing HTML links.
+//
+// The link's target is the respective URL prefix followed by the
+// normalised tag without its leading mark; the link's text is the tag
+// as found in the text. If the URL prefix of a kind is empty, the tags
+// of that kind are left unchanged.
+//
+// Example:
+//
+//	html := Linkify(text, HTMLLinker("/tag/", "/user/"))
+//
+// turns `#GoLang` into `<a href="/tag/golang">#GoLang</a>`.
+//
+// Parameters:
+//   - `aHashURL`: The URL prefix to use for `#hashtags`.
+//   - `aMentionURL`: The URL prefix to use for `@mentions`.
+//
+// Returns:
+//   - `TLinkFunc`: The function to use with [Linkify].
+func HTMLLinker(aHashURL, aMentionURL string) TLinkFunc {
+	return func(aKind byte, aTag string) []byte {
+		href := linkTarget(aKind, aTag, aHashURL, aMentionURL)
+		if "" == href {
+			return []byte(aTag)
+		}
+
+		return []byte(`<a href="` + html.EscapeString(href) + `">` +
+			html.EscapeString(aTag) + `</a>`)
+	}
+} // HTMLLinker()
+
+// `linkTarget()` returns the URL for `aTag`.
+//
+// Parameters:
+//   - `aKind`: The tag's kind (i.e. either '#' or '@').
+//   - `aTag`: The tag as found in the text.
+//   - `aHashURL`: The URL prefix to use for `#hashtags`.
+//   - `aMentionURL`: The URL prefix to use for `@mentions`.
+//
+// Returns:
+//   - `string`: The URL to use (or an empty string).
+func linkTarget(aKind byte, aTag, aHashURL, aMentionURL string) string {
+	prefix := aHashURL
+	if MarkMention == aKind {
+		prefix = aMentionURL
+	}
+	if "" == prefix {
+		return ""
+	}
+
+	return prefix + url.PathEscape(normalise(aTag)[1:])
+} // linkTarget()
+
+// `Linkify()` returns a copy of `aText` with all `#hashtags` and
+// `@mentions` replaced by the result of calling `aLink`.
+//
+// The tags are identified by exactly the same rules used by
+// [THashTags.IDparse], i.e. URL fragments, HTML entities, email
+// addresses and the like are left alone. If `aLink` is `nil` an
+// unchanged copy of `aText` is returned.
+//
+// See [HTMLLinker] and [MarkdownLinker] for ready-made link functions.
+//
+// Parameters:
+//   - `aText`: The text to process.
+//   - `aLink`: The function returning the replacement for a tag.
+//
+// Returns:
+//   - `[]byte`: The processed text.
+func Linkify(aText []byte, aLink TLinkFunc) []byte {
+	if nil == aLink {
+		return Highlight(aText, nil)
+	}
+
+	return replaceTags(aText, func(aMatch tTagMatch) []byte {
+		return aLink(aText[aMatch.start], string(aText[aMatch.start:aMatch.end]))
+	})
+} // Linkify()
+
+// `MarkdownLinker()` returns a [TLinkFunc] producing Markdown links.
+//
+// The link's target is the respective URL prefix followed by the
+// normalised tag without its leading mark; the link's text is the tag
+// as found in the text. If the URL prefix of a kind is empty, the tags
+// of that kind are left unchanged.
+//
+// Example:
+//
+//	md := Linkify(text, MarkdownLinker("/tag/", "/user/"))
+//
+// turns `#GoLang` into `[#GoLang](/tag/golang)`.
+//
+// Parameters:
+//   - `aHashURL`: The URL prefix to use for `#hashtags`.
+//   - `aMentionURL`: The URL prefix to use for `@mentions`.
+//
+// Returns:
+//   - `TLinkFunc`: The function to use with [Linkify].
+func MarkdownLinker(aHashURL, aMentionURL string) TLinkFunc {
+	// escape characters with a special meaning in a link's text
+	escaper := strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`,
+		`_`, `\_`, `*`, `\*`)
+
+	return func(aKind byte, aTag string) []byte {
+		href := linkTarget(aKind, aTag, aHashURL, aMentionURL)
+		if "" == href {
+			return []byte(aTag)
+		}
+		href = strings.NewReplacer(`(`, `%28`, `)`, `%29`, ` `, `%20`).Replace(href)
+
+		return []byte(`[` + escaper.Replace(aTag) + `](` + href + `)`)
+	}
+} // MarkdownLinker()
+
+/* EoF */
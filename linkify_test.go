@@ -0,0 +1,95 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func Test_HTMLLinker(t *testing.T) {
+	link := HTMLLinker("/tag/", "")
+
+	tests := []struct {
+		name string
+		kind byte
+		tag  string
+		want string
+	}{
+		{"hash", MarkHash, "#GoLang", `<a href="/tag/golang">#GoLang</a>`},
+		{"escaped", MarkHash, "#it's", `<a href="/tag/it%27s">#it&#39;s</a>`},
+		{"no prefix", MarkMention, "@Bob", "@Bob"},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(link(tt.kind, tt.tag)); got != tt.want {
+				t.Errorf("%q: HTMLLinker() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_HTMLLinker()
+
+func Test_Linkify(t *testing.T) {
+	html := HTMLLinker("/tag/", "/user/")
+
+	tests := []struct {
+		name string
+		text string
+		link TLinkFunc
+		want string
+	}{
+		{"empty", "", html, ""},
+		{"nil func", "a #tag", nil, "a #tag"},
+		{"hash", "a #Tag.", html, `a <a href="/tag/tag">#Tag</a>.`},
+		{"mention", "hi @Bob", html, `hi <a href="/user/bob">@Bob</a>`},
+		{"underscore", "_#tag_", html, `_<a href="/tag/tag">#tag</a>_`},
+		{"fragment", `<a href="/x#y">z</a>`, html, `<a href="/x#y">z</a>`},
+		{"entity", "it&#39;s", html, "it&#39;s"},
+		{"email", "mail me@example.com.", html, "mail me@example.com."},
+		{"markdown", "a #Go_Lang b", MarkdownLinker("/t/", "/u/"), `a [#Go\_Lang](/t/go_lang) b`},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(Linkify([]byte(tt.text), tt.link)); got != tt.want {
+				t.Errorf("%q: Linkify() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_Linkify()
+
+func Test_MarkdownLinker(t *testing.T) {
+	link := MarkdownLinker("", "/user/")
+
+	tests := []struct {
+		name string
+		kind byte
+		tag  string
+		want string
+	}{
+		{"mention", MarkMention, "@Bob", "[@Bob](/user/bob)"},
+		{"no prefix", MarkHash, "#tag", "#tag"},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(link(tt.kind, tt.tag)); got != tt.want {
+				t.Errorf("%q: MarkdownLinker() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_MarkdownLinker()
+
+/* EoF */
@@ -0,0 +1,478 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	se "github.com/mwat56/sourceerror"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+// The mapped format is an offset-indexed file which can be queried
+// without decoding it. All numbers are little endian:
+//
+//	header:    magic "HTMX", version (uint32), number of tags (uint32),
+//	           reserved (uint32)
+//	directory: one entry per tag sorted by tag: offset and length of
+//	           the tag (2 × uint32), offset (uint64), length in bytes
+//	           and number of its IDs (2 × uint32)
+//	tags:      the normalised tags (without separators)
+//	IDs:       the IDs of each tag encoded by the list's codec, each
+//	           followed by a linefeed
+//
+// All offsets are counted from the file's start.
+
+type (
+	// `TMappedIndex` is a read-only list of `#hashtags` and
+	// `@mentions` served from a memory-mapped file written by
+	// [TIndex.StoreMapped].
+	//
+	// Opening such a file takes constant time; only the IDs of the
+	// tags looked up get decoded.
+	//
+	// The methods are safe for concurrent use but must not be called
+	// after (or concurrently with) [TMappedIndex.Close].
+	TMappedIndex[ID cmp.Ordered] struct {
+		data  []byte       // the mapped file's contents
+		codec TIDCodec[ID] // the codec to decode the IDs with
+		fn    string       // the name of the mapped file
+		tags  int          // the number of tags in the file
+		unmap func() error // the function releasing `data`
+	}
+
+	// `tMapEntry` is an entry of a mapped file's directory.
+	tMapEntry struct {
+		keyOff, keyLen uint32
+		idsOff         uint64
+		idsLen, count  uint32
+	}
+)
+
+const (
+	// `mapMagic` identifies a file in the mapped format.
+	mapMagic = "HTMX"
+
+	// `mapVersion` is the version of the mapped format.
+	mapVersion = 1
+
+	// `mapHeadSize` is the size of a mapped file's header.
+	mapHeadSize = 16
+
+	// `mapEntrySize` is the size of a mapped file's directory entry.
+	mapEntrySize = 24
+)
+
+// --------------------------------------------------------------------------
+// constructor functions:
+
+// `OpenMapped()` maps the file `aFilename` written by
+// [TIndex.StoreMapped] for a [THashTags] list into memory.
+//
+// See [OpenMappedIndex] for details.
+//
+// Parameters:
+//   - `aFilename`: The name of the file to map.
+//
+// Returns:
+//   - `*TMappedIndex[int64]`: The mapped list.
+//   - `error`: `nil` in case of success, otherwise an error.
+func OpenMapped(aFilename string) (*TMappedIndex[int64], error) {
+	return OpenMappedIndex[int64](aFilename, nil)
+} // OpenMapped()
+
+// `OpenMappedIndex()` maps the file `aFilename` written by
+// [TIndex.StoreMapped] into memory.
+//
+// The file's directory is checked but no data are decoded. The
+// returned list has to be closed after use.
+//
+// Parameters:
+//   - `aFilename`: The name of the file to map.
+//   - `aCodec`: The codec the file was written with (`nil` = [DefaultCodec]).
+//
+// Returns:
+//   - `*TMappedIndex[ID]`: The mapped list.
+//   - `error`: `ErrFormatMismatch`, `ErrCorruptFile`, or a possible I/O error.
+func OpenMappedIndex[ID cmp.Ordered](aFilename string, aCodec TIDCodec[ID]) (*TMappedIndex[ID], error) {
+	if aFilename = strings.TrimSpace(aFilename); "" == aFilename {
+		return nil, se.New(ErrEmptyFilename, 1)
+	}
+	if nil == aCodec {
+		aCodec = DefaultCodec[ID]()
+	}
+
+	file, err := os.Open(aFilename) //#nosec G304
+	if nil != err {
+		return nil, se.New(loadError(aFilename, err), 2)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if nil != err {
+		return nil, se.New(loadError(aFilename, err), 2)
+	}
+	if mapHeadSize > info.Size() {
+		return nil, se.New(&TFileError{Kind: ErrFormatMismatch, Filename: aFilename}, 1)
+	}
+
+	data, unmap, err := mapFile(file, int(info.Size()))
+	if nil != err {
+		return nil, se.New(loadError(aFilename, err), 2)
+	}
+	mi := &TMappedIndex[ID]{
+		data:  data,
+		codec: aCodec,
+		fn:    aFilename,
+		unmap: unmap,
+	}
+	if fe := mi.check(); nil != fe {
+		_ = unmap()
+		fe.Filename = aFilename
+		return nil, se.New(fe, 3)
+	}
+
+	return mi, nil
+} // OpenMappedIndex()
+
+// -------------------------------------------------------------------------
+// methods of `TMappedIndex`:
+
+// `check()` validates the header and directory of the mapped file.
+//
+// Returns:
+//   - `*TFileError`: `nil` if the file is valid, or the problem otherwise.
+func (mi *TMappedIndex[ID]) check() *TFileError {
+	if mapMagic != string(mi.data[:4]) {
+		return &TFileError{Kind: ErrFormatMismatch}
+	}
+	if version := binary.LittleEndian.Uint32(mi.data[4:]); mapVersion != version {
+		return &TFileError{Kind: ErrFormatMismatch, Err: errors.New("unknown version")}
+	}
+
+	tags := uint64(binary.LittleEndian.Uint32(mi.data[8:]))
+	size := uint64(len(mi.data))
+	if mapHeadSize+tags*mapEntrySize > size {
+		return &TFileError{Kind: ErrCorruptFile, Err: errors.New("truncated directory")}
+	}
+	mi.tags = int(tags)
+
+	var prev []byte
+	for idx := range mi.tags {
+		me := mi.entry(idx)
+		if (uint64(me.keyOff)+uint64(me.keyLen) > size) ||
+			(me.idsOff+uint64(me.idsLen) > size) {
+			return &TFileError{Kind: ErrCorruptFile, Err: errors.New("offset out of range")}
+		}
+		key := mi.data[me.keyOff : me.keyOff+me.keyLen]
+		if (0 == len(key)) || ((0 < idx) && (0 <= bytes.Compare(prev, key))) {
+			return &TFileError{Kind: ErrCorruptFile, Err: errors.New("unsorted directory")}
+		}
+		prev = key
+	}
+
+	return nil
+} // check()
+
+// `Close()` releases the mapped file.
+//
+// Returns:
+//   - `error`: A possible error releasing the file.
+func (mi *TMappedIndex[ID]) Close() error {
+	if nil == mi.unmap {
+		return nil
+	}
+	err := mi.unmap()
+	mi.data, mi.tags, mi.unmap = nil, 0, nil
+	if nil != err {
+		return se.New(err, 3)
+	}
+
+	return nil
+} // Close()
+
+// `entry()` returns the directory entry at position `aIndex`.
+//
+// Parameters:
+//   - `aIndex`: The position of the entry (`0 <= aIndex < tags`).
+//
+// Returns:
+//   - `tMapEntry`: The directory entry.
+func (mi *TMappedIndex[ID]) entry(aIndex int) tMapEntry {
+	buf := mi.data[mapHeadSize+aIndex*mapEntrySize:]
+
+	return tMapEntry{
+		keyOff: binary.LittleEndian.Uint32(buf),
+		keyLen: binary.LittleEndian.Uint32(buf[4:]),
+		idsOff: binary.LittleEndian.Uint64(buf[8:]),
+		idsLen: binary.LittleEndian.Uint32(buf[16:]),
+		count:  binary.LittleEndian.Uint32(buf[20:]),
+	}
+} // entry()
+
+// `Filename()` returns the name of the mapped file.
+//
+// Returns:
+//   - `string`: The mapped file's name.
+func (mi *TMappedIndex[ID]) Filename() string {
+	return mi.fn
+} // Filename()
+
+// `find()` returns the directory entry of `aTag`.
+//
+// Parameters:
+//   - `aDelim`: The start of words to search (i.e. either '@' or '#').
+//   - `aTag`: The tag to lookup.
+//
+// Returns:
+//   - `tMapEntry`: The tag's directory entry.
+//   - `bool`: `true` if `aTag` was found, or `false` otherwise.
+func (mi *TMappedIndex[ID]) find(aDelim byte, aTag string) (tMapEntry, bool) {
+	// prepare for case-insensitive search:
+	if aTag = normalise(aTag); "" == aTag {
+		return tMapEntry{}, false
+	}
+	if aTag[0] != aDelim {
+		aTag = string(aDelim) + aTag
+	}
+
+	key := []byte(aTag)
+	lo, hi := 0, mi.tags
+	for lo < hi {
+		idx := int(uint(lo+hi) >> 1)
+		me := mi.entry(idx)
+		switch bytes.Compare(mi.data[me.keyOff:me.keyOff+me.keyLen], key) {
+		case -1:
+			lo = idx + 1
+		case 1:
+			hi = idx
+		default:
+			return me, true
+		}
+	}
+
+	return tMapEntry{}, false
+} // find()
+
+// `HashLen()` returns the number of IDs stored for `aHash`.
+//
+// Parameters:
+//   - `aHash`: The hash to lookup.
+//
+// Returns:
+//   - `int`: The number of `aHash` in the list (`-1` if not found).
+func (mi *TMappedIndex[ID]) HashLen(aHash string) int {
+	return mi.idxLen(MarkHash, aHash)
+} // HashLen()
+
+// `HashList()` returns a list of IDs associated with `aHash`.
+//
+// Only the IDs of `aHash` are decoded; IDs which can't be decoded
+// are skipped.
+//
+// Parameters:
+//   - `aHash`: The hash to lookup.
+//
+// Returns:
+//   - `[]ID`: The IDs referencing `aHash` in ascending order.
+func (mi *TMappedIndex[ID]) HashList(aHash string) []ID {
+	return mi.list(MarkHash, aHash)
+} // HashList()
+
+// `idxLen()` returns the number of IDs stored for `aTag`.
+//
+// Parameters:
+//   - `aDelim`: The start of words to search (i.e. either '@' or '#').
+//   - `aTag`: The tag to lookup.
+//
+// Returns:
+//   - `int`: The number of IDs (`-1` if not found).
+func (mi *TMappedIndex[ID]) idxLen(aDelim byte, aTag string) int {
+	me, ok := mi.find(aDelim, aTag)
+	if !ok {
+		return -1
+	}
+
+	return int(me.count)
+} // idxLen()
+
+// `Len()` returns the number of tags in the list.
+//
+// Returns:
+//   - `int`: The number of `#hashtags` and `@mentions`.
+func (mi *TMappedIndex[ID]) Len() int {
+	return mi.tags
+} // Len()
+
+// `list()` returns the decoded IDs associated with `aTag`.
+//
+// Parameters:
+//   - `aDelim`: The start of words to search (i.e. either '@' or '#').
+//   - `aTag`: The tag to lookup.
+//
+// Returns:
+//   - `[]ID`: The IDs referencing `aTag` (or `nil`).
+func (mi *TMappedIndex[ID]) list(aDelim byte, aTag string) []ID {
+	me, ok := mi.find(aDelim, aTag)
+	if !ok || (0 == me.count) {
+		return nil
+	}
+
+	result := make([]ID, 0, me.count)
+	data := mi.data[me.idsOff : me.idsOff+uint64(me.idsLen)]
+	for 0 < len(data) {
+		line := data
+		if idx := bytes.IndexByte(data, '\n'); 0 <= idx {
+			line, data = data[:idx], data[idx+1:]
+		} else {
+			data = nil
+		}
+		if id, err := mi.codec.DecodeID(string(line)); nil == err {
+			result = append(result, id)
+		}
+	}
+
+	return result
+} // list()
+
+// `MentionLen()` returns the number of IDs stored for `aMention`.
+//
+// Parameters:
+//   - `aMention`: The mention to lookup.
+//
+// Returns:
+//   - `int`: The number of `aMention` in the list (`-1` if not found).
+func (mi *TMappedIndex[ID]) MentionLen(aMention string) int {
+	return mi.idxLen(MarkMention, aMention)
+} // MentionLen()
+
+// `MentionList()` returns a list of IDs associated with `aMention`.
+//
+// See [TMappedIndex.HashList] for details.
+//
+// Parameters:
+//   - `aMention`: The mention to lookup.
+//
+// Returns:
+//   - `[]ID`: The IDs referencing `aMention` in ascending order.
+func (mi *TMappedIndex[ID]) MentionList(aMention string) []ID {
+	return mi.list(MarkMention, aMention)
+} // MentionList()
+
+// -------------------------------------------------------------------------
+// methods of `TIndex`:
+
+// `StoreMapped()` writes the list in the mapped format to `aFilename`
+// for use by [OpenMappedIndex].
+//
+// Only the tags and their IDs are written, i.e. neither the tags'
+// metadata nor the list's scopes. The list's configured file is not
+// affected.
+//
+// The data are written to a temporary file which then replaces
+// `aFilename`, i.e. lists which have mapped the previous file keep
+// seeing its data until they're closed.
+//
+// Parameters:
+//   - `aFilename`: The name of the file to write.
+//
+// Returns:
+//   - `int`: Number of bytes written.
+//   - `error`: `ErrEmptyFilename` or a possible [TFileError].
+func (ht *TIndex[ID]) StoreMapped(aFilename string) (int, error) {
+	if aFilename = strings.TrimSpace(aFilename); "" == aFilename {
+		return 0, se.New(ErrEmptyFilename, 1)
+	}
+
+	if ht.safe {
+		ht.mtx.RLock()
+	}
+	keys := ht.hm.keys()
+	slices.Sort(keys)
+	codec := ht.xt.codec()
+	dir := make([]tMapEntry, len(keys))
+	var ids bytes.Buffer
+	for idx, key := range keys {
+		dir[idx].idsOff = uint64(ids.Len())
+		for _, id := range *(*ht.hm)[key] {
+			ids.WriteString(codec.EncodeID(id))
+			ids.WriteByte('\n')
+		}
+		dir[idx].idsLen = uint32(uint64(ids.Len()) - dir[idx].idsOff) //#nosec G115
+		dir[idx].count = uint32(len(*(*ht.hm)[key]))                  //#nosec G115
+	}
+	if ht.safe {
+		ht.mtx.RUnlock()
+	}
+
+	// compute the offsets:
+	offset := uint32(mapHeadSize + len(keys)*mapEntrySize) //#nosec G115
+	for idx, key := range keys {
+		dir[idx].keyOff, dir[idx].keyLen = offset, uint32(len(key)) //#nosec G115
+		offset += dir[idx].keyLen
+	}
+	for idx := range dir {
+		dir[idx].idsOff += uint64(offset)
+	}
+
+	// write to a temporary file first since the target may be mapped
+	// by another process
+	fDir, fBase := filepath.Split(aFilename)
+	file, err := os.CreateTemp(fDir, fBase+".*")
+	if nil != err {
+		return 0, se.New(storeError(aFilename, err), 1)
+	}
+	tmpName := file.Name()
+	defer os.Remove(tmpName) // fails after renaming
+	defer file.Close()
+
+	buf := bufio.NewWriter(file)
+	head := make([]byte, mapHeadSize)
+	copy(head, mapMagic)
+	binary.LittleEndian.PutUint32(head[4:], mapVersion)
+	binary.LittleEndian.PutUint32(head[8:], uint32(len(keys))) //#nosec G115
+	buf.Write(head)
+
+	entry := make([]byte, mapEntrySize)
+	for _, me := range dir {
+		binary.LittleEndian.PutUint32(entry, me.keyOff)
+		binary.LittleEndian.PutUint32(entry[4:], me.keyLen)
+		binary.LittleEndian.PutUint64(entry[8:], me.idsOff)
+		binary.LittleEndian.PutUint32(entry[16:], me.idsLen)
+		binary.LittleEndian.PutUint32(entry[20:], me.count)
+		buf.Write(entry)
+	}
+	for _, key := range keys {
+		buf.WriteString(key)
+	}
+	buf.Write(ids.Bytes())
+	if err = buf.Flush(); nil != err {
+		return 0, se.New(storeError(aFilename, err), 1)
+	}
+	if err = file.Chmod(0660); nil != err {
+		return 0, se.New(storeError(aFilename, err), 1)
+	}
+	if err = file.Close(); nil != err {
+		return 0, se.New(storeError(aFilename, err), 1)
+	}
+	if err = os.Rename(tmpName, aFilename); nil != err {
+		return 0, se.New(storeError(aFilename, err), 1)
+	}
+
+	return int(offset) + ids.Len(), nil
+} // StoreMapped()
+
+/* EoF */
@@ -0,0 +1,134 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func Test_TMappedIndex(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "mapped.idx")
+	ht, _ := New("")
+	ht.IDparse(3, []byte("#Go and @Bob"))
+	ht.IDparse(1, []byte("#go and #rust"))
+	ht.IDparse(2, []byte("@alice likes #rust"))
+
+	size, err := ht.StoreMapped(fn)
+	if nil != err {
+		t.Fatalf("THashTags.StoreMapped() error = %v", err)
+	}
+	if info, _ := os.Stat(fn); int64(size) != info.Size() {
+		t.Errorf("THashTags.StoreMapped() = %d, want %d", size, info.Size())
+	}
+
+	mi, err := OpenMapped(fn)
+	if nil != err {
+		t.Fatalf("OpenMapped() error = %v", err)
+	}
+	defer mi.Close()
+
+	if 4 != mi.Len() {
+		t.Errorf("TMappedIndex.Len() = %d, want 4", mi.Len())
+	}
+	tests := []struct {
+		name string
+		got  []int64
+		want []int64
+	}{
+		{"#go", mi.HashList("#GO"), []int64{1, 3}},
+		{"#rust", mi.HashList("rust"), []int64{1, 2}},
+		{"@alice", mi.MentionList("@alice"), []int64{2}},
+		{"@bob", mi.MentionList("bob"), []int64{3}},
+		{"#missing", mi.HashList("#missing"), nil},
+		{"@go", mi.MentionList("go"), nil},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !slices.Equal(tt.got, tt.want) {
+				t.Errorf("TMappedIndex list(%q) = %v, want %v", tt.name, tt.got, tt.want)
+			}
+		})
+	}
+	if (2 != mi.HashLen("#rust")) || (1 != mi.MentionLen("@bob")) || (-1 != mi.HashLen("#zig")) {
+		t.Error("TMappedIndex.HashLen() failed")
+	}
+	if err = mi.Close(); (nil != err) || (0 != mi.Len()) {
+		t.Errorf("TMappedIndex.Close() = %v", err)
+	}
+} // Test_TMappedIndex()
+
+func Test_THashTags_StoreMapped_mapped(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "mapped.idx")
+	ht := setList(map[string][]int64{"#go": {1, 2}, "@bob": {3}})
+	if _, err := ht.StoreMapped(fn); nil != err {
+		t.Fatalf("THashTags.StoreMapped() error = %v", err)
+	}
+	mi, err := OpenMapped(fn)
+	if nil != err {
+		t.Fatalf("OpenMapped() error = %v", err)
+	}
+	defer mi.Close()
+
+	// rewrite the file while it's mapped
+	ht.HashRemove("#go", 1)
+	ht.IDparse(4, []byte("#rust and #zig and #c"))
+	if _, err = ht.StoreMapped(fn); nil != err {
+		t.Fatalf("THashTags.StoreMapped() error = %v", err)
+	}
+
+	// the mapped list still sees the previous data
+	if got := mi.HashList("#go"); !slices.Equal(got, []int64{1, 2}) || (2 != mi.Len()) {
+		t.Errorf("TMappedIndex.HashList() = %v, len %d", got, mi.Len())
+	}
+
+	mi2, err := OpenMapped(fn)
+	if nil != err {
+		t.Fatalf("OpenMapped() error = %v", err)
+	}
+	defer mi2.Close()
+	if got := mi2.HashList("#go"); !slices.Equal(got, []int64{2}) || (5 != mi2.Len()) {
+		t.Errorf("TMappedIndex.HashList() = %v, len %d", got, mi2.Len())
+	}
+	if matches, _ := filepath.Glob(fn + ".*"); 0 != len(matches) {
+		t.Errorf("THashTags.StoreMapped() left %v", matches)
+	}
+} // Test_THashTags_StoreMapped_mapped()
+
+func Test_OpenMapped_errors(t *testing.T) {
+	dir := t.TempDir()
+	fn := filepath.Join(dir, "bad.idx")
+
+	_ = os.WriteFile(fn, []byte("[#go]\n0000000000000001\n"), 0600)
+	if _, err := OpenMapped(fn); !errors.Is(err, ErrFormatMismatch) {
+		t.Errorf("OpenMapped() error = %v, want %v", err, ErrFormatMismatch)
+	}
+
+	ht, _ := New("")
+	ht.IDparse(1, []byte("#go and @bob"))
+	_, _ = ht.StoreMapped(fn)
+	data, _ := os.ReadFile(fn)
+	_ = os.WriteFile(fn, data[:len(data)-8], 0600)
+	if _, err := OpenMapped(fn); !errors.Is(err, ErrCorruptFile) {
+		t.Errorf("OpenMapped() error = %v, want %v", err, ErrCorruptFile)
+	}
+
+	if _, err := OpenMapped(filepath.Join(dir, "missing.idx")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("OpenMapped() error = %v, want %v", err, os.ErrNotExist)
+	}
+	if _, err := ht.StoreMapped(" "); !errors.Is(err, ErrEmptyFilename) {
+		t.Errorf("THashTags.StoreMapped() error = %v, want %v", err, ErrEmptyFilename)
+	}
+} // Test_OpenMapped_errors()
+
+/* EoF */
@@ -0,0 +1,536 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	se "github.com/mwat56/sourceerror"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `tSection` holds the consecutive sections of a text file whose
+	// tags differ only by their mark (e.g. `#go` and `@go`).
+	tSection[ID cmp.Ordered] struct {
+		hm   *tHashMap[ID] // the sections' tags and IDs
+		xt   *tExtras[ID]  // the sections' additional data
+		name string        // the tags' name without mark
+	}
+
+	// `tSectionReader` reads a file in the plain text format section
+	// by section.
+	tSectionReader[ID cmp.Ordered] struct {
+		scanner   *bufio.Scanner // the file's scanner
+		scopes    *tExtras[ID]   // receives the file's scopes
+		scope     *tScope[ID]    // the current scope (if any)
+		scopeHash string         // the current tag of the current scope
+		pending   string         // a header line read ahead
+		last      string         // name of the last section returned
+		codec     TIDCodec[ID]   // the codec for the IDs
+	}
+)
+
+var (
+	// The sections of a file in plain text format are not sorted.
+	errUnsorted = errors.New("sections not sorted")
+)
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `intersectWith()` removes all IDs from `aMap` (and its scopes) not
+// present in `aOtherMap` (and the respective scopes).
+//
+// Parameters:
+//   - `aMap`: The hash map to change.
+//   - `aExtras`: The additional data of `aMap`.
+//   - `aOtherMap`: The hash map whose IDs are to be kept.
+//   - `aOtherExtras`: The additional data of `aOtherMap`.
+//
+// Returns:
+//   - `bool`: `true` if at least one ID was removed, or `false` otherwise.
+func intersectWith[ID cmp.Ordered](aMap *tHashMap[ID], aExtras *tExtras[ID], aOtherMap *tHashMap[ID], aOtherExtras *tExtras[ID]) bool {
+	result := aMap.intersect(*aOtherMap)
+	if result {
+		aExtras.prune(*aMap)
+	}
+
+	for name, sc := range aExtras.Scopes {
+		if (nil == sc) || (nil == sc.Map) {
+			continue
+		}
+		other, ok := aOtherExtras.Scopes[name]
+		if !ok || (nil == other) || (nil == other.Map) {
+			other = &tScope[ID]{Map: newHashMap[ID](), Extras: newExtras[ID]()}
+		}
+		if intersectWith(sc.Map, sc.Extras, other.Map, other.Extras) {
+			result = true
+		}
+	}
+
+	return result
+} // intersectWith()
+
+// `MergeFiles()` combines the [THashTags] files `aSources` into the
+// file `aTarget`, see [MergeIndexFiles] for details.
+//
+// Parameters:
+//   - `aTarget`: The name of the file to write.
+//   - `aSources`: The names of the files to combine.
+//
+// Returns:
+//   - `error`: `nil` in case of success, or a possible I/O error.
+func MergeFiles(aTarget string, aSources ...string) error {
+	return MergeIndexFiles[int64](aTarget, nil, aSources...)
+} // MergeFiles()
+
+// `MergeIndexFiles()` combines the [TIndex] files `aSources` into the
+// file `aTarget` without loading all of them into memory.
+//
+// The files are expected in the format selected by [UseBinaryStorage].
+// In the plain text format the sources are read section by section
+// (i.e. tag by tag) while writing the combined sections; only the
+// sources' scopes are combined in memory. In the binary format the
+// sources are read one after another, i.e. only the combined list and
+// one source are held in memory. In both formats the combined data
+// are written to a temporary file which then replaces the target;
+// the target may be one of the sources.
+//
+// Parameters:
+//   - `aTarget`: The name of the file to write.
+//   - `aCodec`: The codec for the IDs (`nil` = [DefaultCodec]).
+//   - `aSources`: The names of the files to combine.
+//
+// Returns:
+//   - `error`: `nil` in case of success, or a possible I/O error.
+func MergeIndexFiles[ID cmp.Ordered](aTarget string, aCodec TIDCodec[ID], aSources ...string) error {
+	if aTarget = strings.TrimSpace(aTarget); "" == aTarget {
+		return se.New(ErrEmptyFilename, 1)
+	}
+	for _, fn := range aSources {
+		if _, err := os.Stat(fn); nil != err {
+			return se.New(loadError(fn, err), 1)
+		}
+	}
+
+	if !UseBinaryStorage {
+		err := mergeTextFiles(aTarget, aCodec, aSources)
+		if !errors.Is(err, errUnsorted) {
+			return err // already wrapped
+		}
+		// fall back to reading the sources one after another
+	}
+
+	hm, xt := newHashMap[ID](), newExtras[ID]()
+	xt.ic = aCodec
+	for _, fn := range aSources {
+		shm, sxt := newHashMap[ID](), newExtras[ID]()
+		sxt.ic = aCodec
+		if _, err := shm.loadFile(context.Background(), fn, sxt, LoadLenient, nil, nil); nil != err {
+			return err // already wrapped
+		}
+		hm.union(*shm)
+		xt.union(sxt)
+	}
+
+	// the target may be one of the sources
+	dir, base := filepath.Split(aTarget)
+	file, err := os.CreateTemp(dir, base+".*")
+	if nil != err {
+		return se.New(storeError(aTarget, err), 1)
+	}
+	tmpName := file.Name()
+	defer os.Remove(tmpName) // fails after renaming
+	defer file.Close()
+
+	if _, err = hm.storeTo(file, xt, nil); nil != err {
+		return se.New(storeError(aTarget, err), 1)
+	}
+	if err = file.Chmod(0660); nil != err {
+		return se.New(storeError(aTarget, err), 1)
+	}
+	if err = file.Close(); nil != err {
+		return se.New(storeError(aTarget, err), 1)
+	}
+	if err = os.Rename(tmpName, aTarget); nil != err {
+		return se.New(storeError(aTarget, err), 1)
+	}
+
+	return nil
+} // MergeIndexFiles()
+
+// `mergeTextFiles()` combines the files `aSources` in the plain text
+// format section by section into the file `aTarget`.
+//
+// Parameters:
+//   - `aTarget`: The name of the file to write.
+//   - `aCodec`: The codec for the IDs (`nil` = [DefaultCodec]).
+//   - `aSources`: The names of the files to combine.
+//
+// Returns:
+//   - `error`: `nil` in case of success, `errUnsorted`, or a possible I/O error.
+func mergeTextFiles[ID cmp.Ordered](aTarget string, aCodec TIDCodec[ID], aSources []string) error {
+	scopes := newExtras[ID]()
+	scopes.ic = aCodec
+	sections := make([]*tSection[ID], len(aSources))
+	readers := make([]*tSectionReader[ID], len(aSources))
+	for idx, fn := range aSources {
+		file, err := os.OpenFile(fn, os.O_RDONLY, 0) //#nosec G304
+		if nil != err {
+			return se.New(loadError(fn, err), 1)
+		}
+		defer file.Close()
+		if err = lockFile(file, false); nil != err {
+			return se.New(loadError(fn, err), 1)
+		}
+		if fi, _ := file.Stat(); (nil != fi) && (0 < fi.Size()) && !isTextFile(file) {
+			return se.New(&TFileError{Kind: ErrFormatMismatch, Filename: fn}, 1)
+		}
+		if _, err = file.Seek(0, io.SeekStart); nil != err {
+			return se.New(loadError(fn, err), 1)
+		}
+
+		readers[idx] = newSectionReader(file, aCodec, scopes)
+		if sections[idx], err = readers[idx].next(); nil != err {
+			return se.New(loadError(fn, err), 1)
+		}
+	}
+
+	dir, base := filepath.Split(aTarget)
+	file, err := os.CreateTemp(dir, base+".*")
+	if nil != err {
+		return se.New(storeError(aTarget, err), 1)
+	}
+	tmpName := file.Name()
+	defer os.Remove(tmpName) // fails after renaming
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for {
+		// find the sections to combine next
+		var name string
+		found := false
+		for _, sec := range sections {
+			if (nil != sec) && (!found || (sec.name < name)) {
+				name, found = sec.name, true
+			}
+		}
+		if !found {
+			break
+		}
+
+		hm, xt := newHashMap[ID](), newExtras[ID]()
+		xt.ic = aCodec
+		for idx, sec := range sections {
+			if (nil == sec) || (name != sec.name) {
+				continue
+			}
+			hm.union(*sec.hm)
+			xt.union(sec.xt)
+			if sections[idx], err = readers[idx].next(); nil != err {
+				return se.New(loadError(aSources[idx], err), 1)
+			}
+		}
+		if _, err = writer.WriteString(hm.text(xt)); nil != err {
+			return se.New(storeError(aTarget, err), 1)
+		}
+	}
+
+	var buf bytes.Buffer
+	scopes.writeScopes(&buf)
+	if _, err = writer.Write(buf.Bytes()); nil != err {
+		return se.New(storeError(aTarget, err), 1)
+	}
+	if err = writer.Flush(); nil != err {
+		return se.New(storeError(aTarget, err), 1)
+	}
+	if err = file.Chmod(0660); nil != err {
+		return se.New(storeError(aTarget, err), 1)
+	}
+	if err = file.Close(); nil != err {
+		return se.New(storeError(aTarget, err), 1)
+	}
+	if err = os.Rename(tmpName, aTarget); nil != err {
+		return se.New(storeError(aTarget, err), 1)
+	}
+
+	return nil
+} // mergeTextFiles()
+
+// `newSectionReader()` returns a new `tSectionReader` instance.
+//
+// Parameters:
+//   - `aFile`: The file to read.
+//   - `aCodec`: The codec for the IDs (`nil` = [DefaultCodec]).
+//   - `aScopes`: Container receiving the file's scopes.
+//
+// Returns:
+//   - `*tSectionReader[ID]`: The new reader.
+func newSectionReader[ID cmp.Ordered](aFile io.Reader, aCodec TIDCodec[ID], aScopes *tExtras[ID]) *tSectionReader[ID] {
+	return &tSectionReader[ID]{
+		scanner: bufio.NewScanner(aFile),
+		scopes:  aScopes,
+		codec:   aCodec,
+	}
+} // newSectionReader()
+
+// `subtractWith()` removes all IDs of `aOtherMap` (and its scopes)
+// from `aMap` (and the respective scopes).
+//
+// Parameters:
+//   - `aMap`: The hash map to change.
+//   - `aExtras`: The additional data of `aMap`.
+//   - `aOtherMap`: The hash map whose IDs are to be removed.
+//   - `aOtherExtras`: The additional data of `aOtherMap`.
+//
+// Returns:
+//   - `bool`: `true` if at least one ID was removed, or `false` otherwise.
+func subtractWith[ID cmp.Ordered](aMap *tHashMap[ID], aExtras *tExtras[ID], aOtherMap *tHashMap[ID], aOtherExtras *tExtras[ID]) bool {
+	result := aMap.subtract(*aOtherMap)
+	if result {
+		aExtras.prune(*aMap)
+	}
+
+	for name, sc := range aExtras.Scopes {
+		other, ok := aOtherExtras.Scopes[name]
+		if (nil == sc) || (nil == sc.Map) || !ok || (nil == other) || (nil == other.Map) {
+			continue
+		}
+		if subtractWith(sc.Map, sc.Extras, other.Map, other.Extras) {
+			result = true
+		}
+	}
+
+	return result
+} // subtractWith()
+
+// -------------------------------------------------------------------------
+// methods of `tSectionReader`:
+
+// `line()` returns the next non-empty line of the file.
+//
+// Returns:
+//   - `string`: The trimmed line.
+//   - `bool`: `true` if a line was read, or `false` at the end of the file.
+func (sr *tSectionReader[ID]) line() (string, bool) {
+	if "" != sr.pending {
+		line := sr.pending
+		sr.pending = ""
+		return line, true
+	}
+
+	for sr.scanner.Scan() {
+		if line := strings.TrimSpace(sr.scanner.Text()); "" != line {
+			return line, true
+		}
+	}
+
+	return "", false
+} // line()
+
+// `next()` returns the file's next group of sections.
+//
+// Scope sections are added to the reader's scopes container.
+//
+// Returns:
+//   - `*tSection[ID]`: The next sections, or `nil` at the end of the file.
+//   - `error`: `errUnsorted` or a possible I/O error.
+func (sr *tSectionReader[ID]) next() (*tSection[ID], error) {
+	sec := &tSection[ID]{hm: newHashMap[ID](), xt: newExtras[ID]()}
+	sec.xt.ic = sr.codec
+
+	var hash string
+	for {
+		line, ok := sr.line()
+		if !ok {
+			break
+		}
+
+		switch line[0] {
+		case textScopeMark:
+			if '}' == line[len(line)-1] {
+				sr.scope, sr.scopeHash = sr.scopes.scope(line[1:len(line)-1]), ""
+			} else {
+				sr.scope = nil
+			}
+			continue
+
+		case textScopeLine:
+			if nil != sr.scope {
+				_ = sr.scope.Map.textLine(strings.TrimSpace(line[1:]), &sr.scopeHash, sr.scope.Extras)
+			}
+			continue
+
+		case '[':
+			if matches := htHashHeadRE.FindStringSubmatch(line); nil != matches {
+				name := strings.TrimLeft(normalise(matches[1]), string([]byte{MarkHash, MarkMention}))
+				if "" == sec.name {
+					if ("" != sr.last) && (name <= sr.last) {
+						return nil, errUnsorted
+					}
+					sec.name = name
+				} else if name != sec.name {
+					sr.pending = line // the next group's header
+					sr.last = sec.name
+					return sec, nil
+				}
+			}
+		}
+		_ = sec.hm.textLine(line, &hash, sec.xt) // lenient reading
+	}
+	if err := sr.scanner.Err(); nil != err {
+		return nil, err
+	}
+	if 0 == len(*sec.hm) {
+		return nil, nil
+	}
+	sr.last = sec.name
+
+	return sec, nil
+} // next()
+
+// -------------------------------------------------------------------------
+// methods of `TIndex`:
+
+// `Intersect()` removes all IDs from the list which aren't associated
+// with the same tag in `aOther`.
+//
+// Tags becoming empty are deleted along with their metadata. For a
+// list with scopes the respective scopes are intersected as well.
+//
+// Parameters:
+//   - `aOther`: The list whose associations are to be kept.
+//
+// Returns:
+//   - `bool`: `true` if at least one ID was removed, or `false` otherwise.
+func (ht *TIndex[ID]) Intersect(aOther *TIndex[ID]) bool {
+	if ht.readOnly() || (nil == aOther) {
+		return false
+	}
+	hm, xt := aOther.snapshot()
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+	defer ht.deferredStore()()
+
+	if !intersectWith(ht.hm, ht.xt, hm, xt) {
+		return false
+	}
+	ht.updated()
+	ht.clearHistory()
+
+	return true
+} // Intersect()
+
+// `Merge()` adds all associations of `aOther` to the list.
+//
+// The IDs of each tag are combined by merging both sorted lists. Tag
+// metadata missing in the list are copied from `aOther`, and positions
+// and association times are combined. For a list with scopes the
+// respective scopes are merged as well.
+//
+// Parameters:
+//   - `aOther`: The list whose associations are to be added.
+//
+// Returns:
+//   - `bool`: `true` if at least one ID or tag's data was added, or `false` otherwise.
+func (ht *TIndex[ID]) Merge(aOther *TIndex[ID]) bool {
+	if ht.readOnly() || (nil == aOther) {
+		return false
+	}
+	hm, xt := aOther.snapshot()
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+	defer ht.deferredStore()()
+
+	result := ht.hm.union(*hm)
+	result = ht.xt.union(xt) || result
+	ht.updated()
+	if result {
+		ht.clearHistory()
+	}
+
+	return result
+} // Merge()
+
+// `snapshot()` returns copies of the list's tags and IDs and of the
+// additional data.
+//
+// Returns:
+//   - `*tHashMap[ID]`: The copy of the list's tags and IDs.
+//   - `*tExtras[ID]`: The copy of the list's additional data.
+func (ht *TIndex[ID]) snapshot() (*tHashMap[ID], *tExtras[ID]) {
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	hm, xt := newHashMap[ID](), newExtras[ID]()
+	xt.ic = ht.xt.ic
+	hm.union(*ht.hm)
+	xt.union(ht.xt)
+
+	return hm, xt
+} // snapshot()
+
+// `Subtract()` removes all associations of `aOther` from the list.
+//
+// Tags becoming empty are deleted along with their metadata. For a
+// list with scopes the respective scopes are subtracted as well.
+//
+// Parameters:
+//   - `aOther`: The list whose associations are to be removed.
+//
+// Returns:
+//   - `bool`: `true` if at least one ID was removed, or `false` otherwise.
+func (ht *TIndex[ID]) Subtract(aOther *TIndex[ID]) bool {
+	if ht.readOnly() || (nil == aOther) {
+		return false
+	}
+	hm, xt := aOther.snapshot()
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+	defer ht.deferredStore()()
+
+	if !subtractWith(ht.hm, ht.xt, hm, xt) {
+		return false
+	}
+	ht.updated()
+	ht.clearHistory()
+
+	return true
+} // Subtract()
+
+// `updated()` invalidates the caches after a change of the list's
+// data (including its scopes).
+//
+// NOTE: This method expects the caller to hold the list's write lock.
+func (ht *TIndex[ID]) updated() {
+	ht.rebindScopes()
+	ht.cc.cl = nil
+	ht.invalidate()
+} // updated()
+
+/* EoF */
@@ -0,0 +1,214 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+// `setList()` returns a new list holding the given `#hashtags`.
+func setList(aTags map[string][]int64) *THashTags {
+	ht, _ := New("")
+	for tag, ids := range aTags {
+		for _, id := range ids {
+			ht.HashAdd(tag, id)
+		}
+	}
+
+	return ht
+} // setList()
+
+func Test_THashTags_Merge(t *testing.T) {
+	a := setList(map[string][]int64{"#go": {1, 3}, "#c": {2}})
+	b := setList(map[string][]int64{"#go": {2, 3}, "#rust": {4}})
+	b.SetDescription("#rust", "a language")
+
+	if !a.Merge(b) {
+		t.Error("THashTags.Merge() = false, want true")
+	}
+	if got := a.HashList("#go"); !slices.Equal(got, []int64{1, 2, 3}) {
+		t.Errorf("THashTags.Merge() #go = %v", got)
+	}
+	if item, _ := a.TagItem("#rust"); (1 != item.Count) || ("a language" != item.Description) {
+		t.Errorf("THashTags.Merge() #rust = %+v", item)
+	}
+	if a.Merge(b) || a.Merge(a) || a.Merge(nil) {
+		t.Error("THashTags.Merge() = true, want false")
+	}
+
+	// scopes are merged as well
+	b.Scope("blog").HashAdd("#go", 7)
+	if !a.Merge(b) || (1 != a.Scope("blog").HashLen("#go")) {
+		t.Errorf("THashTags.Merge() scope = %v", a.Scope("blog").String())
+	}
+
+	// tags differing only by their mark
+	b.MentionAdd("@go", 5)
+	if !a.Merge(b) {
+		t.Error("THashTags.Merge() = false, want true")
+	}
+	for range 20 {
+		if a.Merge(b) {
+			t.Fatal("THashTags.Merge() = true for merged data")
+		}
+	}
+} // Test_THashTags_Merge()
+
+func Test_THashTags_Intersect(t *testing.T) {
+	a := setList(map[string][]int64{"#go": {1, 2, 3}, "#c": {2}})
+	a.SetTimestamps(true)
+	a.HashAdd("#go", 4)
+	b := setList(map[string][]int64{"#go": {2, 3, 5}, "#rust": {4}})
+
+	if !a.Intersect(b) {
+		t.Error("THashTags.Intersect() = false, want true")
+	}
+	if got := a.HashList("#go"); !slices.Equal(got, []int64{2, 3}) {
+		t.Errorf("THashTags.Intersect() #go = %v", got)
+	}
+	if (-1 != a.HashLen("#c")) || (-1 != a.HashLen("#rust")) {
+		t.Errorf("THashTags.Intersect() = %v", a.String())
+	}
+	if _, ok := a.xt.Times.get("#go", 4); ok {
+		t.Error("THashTags.Intersect() kept the time of a removed ID")
+	}
+	if a.Intersect(b) || a.Intersect(a) {
+		t.Error("THashTags.Intersect() = true, want false")
+	}
+} // Test_THashTags_Intersect()
+
+func Test_THashTags_Subtract(t *testing.T) {
+	a := setList(map[string][]int64{"#go": {1, 2, 3}, "#c": {2}})
+	b := setList(map[string][]int64{"#go": {2, 3, 5}, "#c": {2}, "#rust": {4}})
+
+	if !a.Subtract(b) {
+		t.Error("THashTags.Subtract() = false, want true")
+	}
+	if got := a.HashList("#go"); !slices.Equal(got, []int64{1}) {
+		t.Errorf("THashTags.Subtract() #go = %v", got)
+	}
+	if -1 != a.HashLen("#c") {
+		t.Errorf("THashTags.Subtract() = %v", a.String())
+	}
+	if a.Subtract(b) {
+		t.Error("THashTags.Subtract() = true, want false")
+	}
+	if !a.Subtract(a) || (0 != a.Len()) {
+		t.Errorf("THashTags.Subtract() = %v", a.String())
+	}
+} // Test_THashTags_Subtract()
+
+func Test_MergeFiles(t *testing.T) {
+	saveBinary := UseBinaryStorage
+	defer func() {
+		UseBinaryStorage = saveBinary
+	}()
+	dir := t.TempDir()
+
+	for _, binary := range []bool{false, true} {
+		UseBinaryStorage = binary
+		fn1 := filepath.Join(dir, "part1"+exts[binary])
+		fn2 := filepath.Join(dir, "part2"+exts[binary])
+		target := filepath.Join(dir, "all"+exts[binary])
+
+		a, _ := New(fn1)
+		a.IDparse(1, []byte("#Go and @go and #zig"))
+		a.SetPinned("#go", true)
+		a.Scope("blog").HashAdd("#c", 1)
+		_, _ = a.Store()
+		b, _ := New(fn2)
+		b.IDparse(2, []byte("#go and #c and @bob"))
+		b.Scope("blog").HashAdd("#c", 2)
+		_, _ = b.Store()
+
+		if err := MergeFiles(target, fn1, fn2); nil != err {
+			t.Fatalf("MergeFiles() error = %v", err)
+		}
+		if !binary {
+			// the sorted files are merged without the fallback
+			if err := mergeTextFiles[int64](target, nil, []string{fn1, fn2}); nil != err {
+				t.Errorf("mergeTextFiles() error = %v", err)
+			}
+		}
+		want := setList(nil)
+		_ = want.SetFilename(fn1)
+		_, _ = want.Load()
+		want.Merge(b)
+
+		got, err := New(target)
+		if nil != err {
+			t.Fatalf("New() error = %v", err)
+		}
+		if !got.hm.equals(*want.hm) {
+			t.Errorf("MergeFiles(%v) =\n%v\n>>>> want >>>>\n%v", binary, got.String(), want.String())
+		}
+		if item, _ := got.TagItem("#go"); !item.Pinned || ("#Go" != item.Display) {
+			t.Errorf("MergeFiles(%v) #go = %+v", binary, item)
+		}
+		if ids := got.Scope("blog").HashList("#c"); !slices.Equal(ids, []int64{1, 2}) {
+			t.Errorf("MergeFiles(%v) scope = %v", binary, ids)
+		}
+
+		// the target may be one of the sources
+		old, _ := os.ReadFile(fn1)
+		held, _ := os.Open(fn1)
+		if err = MergeFiles(fn1, fn1, fn2); nil != err {
+			t.Errorf("MergeFiles() error = %v", err)
+		}
+		// the target was replaced instead of being rewritten
+		data, _ := io.ReadAll(held)
+		held.Close()
+		if !bytes.Equal(old, data) {
+			t.Errorf("MergeFiles(%v) rewrote the target in place", binary)
+		}
+		if matches, _ := filepath.Glob(fn1 + ".*"); 0 != len(matches) {
+			t.Errorf("MergeFiles(%v) left %v", binary, matches)
+		}
+		if _, _ = a.Load(); !a.hm.equals(*want.hm) {
+			t.Errorf("MergeFiles(%v) =\n%v", binary, a.String())
+		}
+	}
+
+	if err := MergeFiles(filepath.Join(dir, "x"), filepath.Join(dir, "missing")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("MergeFiles() error = %v, want %v", err, os.ErrNotExist)
+	}
+} // Test_MergeFiles()
+
+func Test_mergeTextFiles_unsorted(t *testing.T) {
+	saveBinary := UseBinaryStorage
+	defer func() {
+		UseBinaryStorage = saveBinary
+	}()
+	UseBinaryStorage = false
+	dir := t.TempDir()
+	fn1 := filepath.Join(dir, "unsorted.txt")
+	fn2 := filepath.Join(dir, "sorted.txt")
+	target := filepath.Join(dir, "all.txt")
+	_ = os.WriteFile(fn1, []byte("[#zig]\n0000000000000001\n[#go]\n0000000000000001\n"), 0600)
+	_ = os.WriteFile(fn2, []byte("[#go]\n0000000000000002\n"), 0600)
+
+	if err := mergeTextFiles[int64](target, nil, []string{fn1, fn2}); !errors.Is(err, errUnsorted) {
+		t.Errorf("mergeTextFiles() error = %v, want %v", err, errUnsorted)
+	}
+	if err := MergeFiles(target, fn1, fn2); nil != err {
+		t.Fatalf("MergeFiles() error = %v", err)
+	}
+	ht, _ := New(target)
+	if got := ht.HashList("#go"); !slices.Equal(got, []int64{1, 2}) || (1 != ht.HashLen("#zig")) {
+		t.Errorf("MergeFiles() = %v", ht.String())
+	}
+} // Test_mergeTextFiles_unsorted()
+
+/* EoF */
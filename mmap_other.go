@@ -0,0 +1,39 @@
+//go:build !unix
+
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"io"
+	"os"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+// `mapFile()` reads the first `aSize` bytes of `aFile` into memory
+// on platforms without memory mapping.
+//
+// Parameters:
+//   - `aFile`: The file to read.
+//   - `aSize`: The number of bytes to read (`0 < aSize`).
+//
+// Returns:
+//   - `[]byte`: The file's data.
+//   - `func() error`: The function releasing the data.
+//   - `error`: A possible I/O error.
+func mapFile(aFile *os.File, aSize int) ([]byte, func() error, error) {
+	data := make([]byte, aSize)
+	if _, err := io.ReadFull(aFile, data); nil != err {
+		return nil, nil, err
+	}
+
+	return data, func() error { return nil }, nil
+} // mapFile()
+
+/* EoF */
@@ -0,0 +1,41 @@
+//go:build unix
+
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"os"
+	"syscall"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+// `mapFile()` maps the first `aSize` bytes of `aFile` read-only into
+// memory.
+//
+// The mapping remains valid after `aFile` is closed.
+//
+// Parameters:
+//   - `aFile`: The file to map.
+//   - `aSize`: The number of bytes to map (`0 < aSize`).
+//
+// Returns:
+//   - `[]byte`: The mapped data.
+//   - `func() error`: The function releasing the mapping.
+//   - `error`: A possible mapping error.
+func mapFile(aFile *os.File, aSize int) ([]byte, func() error, error) {
+	data, err := syscall.Mmap(int(aFile.Fd()), 0, aSize, syscall.PROT_READ, syscall.MAP_SHARED) //#nosec G115
+	if nil != err {
+		return nil, nil, err
+	}
+
+	return data, func() error { return syscall.Munmap(data) }, nil
+} // mapFile()
+
+/* EoF */
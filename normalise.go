@@ -0,0 +1,238 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `TNormForm` selects the Unicode normalisation form applied
+	// to `#hashtags` and `@mentions`.
+	TNormForm uint8
+
+	// `TCaseFolding` selects how upper and lower case letters of
+	// `#hashtags` and `@mentions` are unified.
+	TCaseFolding uint8
+
+	// `TNormalisation` configures the pipeline used to turn a
+	// `#hashtag` or `@mention` into the key under which it's stored
+	// and looked up.
+	TNormalisation struct {
+		Form        TNormForm    // Unicode normalisation form to apply
+		CaseFolding TCaseFolding // kind of case folding to apply
+		StripMarks  bool         // remove diacritics (e.g. `é` → `e`)
+		FoldWidth   bool         // unify full- and half-width (CJK) forms
+	}
+)
+
+const (
+	// `NormNone` leaves the tags' Unicode representation as is.
+	NormNone TNormForm = iota
+
+	// `NormNFC` applies the canonical composition (NFC).
+	NormNFC
+
+	// `NormNFKC` applies the compatibility composition (NFKC).
+	NormNFKC
+)
+
+const (
+	// `FoldNone` keeps the tags' upper and lower case letters.
+	FoldNone TCaseFolding = iota
+
+	// `FoldLower` lower-cases the tags (i.e. `strings.ToLower()`).
+	FoldLower
+
+	// `FoldFull` applies the full Unicode case folding
+	// (e.g. `Straße` → `strasse`).
+	FoldFull
+
+	// `FoldTurkic` lower-cases the tags using the Turkish and
+	// Azeri rules for the dotted and dotless `i`.
+	FoldTurkic
+)
+
+var (
+	// `gDefaultNorm` is the pipeline used unless [SetNormalisation]
+	// was called: the NFC normalisation form and lower-cased tags.
+	gDefaultNorm = TNormalisation{
+		Form:        NormNFC,
+		CaseFolding: FoldLower,
+	}
+
+	// `gNormalisation` is the pipeline set by [SetNormalisation].
+	gNormalisation atomic.Pointer[TNormalisation]
+)
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `isASCII()` reports whether `aStr` consists of ASCII characters only.
+//
+// Parameters:
+//   - `aStr`: The string to check.
+//
+// Returns:
+//   - `bool`: `true` if there are no multi-byte characters in `aStr`.
+func isASCII(aStr string) bool {
+	for i := 0; i < len(aStr); i++ {
+		if utf8.RuneSelf <= aStr[i] {
+			return false
+		}
+	}
+
+	return true
+} // isASCII()
+
+// `normalise()` applies the current [Normalisation] to `aTag`.
+//
+// Parameters:
+//   - `aTag`: The `#hashtag` or `@mention` to normalise.
+//
+// Returns:
+//   - `string`: The normalised tag.
+func normalise(aTag string) string {
+	if n := gNormalisation.Load(); nil != n {
+		return n.Apply(aTag)
+	}
+
+	return gDefaultNorm.Apply(aTag)
+} // normalise()
+
+// `Normalisation()` returns the pipeline applied to all `#hashtags`
+// and `@mentions` both when inserting and when looking them up.
+//
+// The default applies the NFC normalisation form and lower-cases
+// the tags.
+//
+// Returns:
+//   - `TNormalisation`: The current normalisation pipeline.
+func Normalisation() TNormalisation {
+	if n := gNormalisation.Load(); nil != n {
+		return *n
+	}
+
+	return gDefaultNorm
+} // Normalisation()
+
+// `SetNormalisation()` changes the pipeline applied to all `#hashtags`
+// and `@mentions` (see [Normalisation]).
+//
+// The setting applies to all lists of the program. It should be set
+// once before any list is created or loaded: a list in use while the
+// setting changes may look up some tags with the old and others with
+// the new pipeline.
+//
+// NOTE: Changing this setting doesn't affect the keys already
+// stored in a list; use [THashTags.Renormalise] to migrate
+// a loaded list to the new setting.
+//
+// Parameters:
+//   - `aNorm`: The normalisation pipeline to use.
+func SetNormalisation(aNorm TNormalisation) {
+	gNormalisation.Store(&aNorm)
+} // SetNormalisation()
+
+// -------------------------------------------------------------------------
+// methods of `TNormalisation`:
+
+// `Apply()` runs `aTag` through the configured normalisation pipeline.
+//
+// The steps are applied in this order: width folding, Unicode
+// normalisation, case folding, and removal of diacritical marks.
+//
+// Parameters:
+//   - `aTag`: The `#hashtag` or `@mention` to normalise.
+//
+// Returns:
+//   - `string`: The normalised tag.
+func (n TNormalisation) Apply(aTag string) string {
+	if "" == aTag {
+		return aTag
+	}
+
+	if isASCII(aTag) {
+		// Fast path: all other steps don't change ASCII text.
+		return n.fold(aTag)
+	}
+
+	if n.FoldWidth {
+		aTag = width.Fold.String(aTag)
+	}
+	aTag = n.form(aTag)
+	if FoldNone != n.CaseFolding {
+		// case folding may produce denormalised text
+		aTag = n.form(n.fold(aTag))
+	}
+
+	if n.StripMarks {
+		t := transform.Chain(norm.NFD,
+			runes.Remove(runes.In(unicode.Mn)),
+			norm.NFC)
+		if str, _, err := transform.String(t, aTag); nil == err {
+			aTag = n.form(str)
+		}
+	}
+
+	return aTag
+} // Apply()
+
+// `fold()` applies the configured case folding to `aTag`.
+//
+// Parameters:
+//   - `aTag`: The text to fold.
+//
+// Returns:
+//   - `string`: The case folded text.
+func (n TNormalisation) fold(aTag string) string {
+	switch n.CaseFolding {
+	case FoldLower:
+		return strings.ToLower(aTag)
+
+	case FoldFull:
+		// `cases.Caser` is stateful, hence we can't share it
+		return cases.Fold().String(aTag)
+
+	case FoldTurkic:
+		return strings.ToLowerSpecial(unicode.TurkishCase, aTag)
+	}
+
+	return aTag
+} // fold()
+
+// `form()` applies the configured Unicode normalisation form to `aTag`.
+//
+// Parameters:
+//   - `aTag`: The text to normalise.
+//
+// Returns:
+//   - `string`: The normalised text.
+func (n TNormalisation) form(aTag string) string {
+	switch n.Form {
+	case NormNFC:
+		return norm.NFC.String(aTag)
+
+	case NormNFKC:
+		return norm.NFKC.String(aTag)
+	}
+
+	return aTag
+} // form()
+
+/* EoF */
@@ -0,0 +1,55 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func Test_TNormalisation_Apply(t *testing.T) {
+	nfc := TNormalisation{Form: NormNFC, CaseFolding: FoldLower}
+	full := TNormalisation{Form: NormNFC, CaseFolding: FoldFull}
+	turk := TNormalisation{Form: NormNFC, CaseFolding: FoldTurkic}
+	strip := TNormalisation{Form: NormNFC, CaseFolding: FoldLower, StripMarks: true}
+	wide := TNormalisation{Form: NormNFC, CaseFolding: FoldLower, FoldWidth: true}
+	none := TNormalisation{}
+
+	tests := []struct {
+		name string
+		n    TNormalisation
+		tag  string
+		want string
+	}{
+		{"0", nfc, "", ""},
+		{"1", nfc, "#HashTag", "#hashtag"},
+		{"2", nfc, "#café", "#café"},
+		{"3", nfc, "#Straße", "#straße"},
+		{"4", full, "#Straße", "#strasse"},
+		{"5", full, "#STRASSE", "#strasse"},
+		{"6", turk, "#İstanbul", "#istanbul"},
+		{"7", turk, "#ISPARTA", "#ısparta"},
+		{"8", strip, "#Café", "#cafe"},
+		{"9", strip, "#café", "#cafe"},
+		{"10", wide, "#ＧＯ", "#go"},
+		{"11", none, "#HashTag", "#HashTag"},
+		{"12", TNormalisation{Form: NormNFKC}, "#ﬁle", "#file"},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.n.Apply(tt.tag); got != tt.want {
+				t.Errorf("%q: TNormalisation.Apply() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_TNormalisation_Apply()
+
+/* EoF */
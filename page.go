@@ -0,0 +1,286 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"cmp"
+	"encoding/base64"
+	"errors"
+	"slices"
+
+	se "github.com/mwat56/sourceerror"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `TPageOptions` selects a page of a sorted list.
+	//
+	// The zero value selects the whole list in ascending order.
+	TPageOptions struct {
+		Cursor     string // position returned as `Next` by the previous page
+		Offset     int    // number of items to skip (after `Cursor`, if any)
+		Limit      int    // maximal number of items to return (`0` = all)
+		Descending bool   // whether to page in descending order
+	}
+
+	// `TIDPage` is a page of the IDs associated with a tag.
+	TIDPage[ID cmp.Ordered] struct {
+		IDs   []ID   // the IDs of this page
+		Next  string // cursor of the following page (empty if none)
+		Total int    // total number of IDs associated with the tag
+	}
+
+	// `TCountPage` is a page of the list returned by [TIndex.List].
+	TCountPage struct {
+		Items TCountList // the items of this page
+		Next  string     // cursor of the following page (empty if none)
+		Total int        // total number of tags
+	}
+)
+
+var (
+	// `ErrInvalidCursor` is returned for a cursor not produced by
+	// a previous page of the same list.
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `decodeCursor()` returns the text of the position `aCursor` refers to.
+//
+// Parameters:
+//   - `aCursor`: The cursor to decode.
+//
+// Returns:
+//   - `string`: The position's text.
+//   - `error`: `ErrInvalidCursor` if `aCursor` can't be decoded.
+func decodeCursor(aCursor string) (string, error) {
+	text, err := base64.RawURLEncoding.DecodeString(aCursor)
+	if (nil != err) || (0 == len(text)) {
+		return "", ErrInvalidCursor
+	}
+
+	return string(text), nil
+} // decodeCursor()
+
+// `encodeCursor()` returns an opaque cursor for the position `aText`.
+//
+// Parameters:
+//   - `aText`: The text of the position (i.e. the last item of a page).
+//
+// Returns:
+//   - `string`: The cursor.
+func encodeCursor(aText string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(aText))
+} // encodeCursor()
+
+// `pageOf()` returns a copy of the page of `aList` selected by
+// `aOptions`.
+//
+// Only the items of the page are copied. The cursor's position is
+// given by `aAfter`, i.e. the page starts with the item following
+// (or, if descending, preceding) that value whether or not it is
+// still present in `aList`.
+//
+// Parameters:
+//   - `aList`: The list sorted in ascending order.
+//   - `aAfter`: The position to start after (`nil` = list start).
+//   - `aOptions`: The page's options.
+//
+// Returns:
+//   - `[]T`: The items of the page (or `nil`).
+//   - `bool`: `true` if more items follow the page, or `false` otherwise.
+func pageOf[T cmp.Ordered](aList []T, aAfter *T, aOptions TPageOptions) ([]T, bool) {
+	lo, hi := 0, len(aList) // range of remaining items
+	if nil != aAfter {
+		idx, found := slices.BinarySearch(aList, *aAfter)
+		if aOptions.Descending {
+			hi = idx
+		} else {
+			if found {
+				idx++
+			}
+			lo = idx
+		}
+	}
+
+	offset := max(aOptions.Offset, 0)
+	count := hi - lo - offset
+	if 0 >= count {
+		return nil, false
+	}
+	if (0 < aOptions.Limit) && (aOptions.Limit < count) {
+		count = aOptions.Limit
+	}
+
+	result := make([]T, count)
+	if aOptions.Descending {
+		for i := range result {
+			result[i] = aList[hi-1-offset-i]
+		}
+	} else {
+		copy(result, aList[lo+offset:])
+	}
+
+	return result, (count < hi-lo-offset)
+} // pageOf()
+
+// -------------------------------------------------------------------------
+// methods of `TIndex`:
+
+// `HashPage()` returns a page of the IDs associated with `aHash`.
+//
+// The IDs are sorted in ascending (or descending) order. To get the
+// following page call this method again with the `Next` cursor of the
+// current page; a cursor remains valid even if the list changes in
+// between (IDs added or removed before the cursor's position do not
+// affect the following pages).
+//
+// Parameters:
+//   - `aHash`: The `#hashtag` to lookup.
+//   - `aOptions`: The page's options.
+//
+// Returns:
+//   - `TIDPage[ID]`: The requested page.
+//   - `error`: `ErrInvalidCursor` if the cursor can't be decoded.
+func (ht *TIndex[ID]) HashPage(aHash string, aOptions TPageOptions) (TIDPage[ID], error) {
+	return ht.idPage(MarkHash, aHash, aOptions)
+} // HashPage()
+
+// `idPage()` returns a page of the IDs associated with `aTag`.
+//
+// Parameters:
+//   - `aDelim`: The start of words to search (i.e. either '@' or '#').
+//   - `aTag`: The tag to lookup.
+//   - `aOptions`: The page's options.
+//
+// Returns:
+//   - `TIDPage[ID]`: The requested page.
+//   - `error`: `ErrInvalidCursor` if the cursor can't be decoded.
+func (ht *TIndex[ID]) idPage(aDelim byte, aTag string, aOptions TPageOptions) (TIDPage[ID], error) {
+	var (
+		after  *ID
+		result TIDPage[ID]
+	)
+	if "" != aOptions.Cursor {
+		text, err := decodeCursor(aOptions.Cursor)
+		if nil != err {
+			return result, se.New(err, 2)
+		}
+		id, err := ht.xt.codec().DecodeID(text)
+		if nil != err {
+			return result, se.New(ErrInvalidCursor, 2)
+		}
+		after = &id
+	}
+
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	list := ht.hm.view(aDelim, aTag) // `pageOf()` copies the page
+	ids, more := pageOf(list, after, aOptions)
+	result.IDs, result.Total = ids, len(list)
+	if more {
+		result.Next = encodeCursor(ht.xt.codec().EncodeID(ids[len(ids)-1]))
+	}
+
+	return result, nil
+} // idPage()
+
+// `ListPage()` returns a page of the list returned by [TIndex.List].
+//
+// The items are sorted by tag in ascending (or descending) order.
+// Only the items of the requested page are assembled. To get the
+// following page call this method again with the `Next` cursor of the
+// current page.
+//
+// Parameters:
+//   - `aOptions`: The page's options.
+//
+// Returns:
+//   - `TCountPage`: The requested page.
+//   - `error`: `ErrInvalidCursor` if the cursor can't be decoded.
+func (ht *TIndex[ID]) ListPage(aOptions TPageOptions) (TCountPage, error) {
+	var (
+		after  *string
+		result TCountPage
+	)
+	if "" != aOptions.Cursor {
+		text, err := decodeCursor(aOptions.Cursor)
+		if nil != err {
+			return result, se.New(err, 2)
+		}
+		after = &text
+	}
+
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	keys := ht.sortedKeys()
+	page, more := pageOf(keys, after, aOptions)
+	result.Total = len(keys)
+	if 0 < len(page) {
+		result.Items = make(TCountList, 0, len(page))
+		for _, key := range page {
+			result.Items = append(result.Items,
+				ht.xt.Info.countItem(key, len(*(*ht.hm)[key])))
+		}
+	}
+	if more {
+		result.Next = encodeCursor(page[len(page)-1])
+	}
+
+	return result, nil
+} // ListPage()
+
+// `MentionPage()` returns a page of the IDs associated with `aMention`.
+//
+// See [TIndex.HashPage] for details.
+//
+// Parameters:
+//   - `aMention`: The `@mention` to lookup.
+//   - `aOptions`: The page's options.
+//
+// Returns:
+//   - `TIDPage[ID]`: The requested page.
+//   - `error`: `ErrInvalidCursor` if the cursor can't be decoded.
+func (ht *TIndex[ID]) MentionPage(aMention string, aOptions TPageOptions) (TIDPage[ID], error) {
+	return ht.idPage(MarkMention, aMention, aOptions)
+} // MentionPage()
+
+// `sortedKeys()` returns the list's tags sorted in ascending order.
+//
+// The sorted tags are cached until the list's data change, so that
+// paging through the list doesn't copy and sort all tags per page.
+// The returned slice must not be modified.
+//
+// NOTE: This method expects the caller to hold the list's (read) lock.
+//
+// Returns:
+//   - `[]string`: The sorted tags.
+func (ht *TIndex[ID]) sortedKeys() []string {
+	if keys := ht.cc.keys.Load(); nil != keys {
+		return *keys
+	}
+
+	keys := make([]string, 0, len(*ht.hm))
+	for key := range *ht.hm {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys) // same order as `List()`
+	ht.cc.keys.Store(&keys)
+
+	return keys
+} // sortedKeys()
+
+/* EoF */
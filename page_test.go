@@ -0,0 +1,136 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func Test_pageOf(t *testing.T) {
+	list := []int{1, 3, 5, 7, 9}
+	three, four := 3, 4
+
+	tests := []struct {
+		name     string
+		after    *int
+		options  TPageOptions
+		want     []int
+		wantMore bool
+	}{
+		{"all", nil, TPageOptions{}, list, false},
+		{"limit", nil, TPageOptions{Limit: 2}, []int{1, 3}, true},
+		{"offset", nil, TPageOptions{Offset: 3}, []int{7, 9}, false},
+		{"offset+limit", nil, TPageOptions{Offset: 1, Limit: 3}, []int{3, 5, 7}, true},
+		{"beyond", nil, TPageOptions{Offset: 5}, nil, false},
+		{"after", &three, TPageOptions{Limit: 2}, []int{5, 7}, true},
+		{"after missing", &four, TPageOptions{}, []int{5, 7, 9}, false},
+		{"desc", nil, TPageOptions{Limit: 2, Descending: true}, []int{9, 7}, true},
+		{"desc offset", nil, TPageOptions{Offset: 3, Descending: true}, []int{3, 1}, false},
+		{"desc after", &three, TPageOptions{Descending: true}, []int{1}, false},
+		{"desc after missing", &four, TPageOptions{Limit: 1, Descending: true}, []int{3}, true},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, more := pageOf(list, tt.after, tt.options)
+			if !slices.Equal(got, tt.want) || (more != tt.wantMore) {
+				t.Errorf("%q: pageOf() = %v, %v, want %v, %v",
+					tt.name, got, more, tt.want, tt.wantMore)
+			}
+		})
+	}
+} // Test_pageOf()
+
+func Test_THashTags_HashPage(t *testing.T) {
+	ht, _ := New("")
+	for id := int64(1); 10 >= id; id++ {
+		ht.HashAdd("#go", id)
+	}
+
+	for _, desc := range []bool{true, false} {
+		var got []int64
+		options := TPageOptions{Limit: 3, Descending: desc}
+		for {
+			page, err := ht.HashPage("#GO", options)
+			if nil != err {
+				t.Fatalf("THashTags.HashPage() error = %v", err)
+			}
+			if ("" == options.Cursor) && (10 != page.Total) {
+				t.Errorf("THashTags.HashPage() Total = %d, want 10", page.Total)
+			}
+			got = append(got, page.IDs...)
+			if "" == page.Next {
+				break
+			}
+			options.Cursor = page.Next
+			if !desc {
+				// changes before the cursor don't affect the next pages
+				ht.HashRemove("#go", page.IDs[0])
+			}
+		}
+
+		want := []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+		if desc {
+			slices.Reverse(want)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("THashTags.HashPage(desc=%v) = %v, want %v", desc, got, want)
+		}
+	}
+
+	if _, err := ht.MentionPage("@bob", TPageOptions{Cursor: "!"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("THashTags.MentionPage() error = %v, want %v", err, ErrInvalidCursor)
+	}
+} // Test_THashTags_HashPage()
+
+func Test_THashTags_ListPage(t *testing.T) {
+	ht := prepHT()
+	list := ht.List()
+
+	var got TCountList
+	options := TPageOptions{Limit: 2}
+	for {
+		page, err := ht.ListPage(options)
+		if nil != err {
+			t.Fatalf("THashTags.ListPage() error = %v", err)
+		}
+		if len(list) != page.Total {
+			t.Errorf("THashTags.ListPage() Total = %d, want %d", page.Total, len(list))
+		}
+		got = append(got, page.Items...)
+		if "" == page.Next {
+			break
+		}
+		options.Cursor = page.Next
+	}
+	if !got.Equal(list) {
+		t.Errorf("THashTags.ListPage() =\n%v\nwant\n%v", got, list)
+	}
+
+	page, _ := ht.ListPage(TPageOptions{Limit: 1, Descending: true})
+	if (1 != len(page.Items)) || (list[len(list)-1].Tag != page.Items[0].Tag) {
+		t.Errorf("THashTags.ListPage(desc) = %v, want %v", page.Items, list[len(list)-1])
+	}
+
+	// the cached tags are dropped once the list changes
+	ht.MentionAdd("@zzz", 1)
+	page, _ = ht.ListPage(TPageOptions{Limit: 1, Descending: true})
+	if (len(list)+1 != page.Total) || ("@zzz" != page.Items[0].Tag) {
+		t.Errorf("THashTags.ListPage() after MentionAdd() = %v", page)
+	}
+	ht.MentionRemove("@zzz", 1)
+	if page, _ = ht.ListPage(TPageOptions{}); len(list) != page.Total {
+		t.Errorf("THashTags.ListPage() after MentionRemove() = %v", page)
+	}
+} // Test_THashTags_ListPage()
+
+/* EoF */
@@ -0,0 +1,252 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import "cmp"
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `tPairMap` holds data of type `V` per `#hashtag`/`@mention`
+	// and ID, i.e. per association stored in a `tHashMap`.
+	tPairMap[ID cmp.Ordered, V any] map[string]map[ID]V
+)
+
+// -------------------------------------------------------------------------
+// methods of `tPairMap`:
+
+// `clear()` removes all entries.
+//
+// Returns:
+//   - `*tPairMap[ID, V]`: The cleared map.
+func (pm *tPairMap[ID, V]) clear() *tPairMap[ID, V] {
+	if (nil != pm) && (0 < len(*pm)) {
+		clear(*pm)
+	}
+
+	return pm
+} // clear()
+
+// `dropID()` removes the data of `aID` for all tags.
+//
+// Parameters:
+//   - `aID`: The ID whose data is to be removed.
+func (pm tPairMap[ID, V]) dropID(aID ID) {
+	for key, ids := range pm {
+		delete(ids, aID)
+		if 0 == len(ids) {
+			delete(pm, key)
+		}
+	}
+} // dropID()
+
+// `dropIf()` removes all data for which `aDrop` returns `true`.
+//
+// Parameters:
+//   - `aDrop`: The function deciding whether to remove a value.
+//
+// Returns:
+//   - `int`: The number of values removed.
+func (pm tPairMap[ID, V]) dropIf(aDrop func(aValue V) bool) int {
+	result := 0
+	for key, ids := range pm {
+		for id, v := range ids {
+			if aDrop(v) {
+				delete(ids, id)
+				result++
+			}
+		}
+		if 0 == len(ids) {
+			delete(pm, key)
+		}
+	}
+
+	return result
+} // dropIf()
+
+// `dropPair()` removes the data of `aKey` and `aID`.
+//
+// Parameters:
+//   - `aKey`: The normalised tag to use.
+//   - `aID`: The ID whose data is to be removed.
+func (pm tPairMap[ID, V]) dropPair(aKey string, aID ID) {
+	if ids, ok := pm[aKey]; ok {
+		delete(ids, aID)
+		if 0 == len(ids) {
+			delete(pm, aKey)
+		}
+	}
+} // dropPair()
+
+// `get()` returns the data of `aKey` and `aID`.
+//
+// Parameters:
+//   - `aKey`: The normalised tag to lookup.
+//   - `aID`: The ID to lookup.
+//
+// Returns:
+//   - `V`: The data found (or the zero value).
+//   - `bool`: `true` if data was found, or `false` otherwise.
+func (pm tPairMap[ID, V]) get(aKey string, aID ID) (V, bool) {
+	if ids, ok := pm[aKey]; ok {
+		v, ok := ids[aID]
+		return v, ok
+	}
+
+	var zero V
+	return zero, false
+} // get()
+
+// `merge()` moves the data of all `aSources` to `aTarget`.
+//
+// Data present for both, a source and the target, is combined by
+// calling `aCombine`.
+//
+// Parameters:
+//   - `aSources`: The normalised tags whose data is to be moved.
+//   - `aTarget`: The normalised tag to receive the data.
+//   - `aCombine`: The function to combine two values of the same ID.
+func (pm *tPairMap[ID, V]) merge(aSources []string, aTarget string, aCombine func(a, b V) V) {
+	for _, source := range aSources {
+		if source == aTarget {
+			continue
+		}
+		ids, ok := (*pm)[source]
+		if !ok {
+			continue
+		}
+		delete(*pm, source)
+
+		for id, v := range ids {
+			if old, ok := pm.get(aTarget, id); ok {
+				v = aCombine(old, v)
+			}
+			pm.set(aTarget, id, v)
+		}
+	}
+} // merge()
+
+// `prune()` removes the data of all associations not present
+// in `aMap`.
+//
+// Parameters:
+//   - `aMap`: The hash map whose associations are to be kept.
+func (pm tPairMap[ID, V]) prune(aMap tHashMap[ID]) {
+	for key, ids := range pm {
+		sl, ok := aMap[key]
+		if !ok || (nil == sl) {
+			delete(pm, key)
+			continue
+		}
+		for id := range ids {
+			if 0 > sl.findIndex(id) {
+				delete(ids, id)
+			}
+		}
+		if 0 == len(ids) {
+			delete(pm, key)
+		}
+	}
+} // prune()
+
+// `renameID()` replaces `aOldID` by `aNewID` for all tags.
+//
+// Data present for both IDs is combined by calling `aCombine`.
+//
+// Parameters:
+//   - `aOldID`: The ID to be replaced.
+//   - `aNewID`: The replacement ID.
+//   - `aCombine`: The function to combine two values of the same tag.
+func (pm tPairMap[ID, V]) renameID(aOldID, aNewID ID, aCombine func(a, b V) V) {
+	if aOldID == aNewID {
+		return
+	}
+
+	for _, ids := range pm {
+		v, ok := ids[aOldID]
+		if !ok {
+			continue
+		}
+		delete(ids, aOldID)
+
+		if old, ok := ids[aNewID]; ok {
+			v = aCombine(old, v)
+		}
+		ids[aNewID] = v
+	}
+} // renameID()
+
+// `renormalise()` applies the current [Normalisation] to all keys.
+//
+// Data of keys which become equal are combined by calling `aCombine`.
+//
+// Parameters:
+//   - `aCombine`: The function to combine two values of the same ID.
+func (pm *tPairMap[ID, V]) renormalise(aCombine func(a, b V) V) {
+	if 0 == len(*pm) {
+		return
+	}
+
+	nm := make(tPairMap[ID, V], len(*pm))
+	for key, ids := range *pm {
+		key = normalise(key)
+		for id, v := range ids {
+			if old, ok := nm.get(key, id); ok {
+				v = aCombine(old, v)
+			}
+			nm.set(key, id, v)
+		}
+	}
+	*pm = nm
+} // renormalise()
+
+// `set()` stores `aValue` for `aKey` and `aID`.
+//
+// Parameters:
+//   - `aKey`: The normalised tag to use.
+//   - `aID`: The ID to use.
+//   - `aValue`: The data to store.
+func (pm *tPairMap[ID, V]) set(aKey string, aID ID, aValue V) {
+	if nil == *pm {
+		*pm = make(tPairMap[ID, V], defaultListSize)
+	}
+
+	ids, ok := (*pm)[aKey]
+	if !ok {
+		ids = make(map[ID]V)
+		(*pm)[aKey] = ids
+	}
+	ids[aID] = aValue
+} // set()
+
+// `union()` adds all data of `aOther` to this map.
+//
+// Data present in both maps is combined by calling `aCombine`.
+//
+// Parameters:
+//   - `aOther`: The map whose data is to be added.
+//   - `aCombine`: The function to combine two values of the same pair.
+//
+// Returns:
+//   - `bool`: `true` if at least one pair was added, or `false` otherwise.
+func (pm *tPairMap[ID, V]) union(aOther tPairMap[ID, V], aCombine func(a, b V) V) bool {
+	var result bool
+	for key, ids := range aOther {
+		for id, v := range ids {
+			if old, ok := pm.get(key, id); ok {
+				v = aCombine(old, v)
+			} else {
+				result = true
+			}
+			pm.set(key, id, v)
+		}
+	}
+
+	return result
+} // union()
+
+/* EoF */
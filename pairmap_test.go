@@ -0,0 +1,84 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"slices"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func prepPairMap() tPairMap[int64, []int] {
+	var pm tPairMap[int64, []int]
+	pm.set("#a", 1, []int{1})
+	pm.set("#a", 2, []int{2})
+	pm.set("#b", 1, []int{3})
+	pm.set("#c", 3, []int{4})
+
+	return pm
+} // prepPairMap()
+
+func Test_tPairMap_dropID(t *testing.T) {
+	pm := prepPairMap()
+	pm.dropID(1)
+
+	if _, ok := pm.get("#a", 1); ok {
+		t.Error("tPairMap.dropID() kept #a/1")
+	}
+	if _, ok := pm["#b"]; ok {
+		t.Error("tPairMap.dropID() kept empty #b")
+	}
+	if _, ok := pm.get("#a", 2); !ok {
+		t.Error("tPairMap.dropID() removed #a/2")
+	}
+} // Test_tPairMap_dropID()
+
+func Test_tPairMap_merge(t *testing.T) {
+	pm := prepPairMap()
+	pm.merge([]string{"#a", "#c", "#x"}, "#b", mergeOffsets)
+
+	tests := []struct {
+		name string
+		key  string
+		id   int64
+		want []int
+	}{
+		{"1", "#b", 1, []int{1, 3}},
+		{"2", "#b", 2, []int{2}},
+		{"3", "#b", 3, []int{4}},
+		{"4", "#a", 1, nil},
+		{"5", "#c", 3, nil},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := pm.get(tt.key, tt.id); !slices.Equal(got, tt.want) {
+				t.Errorf("%q: tPairMap.merge() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_tPairMap_merge()
+
+func Test_tPairMap_renameID(t *testing.T) {
+	pm := prepPairMap()
+	pm.renameID(1, 2, mergeOffsets)
+
+	if got, _ := pm.get("#a", 2); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("tPairMap.renameID() #a = %v, want [1 2]", got)
+	}
+	if got, _ := pm.get("#b", 2); !slices.Equal(got, []int{3}) {
+		t.Errorf("tPairMap.renameID() #b = %v, want [3]", got)
+	}
+	if _, ok := pm.get("#a", 1); ok {
+		t.Error("tPairMap.renameID() kept old ID")
+	}
+} // Test_tPairMap_renameID()
+
+/* EoF */
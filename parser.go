@@ -0,0 +1,172 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"bytes"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `tTagMatch` is the position of a `#hashtag` or `@mention`
+	// found in a text.
+	tTagMatch struct {
+		start int // byte offset of the tag's leading mark
+		end   int // byte offset following the tag's last character
+	}
+)
+
+// --------------------------------------------------------------------------
+// parser functions:
+
+// `findTags()` returns the positions of all `#hashtags` and `@mentions`
+// found in `aText`.
+//
+// This function implements the extraction rules used when indexing
+// texts, i.e. it skips URL fragments, Markdown link targets, HTML
+// entities, email addresses and the like.
+//
+// Parameters:
+//   - `aText`: The text to search.
+//
+// Returns:
+//   - `[]tTagMatch`: The positions of the tags found in `aText`.
+func findTags(aText []byte) []tTagMatch {
+	indices := htHashMentionRE.FindAllSubmatchIndex(aText, -1)
+	if 0 == len(indices) {
+		return nil
+	}
+
+	var (
+		end, start int
+		idx        []int
+		match0     []byte
+		tag        []byte
+	)
+	result := make([]tTagMatch, 0, len(indices))
+	for _, idx = range indices {
+		// `match0` is the match including prefix and postfix
+		match0 = aText[idx[0]:idx[1]]
+		start, end = idx[2], idx[3]
+
+		if '_' == aText[end-1] {
+			// '_' can be both, part of the hashtag and italic
+			// markup so we must remove it if it's at the end:
+			end--
+		}
+		if 2 > (end - start) {
+			continue // a mark without a name
+		}
+		tag = aText[start:end]
+
+		if MarkHash == tag[0] {
+			switch match0[len(match0)-1] {
+			case '"':
+				// Double quote following a possible hashtag:
+				// most probably an URL#fragment, so check
+				// whether it's a quoted string:
+				if '"' != match0[0] {
+					continue // URL#fragment
+				}
+
+			case ')':
+				// This is a tricky one: It can either be a
+				// normal right round bracket or the end of
+				// a Markdown link. Here we assume that it's
+				// the latter one and ignore this match:
+				continue
+
+			case '-':
+				// A hyphen at the end of a hashtag:
+				// that's not part of an acceptable tag.
+				continue
+
+			case ';':
+				if htEntityRE.Match(match0) {
+					// leave HTML entities as is
+					continue
+				}
+			} // switch
+
+			if htHyphenRE.Match(tag) {
+				continue
+			}
+		} else if MarkMention == tag[0] {
+			if '.' == match0[len(match0)-1] {
+				// we assume that it's an email address
+				continue
+			}
+		} // if
+
+		result = append(result, tTagMatch{start, end})
+	} // for
+
+	return result
+} // findTags()
+
+// `Highlight()` returns a copy of `aText` with all `#hashtags` and
+// `@mentions` replaced by the result of calling `aWrap` with the
+// respective tag.
+//
+// The tags are identified by the same rules used by [THashTags.IDparse].
+// If `aWrap` is `nil` an unchanged copy of `aText` is returned.
+//
+// Example:
+//
+//	html := Highlight(text, func(aTag []byte) []byte {
+//		return []byte("<mark>" + string(aTag) + "</mark>")
+//	})
+//
+// Parameters:
+//   - `aText`: The text to process.
+//   - `aWrap`: The function returning the replacement for a tag.
+//
+// Returns:
+//   - `[]byte`: The processed text.
+func Highlight(aText []byte, aWrap func(aTag []byte) []byte) []byte {
+	if nil == aWrap {
+		return bytes.Clone(aText)
+	}
+
+	return replaceTags(aText, func(aMatch tTagMatch) []byte {
+		return aWrap(aText[aMatch.start:aMatch.end])
+	})
+} // Highlight()
+
+// `replaceTags()` returns a copy of `aText` with all `#hashtags` and
+// `@mentions` replaced by the result of calling `aFunc`.
+//
+// Parameters:
+//   - `aText`: The text to process.
+//   - `aFunc`: The function returning the replacement for a tag.
+//
+// Returns:
+//   - `[]byte`: The processed text.
+func replaceTags(aText []byte, aFunc func(aMatch tTagMatch) []byte) []byte {
+	matches := findTags(aText)
+	if 0 == len(matches) {
+		return bytes.Clone(aText)
+	}
+
+	var (
+		buf  bytes.Buffer
+		last int
+	)
+	buf.Grow(len(aText) + len(matches)*32) // Estimate size
+
+	for _, match := range matches {
+		buf.Write(aText[last:match.start])
+		buf.Write(aFunc(match))
+		last = match.end
+	}
+	buf.Write(aText[last:])
+
+	return buf.Bytes()
+} // replaceTags()
+
+/* EoF */
@@ -0,0 +1,79 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"slices"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func Test_findTags(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"none", "no tags here", nil},
+		{"hash", "a #tag here", []string{"#tag"}},
+		{"mention", "ask @user today", []string{"@user"}},
+		{"underscore", "some _#italic_ text", []string{"#italic"}},
+		{"url fragment", `<a href="/page#frag">`, nil},
+		{"markdown link", "[see](/page#anchor)", nil},
+		{"entity", "a &#39; quote", nil},
+		{"email", "mail me@example.com.", nil},
+		{"mixed", "#one and @two", []string{"#one", "@two"}},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, m := range findTags([]byte(tt.text)) {
+				got = append(got, tt.text[m.start:m.end])
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("%q: findTags() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_findTags()
+
+func Test_Highlight(t *testing.T) {
+	mark := func(aTag []byte) []byte {
+		return []byte("<mark>" + string(aTag) + "</mark>")
+	}
+
+	tests := []struct {
+		name string
+		text string
+		wrap func([]byte) []byte
+		want string
+	}{
+		{"empty", "", mark, ""},
+		{"nil func", "a #tag", nil, "a #tag"},
+		{"none", "no tags", mark, "no tags"},
+		{"hash", "a #tag here", mark, "a <mark>#tag</mark> here"},
+		{"underscore", "_#tag_", mark, "_<mark>#tag</mark>_"},
+		{"fragment", `<a href="/x#y">@me</a>`, mark, `<a href="/x#y"><mark>@me</mark></a>`},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(Highlight([]byte(tt.text), tt.wrap)); got != tt.want {
+				t.Errorf("%q: Highlight() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_Highlight()
+
+/* EoF */
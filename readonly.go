@@ -0,0 +1,80 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"cmp"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+// --------------------------------------------------------------------------
+// constructor functions:
+
+// `OpenIndexReadOnly()` returns a new read-only `TIndex` instance
+// after reading the given file.
+//
+// A read-only list answers all queries but rejects all modifications:
+// the methods returning a `bool` return `false`, those returning an
+// `error` return `ErrReadOnly`, and the list's file is never written.
+// The file may be read again by calling [TIndex.Load].
+//
+// Parameters:
+//   - `aFilename`: The name of the file to read.
+//   - `aCodec`: The codec for the text format (`nil` = [DefaultCodec]).
+//
+// Returns:
+//   - `*TIndex[ID]`: The new read-only `TIndex` instance.
+//   - `error`: `nil` in case of success, otherwise an error.
+func OpenIndexReadOnly[ID cmp.Ordered](aFilename string, aCodec TIDCodec[ID]) (*TIndex[ID], error) {
+	ht, err := NewIndex(aFilename, aCodec)
+	ht.ro = true
+
+	return ht, err
+} // OpenIndexReadOnly()
+
+// `OpenReadOnly()` returns a new read-only `THashTags` instance
+// after reading the given file.
+//
+// See [OpenIndexReadOnly] for details.
+//
+// Parameters:
+//   - `aFilename`: The name of the file to read.
+//
+// Returns:
+//   - `*THashTags`: The new read-only `THashTags` instance.
+//   - `error`: `nil` in case of success, otherwise an error.
+func OpenReadOnly(aFilename string) (*THashTags, error) {
+	return OpenIndexReadOnly[int64](aFilename, nil)
+} // OpenReadOnly()
+
+// -------------------------------------------------------------------------
+// methods of `TIndex`:
+
+// `readOnly()` reports whether the list (or the list a scope belongs
+// to) rejects modifications.
+//
+// Returns:
+//   - `bool`: `true` if the list is read-only.
+func (ht *TIndex[ID]) readOnly() bool {
+	if nil != ht.root {
+		return ht.root.ro
+	}
+
+	return ht.ro
+} // readOnly()
+
+// `ReadOnly()` reports whether the list rejects modifications (see
+// [OpenIndexReadOnly]).
+//
+// Returns:
+//   - `bool`: `true` if the list is read-only.
+func (ht *TIndex[ID]) ReadOnly() bool {
+	return ht.readOnly()
+} // ReadOnly()
+
+/* EoF */
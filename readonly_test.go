@@ -0,0 +1,85 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func Test_OpenReadOnly(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "readonly.db")
+	ht, _ := New(fn)
+	ht.IDparse(1, []byte("#go and @bob"))
+	if _, err := ht.Store(); nil != err {
+		t.Fatalf("THashTags.Store() error = %v", err)
+	}
+	info1, _ := os.Stat(fn)
+
+	ro, err := OpenReadOnly(fn)
+	if nil != err {
+		t.Fatalf("OpenReadOnly() error = %v", err)
+	}
+	if !ro.ReadOnly() || ht.ReadOnly() {
+		t.Error("THashTags.ReadOnly() failed")
+	}
+	if got := ro.HashList("#go"); !slices.Equal(got, []int64{1}) {
+		t.Errorf("THashTags.HashList() = %v, want [1]", got)
+	}
+
+	tests := []struct {
+		name string
+		fn   func() bool
+	}{
+		{"HashAdd", func() bool { return ro.HashAdd("#rust", 2) }},
+		{"HashRemove", func() bool { return ro.HashRemove("#go", 1) }},
+		{"IDparse", func() bool { return ro.IDparse(2, []byte("#zig")) }},
+		{"IDremove", func() bool { return ro.IDremove(1) }},
+		{"IDrename", func() bool { return ro.IDrename(1, 2) }},
+		{"IDupdate", func() bool { return ro.IDupdate(1, []byte("#zig")) }},
+		{"MentionAdd", func() bool { return ro.MentionAdd("@alice", 2) }},
+		{"MentionRemove", func() bool { return ro.MentionRemove("@bob", 1) }},
+		{"Renormalise", func() bool { return ro.Renormalise() }},
+		{"SetPinned", func() bool { return ro.SetPinned("#go", true) }},
+		{"TagRename", func() bool { return ro.TagRename("#go", "#golang") }},
+		{"Scope", func() bool { return ro.Scope("news").HashAdd("#go", 3) }},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.fn() {
+				t.Errorf("THashTags.%s() modified a read-only list", tt.name)
+			}
+		})
+	}
+
+	ro.Clear()
+	ro.List()
+	if (2 != ro.Len()) || (0 != ro.Prune()) {
+		t.Errorf("THashTags.Clear() modified a read-only list: %d", ro.Len())
+	}
+	if err = ro.AddHash(context.Background(), "#rust", 2); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("THashTags.AddHash() error = %v, want %v", err, ErrReadOnly)
+	}
+	if _, err = ro.Store(); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("THashTags.Store() error = %v, want %v", err, ErrReadOnly)
+	}
+	if _, err = ro.Repair(); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("THashTags.Repair() error = %v, want %v", err, ErrReadOnly)
+	}
+	if info2, _ := os.Stat(fn); !info1.ModTime().Equal(info2.ModTime()) {
+		t.Error("read-only list wrote its file")
+	}
+} // Test_OpenReadOnly()
+
+/* EoF */
@@ -0,0 +1,259 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	se "github.com/mwat56/sourceerror"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `TDocumentSource` yields the documents to index by [TIndex.Rebuild].
+	//
+	// The function calls `aYield` for each document, stopping once
+	// `aYield` returns `false`, and returns the error (if any) which
+	// prevented it from reading all documents.
+	TDocumentSource[ID cmp.Ordered] func(aYield func(aID ID, aText []byte) bool) error
+
+	// `TRebuildProgress` describes the state of a list's rebuild.
+	TRebuildProgress struct {
+		Started   time.Time // the start of the current (or last) rebuild
+		Documents int       // the number of documents parsed so far
+		Running   bool      // flag whether a rebuild is running
+	}
+
+	// `tDocument` is a single document passed to a rebuild's workers.
+	tDocument[ID cmp.Ordered] struct {
+		id   ID     // the document's ID
+		text []byte // the document's text
+	}
+
+	// `tRebuildPart` holds the tags and IDs parsed by a single worker.
+	tRebuildPart[ID cmp.Ordered] struct {
+		hm *tHashMap[ID] // the tags and IDs found
+		xt *tExtras[ID]  // the tags' additional data
+	}
+
+	// `tRebuildState` holds the progress of a list's rebuild.
+	//
+	// NOTE: All fields are accessed atomically.
+	tRebuildState struct {
+		docs    int64  // number of documents parsed
+		start   int64  // start of the rebuild (Unix nanoseconds)
+		running uint32 // flag whether a rebuild is running
+	}
+)
+
+var (
+	// `ErrRebuilding` is returned if [TIndex.Rebuild] is called while
+	// another rebuild of the list is running.
+	ErrRebuilding = errors.New("rebuild already running")
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newRebuildPart()` returns a new and empty `tRebuildPart` instance.
+//
+// Returns:
+//   - `*tRebuildPart[ID]`: The new `tRebuildPart` instance.
+func newRebuildPart[ID cmp.Ordered]() *tRebuildPart[ID] {
+	return &tRebuildPart[ID]{
+		hm: newHashMap[ID](),
+		xt: newExtras[ID](),
+	}
+} // newRebuildPart()
+
+// -------------------------------------------------------------------------
+// methods of `tRebuildPart`:
+
+// `parse()` adds `aID` to the lists of all tags found in `aText`.
+//
+// Parameters:
+//   - `aID`: The ID of the document.
+//   - `aText`: The text of the document.
+//   - `aRules`: The validation rules the tags have to satisfy.
+//   - `aPos`: Flag whether to record the tags' positions.
+//   - `aStamp`: Flag whether to record the association times.
+//   - `aNow`: The time of the associations.
+func (rp *tRebuildPart[ID]) parse(aID ID, aText []byte, aRules *tValidator, aPos, aStamp bool, aNow time.Time) {
+	matches := findTags(aText)
+	if 0 == len(matches) {
+		return
+	}
+
+	var offsets map[string][]int
+	if aPos {
+		offsets = make(map[string][]int, len(matches))
+	}
+	for _, match := range matches {
+		tag := string(aText[match.start:match.end])
+		key := normalise(tag)
+		if !aRules.isValid(key) {
+			continue
+		}
+
+		rp.xt.Info.note(tag)
+		if ti := rp.xt.Info.info(key); ti.Created.IsZero() {
+			ti.Created, ti.Modified = aNow, aNow
+		}
+		if rp.hm.insert(tag, aID) && aStamp {
+			rp.xt.Times.set(key, aID, aNow)
+		}
+		if nil != offsets {
+			offsets[key] = append(offsets[key], match.start)
+		}
+	}
+
+	for key, offs := range offsets {
+		rp.xt.Pos.set(key, aID, offs)
+	}
+} // parse()
+
+// -------------------------------------------------------------------------
+// methods of `TIndex`:
+
+// `Rebuild()` replaces the list's tags and IDs by the ones found in
+// the documents yielded by `aSource`, e.g. after the extraction rules
+// or the [Normalisation] setting were changed.
+//
+// The documents are parsed concurrently by `aWorkers` goroutines
+// without holding the list's lock, i.e. the list remains usable (and
+// unchanged) while the documents are read. Once all documents were
+// parsed, the new data replace the old ones in one step. If `aCtx`
+// is done or `aSource` fails before, the list remains unchanged.
+//
+// The metadata of tags found again (e.g. their descriptions) and the
+// association times of associations found again are kept. The list's
+// scopes are not touched. The rebuild isn't recorded in the list's
+// history but discards it (see [TIndex.SetHistory]).
+//
+// The progress can be watched by calling [TIndex.RebuildProgress].
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//   - `aSource`: The function yielding all documents to index.
+//   - `aWorkers`: The number of parsing goroutines (`0` = one per CPU).
+//
+// Returns:
+//   - `error`: `nil` in case of success, or `ErrRebuilding`, `ErrAutoSave`, `ErrReadOnly`, the source's or the context's error.
+func (ht *TIndex[ID]) Rebuild(aCtx context.Context, aSource TDocumentSource[ID], aWorkers int) error {
+	if ht.readOnly() {
+		return se.New(ErrReadOnly, 1)
+	}
+	if nil == aSource {
+		return se.New(ErrUnchanged, 1)
+	}
+	if err := aCtx.Err(); nil != err {
+		return se.New(err, 1)
+	}
+	if !atomic.CompareAndSwapUint32(&ht.rb.running, 0, 1) {
+		return se.New(ErrRebuilding, 1)
+	}
+	defer atomic.StoreUint32(&ht.rb.running, 0)
+
+	now := time.Now()
+	atomic.StoreInt64(&ht.rb.docs, 0)
+	atomic.StoreInt64(&ht.rb.start, now.UnixNano())
+	if 0 >= aWorkers {
+		aWorkers = runtime.GOMAXPROCS(0)
+	}
+
+	if ht.safe {
+		ht.mtx.RLock()
+	}
+	rules, pos, stamp := ht.vr, ht.pos, ht.stamp
+	if ht.safe {
+		ht.mtx.RUnlock()
+	}
+
+	var wg sync.WaitGroup
+	docs := make(chan tDocument[ID], aWorkers*4)
+	parts := make([]*tRebuildPart[ID], aWorkers)
+	for idx := range parts {
+		parts[idx] = newRebuildPart[ID]()
+		wg.Add(1)
+		go func(aPart *tRebuildPart[ID]) {
+			defer wg.Done()
+			for doc := range docs {
+				if nil != aCtx.Err() {
+					continue // drain the channel
+				}
+				aPart.parse(doc.id, doc.text, rules, pos, stamp, now)
+				atomic.AddInt64(&ht.rb.docs, 1)
+			}
+		}(parts[idx])
+	}
+
+	err := aSource(func(aID ID, aText []byte) bool {
+		select {
+		case <-aCtx.Done():
+			return false
+		case docs <- tDocument[ID]{id: aID, text: bytes.Clone(aText)}:
+			return true
+		}
+	})
+	close(docs)
+	wg.Wait()
+	if nil != err {
+		return se.New(err, 1)
+	}
+
+	result := parts[0]
+	for _, part := range parts[1:] {
+		result.hm.union(*part.hm)
+		result.xt.union(part.xt)
+	}
+
+	if err = ht.lockContext(aCtx); nil != err {
+		return err // already wrapped
+	}
+	if ht.safe {
+		defer ht.mtx.Unlock()
+	}
+
+	ht.xt.Info.prune(result.hm.has)
+	ht.xt.Info.union(result.xt.Info)
+	result.xt.Times.union(ht.xt.Times, earlierTime)
+	result.xt.Times.prune(*result.hm)
+	ht.xt.Times = result.xt.Times
+	ht.xt.Pos = result.xt.Pos
+	*ht.hm = *result.hm
+	ht.updated()
+	ht.hs.reset()
+
+	return ht.autoSave()
+} // Rebuild()
+
+// `RebuildProgress()` returns the progress of the list's current (or
+// last) rebuild (see [TIndex.Rebuild]).
+//
+// Returns:
+//   - `TRebuildProgress`: The state of the rebuild.
+func (ht *TIndex[ID]) RebuildProgress() TRebuildProgress {
+	result := TRebuildProgress{
+		Documents: int(atomic.LoadInt64(&ht.rb.docs)),
+		Running:   1 == atomic.LoadUint32(&ht.rb.running),
+	}
+	if start := atomic.LoadInt64(&ht.rb.start); 0 != start {
+		result.Started = time.Unix(0, start)
+	}
+
+	return result
+} // RebuildProgress()
+
+/* EoF */
@@ -0,0 +1,119 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+// `sliceSource()` returns a document source yielding `aTexts` with
+// their indices as IDs.
+func sliceSource(aTexts []string) TDocumentSource[int64] {
+	return func(aYield func(aID int64, aText []byte) bool) error {
+		buf := make([]byte, 0, 64)
+		for idx, text := range aTexts {
+			// reusing the buffer like e.g. `bufio.Scanner` does
+			buf = append(buf[:0], text...)
+			if !aYield(int64(idx), buf) {
+				break
+			}
+		}
+		return nil
+	}
+} // sliceSource()
+
+func Test_THashTags_Rebuild(t *testing.T) {
+	texts := make([]string, 100)
+	for idx := range texts {
+		texts[idx] = fmt.Sprintf("#Tag%d and #all and @user%d", idx%7, idx%3)
+	}
+	want, _ := New("")
+	for idx, text := range texts {
+		want.IDparse(int64(idx), []byte(text))
+	}
+
+	ht := setList(map[string][]int64{"#old": {1}, "#all": {1000}})
+	ht.SetDescription("#all", "everything")
+	ht.SetTimestamps(true)
+	ht.Scope("blog").HashAdd("#go", 1)
+
+	for _, workers := range []int{0, 1, 3} {
+		if err := ht.Rebuild(context.Background(), sliceSource(texts), workers); nil != err {
+			t.Fatalf("THashTags.Rebuild(%d) error = %v", workers, err)
+		}
+		if !ht.hm.equals(*want.hm) {
+			t.Errorf("THashTags.Rebuild(%d) =\n%v\n>>>> want >>>>\n%v", workers, ht.String(), want.String())
+		}
+	}
+	if item, _ := ht.TagItem("#tag1"); "#Tag1" != item.Display {
+		t.Errorf("THashTags.Rebuild() #tag1 = %+v", item)
+	}
+	if item, _ := ht.TagItem("#all"); "everything" != item.Description {
+		t.Errorf("THashTags.Rebuild() #all = %+v", item)
+	}
+	if _, ok := ht.xt.Times.get("#all", 99); !ok {
+		t.Error("THashTags.Rebuild() recorded no association time")
+	}
+	if 1 != ht.Scope("blog").HashLen("#go") {
+		t.Errorf("THashTags.Rebuild() scope = %v", ht.Scope("blog").String())
+	}
+	if p := ht.RebuildProgress(); p.Running || (len(texts) != p.Documents) || p.Started.IsZero() {
+		t.Errorf("THashTags.RebuildProgress() = %+v", p)
+	}
+} // Test_THashTags_Rebuild()
+
+func Test_THashTags_Rebuild_errors(t *testing.T) {
+	ht := setList(map[string][]int64{"#old": {1}})
+	errSource := errors.New("source failed")
+	failing := func(aYield func(aID int64, aText []byte) bool) error {
+		aYield(2, []byte("#new"))
+		return errSource
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	later, stop := context.WithCancel(context.Background())
+	stopping := func(aYield func(aID int64, aText []byte) bool) error {
+		for id := int64(2); aYield(id, []byte("#new")); id++ {
+			if 10 == id {
+				stop()
+			}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		source  TDocumentSource[int64]
+		wantErr error
+	}{
+		{"source", context.Background(), failing, errSource},
+		{"canceled", ctx, sliceSource([]string{"#new"}), context.Canceled},
+		{"canceled later", later, stopping, context.Canceled},
+		{"nil", context.Background(), nil, ErrUnchanged},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ht.Rebuild(tt.ctx, tt.source, 2); !errors.Is(err, tt.wantErr) {
+				t.Errorf("THashTags.Rebuild() error = %v, want %v", err, tt.wantErr)
+			}
+			// the list remains unchanged
+			if got := ht.HashList("#old"); !slices.Equal(got, []int64{1}) || (1 != ht.Len()) {
+				t.Errorf("THashTags.Rebuild() = %v", ht.String())
+			}
+		})
+	}
+} // Test_THashTags_Rebuild_errors()
+
+/* EoF */
@@ -0,0 +1,291 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `tScope` holds the data of a single scope (namespace) of a
+	// `THashTags` list.
+	//
+	// NOTE: All fields must be exported to allow for `gob` encoding.
+	tScope[ID cmp.Ordered] struct {
+		Map    *tHashMap[ID] // the scope's map of tags and IDs
+		Extras *tExtras[ID]  // the scope's optional data
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newScope()` returns a new and empty `tScope` instance.
+//
+// Returns:
+//   - `*tScope[ID]`: The new `tScope` instance.
+func newScope[ID cmp.Ordered]() *tScope[ID] {
+	return &tScope[ID]{
+		Map:    newHashMap[ID](),
+		Extras: newExtras[ID](),
+	}
+} // newScope()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `scopeName()` prepares `aName` for use as a scope's name.
+//
+// Parameters:
+//   - `aName`: The name to check.
+//
+// Returns:
+//   - `string`: The trimmed name, or an empty string if it's invalid.
+func scopeName(aName string) string {
+	if aName = strings.TrimSpace(aName); strings.ContainsAny(aName, "\r\n") {
+		return ""
+	}
+
+	return aName
+} // scopeName()
+
+// -------------------------------------------------------------------------
+// methods of `tExtras`:
+
+// `renormaliseScopes()` applies the current [Normalisation] to all
+// keys of all scopes.
+//
+// Returns:
+//   - `bool`: `true` if at least one tag was changed, or `false` otherwise.
+func (xt *tExtras[ID]) renormaliseScopes() bool {
+	result := false
+	for _, sc := range xt.Scopes {
+		if sc.Map.renormalise() {
+			sc.Extras.renormalise()
+			result = true
+		}
+	}
+
+	return result
+} // renormaliseScopes()
+
+// `scope()` returns the data of the scope `aName`, creating it if
+// necessary.
+//
+// Parameters:
+//   - `aName`: The name of the scope.
+//
+// Returns:
+//   - `*tScope[ID]`: The data of the scope.
+func (xt *tExtras[ID]) scope(aName string) *tScope[ID] {
+	if nil == xt.Scopes {
+		xt.Scopes = make(map[string]*tScope[ID])
+	}
+
+	sc, ok := xt.Scopes[aName]
+	if !ok {
+		sc = newScope[ID]()
+		xt.Scopes[aName] = sc
+	}
+	// `gob` doesn't transmit empty values
+	if nil == sc.Map {
+		sc.Map = newHashMap[ID]()
+	}
+	if nil == sc.Extras {
+		sc.Extras = newExtras[ID]()
+	}
+	sc.Extras.ic = xt.ic
+
+	return sc
+} // scope()
+
+// `writeScopes()` appends the data of all non-empty scopes in the
+// plain text format to `aBuf`.
+//
+// Parameters:
+//   - `aBuf`: The buffer to write to.
+func (xt *tExtras[ID]) writeScopes(aBuf *bytes.Buffer) {
+	names := make([]string, 0, len(xt.Scopes))
+	for name, sc := range xt.Scopes {
+		if (nil != sc.Map) && (0 < len(*sc.Map)) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		sc := xt.Scopes[name]
+		aBuf.WriteString(fmt.Sprintf("%c%s}\n", textScopeMark, name))
+
+		scanner := bufio.NewScanner(strings.NewReader(sc.Map.text(sc.Extras)))
+		for scanner.Scan() {
+			aBuf.WriteString(fmt.Sprintf("%c%s\n", textScopeLine, scanner.Text()))
+		}
+	}
+} // writeScopes()
+
+// -------------------------------------------------------------------------
+// methods of `TIndex`:
+
+// `rebindScopes()` connects all scope instances handed out by [Scope]
+// with the current scope data, e.g. after loading or clearing the list.
+func (ht *TIndex[ID]) rebindScopes() {
+	for name, child := range ht.sh {
+		sc := ht.xt.scope(name)
+		child.hm, child.xt = sc.Map, sc.Extras
+		child.cc = tCountCache{}
+		atomic.StoreUint32(&child.changed, 0)
+	}
+} // rebindScopes()
+
+// `Scope()` returns the scope (namespace) `aName` of the list.
+//
+// A scope is an independent index of `#hashtags` and `@mentions` with
+// its own IDs, e.g. for one of several blogs hosted by a program. All
+// scopes are guarded by the list's lock and stored in the list's file
+// by [Store] (calling [Store] or [Load] for a scope acts on the whole
+// list). A new scope inherits the list's settings (validation,
+// positional and timestamp mode, retention).
+//
+// The empty name denotes the list itself; calling this method for a
+// scope returns the scope `aName` of the list the scope belongs to.
+//
+// Parameters:
+//   - `aName`: The name of the scope.
+//
+// Returns:
+//   - `*TIndex[ID]`: The scope's index, or `nil` if `aName` is invalid.
+func (ht *TIndex[ID]) Scope(aName string) *TIndex[ID] {
+	if nil != ht.root {
+		return ht.root.Scope(aName)
+	}
+	name := scopeName(aName)
+	if "" == name {
+		if "" != strings.TrimSpace(aName) {
+			return nil // invalid name
+		}
+		return ht
+	}
+
+	if ht.safe {
+		ht.mtx.Lock()
+		defer ht.mtx.Unlock()
+	}
+
+	if child, ok := ht.sh[name]; ok {
+		return child
+	}
+
+	sc := ht.xt.scope(name)
+	child := &TIndex[ID]{
+		mtx:   ht.mtx,
+		hm:    sc.Map,
+		xt:    sc.Extras,
+		vr:    ht.vr,
+		root:  ht,
+		scope: name,
+		rt:    ht.rt,
+		pos:   ht.pos,
+		safe:  ht.safe,
+		stamp: ht.stamp,
+		hs:    tHistory[ID]{size: ht.hs.size},
+	}
+	if nil == ht.sh {
+		ht.sh = make(map[string]*TIndex[ID])
+	}
+	ht.sh[name] = child
+
+	return child
+} // Scope()
+
+// `ScopeName()` returns the name of this scope (see [Scope]).
+//
+// Returns:
+//   - `string`: The scope's name, or an empty string for the list itself.
+func (ht *TIndex[ID]) ScopeName() string {
+	return ht.scope
+} // ScopeName()
+
+// `Scopes()` returns the names of all scopes holding any tags.
+//
+// Returns:
+//   - `[]string`: The sorted list of scope names.
+func (ht *TIndex[ID]) Scopes() []string {
+	if nil != ht.root {
+		return ht.root.Scopes()
+	}
+
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	result := make([]string, 0, len(ht.xt.Scopes))
+	for name, sc := range ht.xt.Scopes {
+		if (nil != sc.Map) && (0 < len(*sc.Map)) {
+			result = append(result, name)
+		}
+	}
+	slices.Sort(result)
+
+	return result
+} // Scopes()
+
+// `TagScopes()` returns the IDs associated with `aTag` in all scopes
+// of the list.
+//
+// The IDs of the list itself are returned with the empty scope name.
+// Tags without a leading mark are considered `#hashtags`.
+//
+// Parameters:
+//   - `aTag`: The `#hashtag` or `@mention` to lookup.
+//
+// Returns:
+//   - `map[string][]ID`: The IDs per scope (or `nil`).
+func (ht *TIndex[ID]) TagScopes(aTag string) map[string][]ID {
+	if nil != ht.root {
+		return ht.root.TagScopes(aTag)
+	}
+	if aTag = tagName(aTag); "" == aTag {
+		return nil
+	}
+
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	key := normalise(aTag)
+	var result map[string][]ID
+	add := func(aName string, aMap *tHashMap[ID]) {
+		if sl, ok := (*aMap)[key]; ok && (0 < len(*sl)) {
+			if nil == result {
+				result = make(map[string][]ID)
+			}
+			result[aName] = slices.Clone(*sl)
+		}
+	}
+
+	add("", ht.hm)
+	for name, sc := range ht.xt.Scopes {
+		if nil != sc.Map {
+			add(name, sc.Map)
+		}
+	}
+
+	return result
+} // TagScopes()
+
+/* EoF */
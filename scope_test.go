@@ -0,0 +1,134 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func Test_THashTags_Scope(t *testing.T) {
+	ht := prepHT()
+
+	if got := ht.Scope(""); got != ht {
+		t.Error("THashTags.Scope(\"\") didn't return the list itself")
+	}
+	if got := ht.Scope("bad\nname"); nil != got {
+		t.Error("THashTags.Scope() accepted an invalid name")
+	}
+
+	blog1 := ht.Scope(" blog1 ")
+	if (nil == blog1) || ("blog1" != blog1.ScopeName()) {
+		t.Errorf("THashTags.Scope() = %v", blog1)
+		return
+	}
+	if got := blog1.Scope("blog1"); got != blog1 {
+		t.Error("THashTags.Scope() returned a different instance")
+	}
+	if got := blog1.Scope("blog2"); got != ht.Scope("blog2") {
+		t.Error("THashTags.Scope() of a scope doesn't use the list's scopes")
+	}
+
+	hLen := ht.Len()
+	blog1.IDparse(1, []byte("#Go in blog1"))
+	blog1.IDparse(2, []byte("#go and @Bob"))
+	ht.Scope("blog2").IDparse(1, []byte("#go in blog2"))
+
+	if got := ht.Len(); got != hLen {
+		t.Errorf("THashTags.Len() = %d, want %d", got, hLen)
+	}
+	if got := blog1.Len(); 2 != got {
+		t.Errorf("THashTags.Scope().Len() = %d, want 2", got)
+	}
+	if got := blog1.HashList("#GO"); !slices.Equal(got, []int64{1, 2}) {
+		t.Errorf("THashTags.Scope().HashList() = %v", got)
+	}
+	if got := len(blog1.List()); 2 != got {
+		t.Errorf("THashTags.Scope().List() = %d items, want 2", got)
+	}
+	if got := ht.Scopes(); !slices.Equal(got, []string{"blog1", "blog2"}) {
+		t.Errorf("THashTags.Scopes() = %v", got)
+	}
+} // Test_THashTags_Scope()
+
+func Test_THashTags_Scope_store(t *testing.T) {
+	saveBinary := UseBinaryStorage
+	defer func() {
+		UseBinaryStorage = saveBinary
+	}()
+
+	for _, binary := range []bool{false, true} {
+		UseBinaryStorage = binary
+
+		ht, _ := New(filepath.Join(t.TempDir(), "scopes.db"))
+		ht.IDparse(9, []byte("#root only"))
+		blog := ht.Scope("blog")
+		blog.SetPositional(true)
+		blog.IDparse(1, []byte("Some #Go news"))
+
+		if _, err := blog.Store(); nil != err { // stores the whole list
+			t.Errorf("THashTags.Store() error = %v", err)
+			return
+		}
+
+		ht2, err := New(ht.Filename())
+		if nil != err {
+			t.Errorf("New() error = %v", err)
+			return
+		}
+		blog2 := ht2.Scope("blog")
+		if got := blog2.HashList("#go"); !slices.Equal(got, []int64{1}) {
+			t.Errorf("scope after loading (binary: %v) = %v", binary, got)
+		}
+		if got := blog2.DisplayName("#go"); "#Go" != got {
+			t.Errorf("scope display after loading (binary: %v) = %q", binary, got)
+		}
+		if got := blog2.Occurrences("#go", 1); !slices.Equal(got, []int{5}) {
+			t.Errorf("scope offsets after loading (binary: %v) = %v", binary, got)
+		}
+		if got := ht2.HashList("#root"); !slices.Equal(got, []int64{9}) {
+			t.Errorf("list after loading (binary: %v) = %v", binary, got)
+		}
+		if got := ht2.HashLen("#go"); -1 != got {
+			t.Errorf("scope leaked into list (binary: %v): %d", binary, got)
+		}
+
+		// handed out scopes stay valid after reloading
+		blog.HashAdd("#extra", 2)
+		if _, err = ht.Load(); nil != err {
+			t.Errorf("THashTags.Load() error = %v", err)
+		}
+		if got := blog.HashLen("#extra"); -1 != got {
+			t.Errorf("scope not rebound after loading (binary: %v): %d", binary, got)
+		}
+		if got := blog.HashLen("#go"); 1 != got {
+			t.Errorf("scope lost data after loading (binary: %v): %d", binary, got)
+		}
+	}
+} // Test_THashTags_Scope_store()
+
+func Test_THashTags_TagScopes(t *testing.T) {
+	ht := prepHT()
+	ht.HashAdd("#shared", 7)
+	ht.Scope("a").HashAdd("#Shared", 1)
+	ht.Scope("b").IDparse(2, []byte("#shared #other"))
+	ht.Scope("c").IDparse(3, []byte("#other"))
+
+	got := ht.Scope("c").TagScopes("shared")
+	if (3 != len(got)) || !slices.Equal(got[""], []int64{7}) ||
+		!slices.Equal(got["a"], []int64{1}) || !slices.Equal(got["b"], []int64{2}) {
+		t.Errorf("THashTags.TagScopes() = %v", got)
+	}
+	if got := ht.TagScopes("#unknown"); nil != got {
+		t.Errorf("THashTags.TagScopes() unknown = %v", got)
+	}
+} // Test_THashTags_TagScopes()
+
+/* EoF */
@@ -8,16 +8,15 @@ package hashtags
 
 import (
 	"bytes"
-	"fmt"
+	"cmp"
 	"slices"
-	"strings"
 )
 
 //lint:file-ignore ST1017 - I prefer Yoda conditions
 
 type (
 	// `tSourceList` is storing the IDs using a certain #hashtag/@mention.
-	tSourceList []int64
+	tSourceList[ID cmp.Ordered] []ID
 )
 
 // --------------------------------------------------------------------------
@@ -28,9 +27,9 @@ type (
 // The initial capacity of the list is set to 64 to optimise memory usage.
 //
 // Returns:
-//   - `*tSourceList`: A pointer to the newly created instance.
-func newSourceList() *tSourceList {
-	sl := make(tSourceList, 0, 64)
+//   - `*tSourceList[ID]`: A pointer to the newly created instance.
+func newSourceList[ID cmp.Ordered]() *tSourceList[ID] {
+	sl := make(tSourceList[ID], 0, 64)
 
 	return &sl
 } // newSourceList()
@@ -41,8 +40,8 @@ func newSourceList() *tSourceList {
 // `clear()` removes all entries in this list.
 //
 // Returns:
-//   - `*tSourceList`: A pointer to the updated sources list.
-func (sl *tSourceList) clear() *tSourceList {
+//   - `*tSourceList[ID]`: A pointer to the updated sources list.
+func (sl *tSourceList[ID]) clear() *tSourceList[ID] {
 	if nil != sl {
 		if sLen := len(*sl); 0 < sLen {
 			(*sl) = (*sl)[:0]
@@ -53,6 +52,46 @@ func (sl *tSourceList) clear() *tSourceList {
 	return sl
 } // clear()
 
+// `diff()` compares this list with `aList`.
+//
+// Both lists are expected to be sorted in ascending order, so the
+// differences can be found in a single pass over both lists.
+//
+// Parameters:
+//   - `aList`: The sorted list of IDs to compare with.
+//
+// Returns:
+//   - `[]ID`: The IDs only present in `aList`.
+//   - `[]ID`: The IDs only present in this list.
+func (sl tSourceList[ID]) diff(aList tSourceList[ID]) ([]ID, []ID) {
+	var (
+		added, removed []ID
+		i, j           int
+	)
+	sLen, aLen := len(sl), len(aList)
+	for (i < sLen) || (j < aLen) {
+		switch {
+		case j == aLen:
+			removed = append(removed, sl[i])
+			i++
+		case i == sLen:
+			added = append(added, aList[j])
+			j++
+		case sl[i] < aList[j]:
+			removed = append(removed, sl[i])
+			i++
+		case sl[i] > aList[j]:
+			added = append(added, aList[j])
+			j++
+		default: // same ID in both lists
+			i++
+			j++
+		}
+	}
+
+	return added, removed
+} // diff()
+
 // `equals()` returns whether the current source list is equal
 // to the provided source list.
 //
@@ -61,7 +100,7 @@ func (sl *tSourceList) clear() *tSourceList {
 //
 // Returns:
 //   - `bool`: Whether the source lists are equal.
-func (sl tSourceList) equals(aList tSourceList) bool {
+func (sl tSourceList[ID]) equals(aList tSourceList[ID]) bool {
 	return slices.Equal(sl, aList)
 } // equals()
 
@@ -72,7 +111,7 @@ func (sl tSourceList) equals(aList tSourceList) bool {
 //
 // Returns:
 //   - `int`: The index of `aID` in the list.
-func (sl tSourceList) findIndex(aID int64) int {
+func (sl tSourceList[ID]) findIndex(aID ID) int {
 	sLen := len(sl)
 	if 0 == sLen { // empty list
 		return -1
@@ -98,7 +137,7 @@ func (sl tSourceList) findIndex(aID int64) int {
 //
 // Returns:
 //   - `bool`: `true` if `aID` was inserted, or `false` otherwise.
-func (sl *tSourceList) insert(aID int64) bool {
+func (sl *tSourceList[ID]) insert(aID ID) bool {
 	if nil == sl {
 		return false
 	}
@@ -116,7 +155,8 @@ func (sl *tSourceList) insert(aID int64) bool {
 		return true
 	}
 	if (*sl)[idx] != aID {
-		*sl = append(*sl, 0) // make room to insert new ID
+		var zero ID
+		*sl = append(*sl, zero) // make room to insert new ID
 		copy((*sl)[idx+1:], (*sl)[idx:])
 		(*sl)[idx] = aID
 		return true
@@ -125,6 +165,103 @@ func (sl *tSourceList) insert(aID int64) bool {
 	return false
 } // insert()
 
+// `intersect()` removes all IDs not present in `aList`.
+//
+// Both lists are expected to be sorted in ascending order, so the
+// intersection can be built in a single pass over both lists.
+//
+// Parameters:
+//   - `aList`: The sorted list of IDs to keep.
+//
+// Returns:
+//   - `bool`: `true` if at least one ID was removed, or `false` otherwise.
+func (sl *tSourceList[ID]) intersect(aList tSourceList[ID]) bool {
+	if (nil == sl) || (0 == len(*sl)) {
+		return false
+	}
+
+	var i, j, k int
+	sLen, aLen := len(*sl), len(aList)
+	for (i < sLen) && (j < aLen) {
+		switch {
+		case (*sl)[i] < aList[j]:
+			i++
+		case (*sl)[i] > aList[j]:
+			j++
+		default: // same ID in both lists
+			(*sl)[k] = (*sl)[i]
+			i++
+			j++
+			k++
+		}
+	}
+	if k == sLen {
+		return false // nothing removed
+	}
+	clear((*sl)[k:]) // zero out the former elements for GC
+	*sl = (*sl)[:k]
+
+	return true
+} // intersect()
+
+// `merge()` adds all IDs of `aList` to this list while keeping the
+// list sorted and free of duplicates.
+//
+// Both lists are expected to be sorted in ascending order (which is
+// guaranteed for all lists managed by `insert()`), so the union can
+// be built in a single pass over both lists.
+//
+// Parameters:
+//   - `aList`: The sorted list of IDs to merge into this list.
+//
+// Returns:
+//   - `bool`: `true` if at least one ID was added, or `false` otherwise.
+func (sl *tSourceList[ID]) merge(aList tSourceList[ID]) bool {
+	aLen := len(aList)
+	if (nil == sl) || (0 == aLen) {
+		return false
+	}
+	sLen := len(*sl)
+	if 0 == sLen { // empty list
+		*sl = append(*sl, aList...)
+		return true
+	}
+
+	var (
+		i, j int
+		id   ID
+	)
+	result := make(tSourceList[ID], 0, sLen+aLen)
+	for (i < sLen) || (j < aLen) {
+		switch {
+		case j == aLen:
+			id = (*sl)[i]
+			i++
+		case i == sLen:
+			id = aList[j]
+			j++
+		case (*sl)[i] < aList[j]:
+			id = (*sl)[i]
+			i++
+		case (*sl)[i] > aList[j]:
+			id = aList[j]
+			j++
+		default: // same ID in both lists
+			id = (*sl)[i]
+			i++
+			j++
+		}
+		result = append(result, id)
+	}
+
+	if len(result) == sLen {
+		return false // nothing new
+	}
+	*sl = result
+
+	return true
+} // merge()
+
 // `remove()` deletes the list entry of `aID`.
 //
 // NOTE: The method's result is an change indicator.
@@ -134,7 +271,7 @@ func (sl *tSourceList) insert(aID int64) bool {
 //
 // Returns:
 //   - `bool`: `true` if `aID` was removed, or `false` otherwise.
-func (sl *tSourceList) remove(aID int64) bool {
+func (sl *tSourceList[ID]) remove(aID ID) bool {
 	sLen := len(*sl)
 	if 0 == sLen { // empty list
 		return false
@@ -146,7 +283,7 @@ func (sl *tSourceList) remove(aID int64) bool {
 		// `aID` found at index `idx`
 		if 0 == idx {
 			if 1 == sLen { // the only element
-				*sl = *newSourceList()
+				*sl = *newSourceList[ID]()
 			} else { // a longer list
 				*sl = (*sl)[1:] // remove the first element
 			}
@@ -177,7 +314,7 @@ func (sl *tSourceList) remove(aID int64) bool {
 //
 // Returns:
 //   - `bool`: `true` if the the renaming was successful, or `false` otherwise.
-func (sl *tSourceList) rename(aOldID, aNewID int64) bool {
+func (sl *tSourceList[ID]) rename(aOldID, aNewID ID) bool {
 	if (nil == sl) || (0 == len(*sl)) || (aOldID == aNewID) {
 		return false
 	}
@@ -187,17 +324,9 @@ func (sl *tSourceList) rename(aOldID, aNewID int64) bool {
 		return false // ignored according to specification
 	}
 
-	if !sl.insert(aNewID) {
-		// This should only happen it there's an OOM problem.
-		// Hence we just replace the aOldID by aNewID and sort
-		// the list again.
-		if (*sl)[idx] != aNewID {
-			(*sl)[idx] = aNewID
-			sl.sort()
-			return true
-		}
-		return false
-	}
+	// If `aNewID` is already present, `insert()` leaves the
+	// list unchanged and only `aOldID` has to be removed.
+	sl.insert(aNewID)
 
 	return sl.remove(aOldID)
 } // rename()
@@ -208,8 +337,8 @@ func (sl *tSourceList) rename(aOldID, aNewID int64) bool {
 // library to sort the list.
 //
 // Returns:
-//   - `*tSourceList`: The sorted `tSourceList` instance.
-func (sl *tSourceList) sort() *tSourceList {
+//   - `*tSourceList[ID]`: The sorted `tSourceList` instance.
+func (sl *tSourceList[ID]) sort() *tSourceList[ID] {
 	if nil != sl {
 		slices.Sort(*sl) // ascending
 	}
@@ -219,34 +348,81 @@ func (sl *tSourceList) sort() *tSourceList {
 
 // `String()` implements the `fmt.Stringer` interface.
 //
-// The method returns the list as a linefeed separated string.
-// All IDs are represented as strings of 16 hexadecimal characters.
+// The method returns the list as a linefeed separated string using
+// the [DefaultCodec] of `ID` (e.g. `int64` IDs are represented as
+// strings of 16 hexadecimal characters).
+//
+// Returns:
+//   - `string`: The list's contents as a string.
+func (sl *tSourceList[ID]) String() string {
+	return sl.text(DefaultCodec[ID]())
+} // String()
+
+// `subtract()` removes all IDs present in `aList`.
+//
+// Both lists are expected to be sorted in ascending order, so the
+// difference can be built in a single pass over both lists.
+//
+// Parameters:
+//   - `aList`: The sorted list of IDs to remove.
+//
+// Returns:
+//   - `bool`: `true` if at least one ID was removed, or `false` otherwise.
+func (sl *tSourceList[ID]) subtract(aList tSourceList[ID]) bool {
+	if (nil == sl) || (0 == len(*sl)) || (0 == len(aList)) {
+		return false
+	}
+
+	var i, j, k int
+	sLen, aLen := len(*sl), len(aList)
+	for i < sLen {
+		switch {
+		case (j == aLen) || ((*sl)[i] < aList[j]):
+			(*sl)[k] = (*sl)[i]
+			i++
+			k++
+		case (*sl)[i] > aList[j]:
+			j++
+		default: // same ID in both lists
+			i++
+			j++
+		}
+	}
+	if k == sLen {
+		return false // nothing removed
+	}
+	clear((*sl)[k:]) // zero out the former elements for GC
+	*sl = (*sl)[:k]
+
+	return true
+} // subtract()
+
+// `text()` returns the list as a linefeed separated string with all
+// IDs encoded by `aCodec`.
+//
+// Parameters:
+//   - `aCodec`: The codec to encode the IDs with.
 //
 // Returns:
 //   - `string`: The list's contents as a string.
-func (sl *tSourceList) String() string {
+func (sl *tSourceList[ID]) text(aCodec TIDCodec[ID]) string {
 	if nil == sl {
 		return ""
 	}
 
 	// Pre-allocate buffer to avoid multiple allocations
 	var (
-		buf   bytes.Buffer
-		id    int64
-		strID string
+		buf bytes.Buffer
+		id  ID
 	)
-	buf.Grow(len(*sl) * 16) // Estimate size
+	buf.Grow(len(*sl) * 17) // Estimate size
 
 	for _, id = range *sl {
-		strID = fmt.Sprintf("%x\n", id)
-		if 17 > len(strID) { // 16 hex chars + LF
-			buf.WriteString(strings.Repeat("0", 17-len(strID)) + strID)
-		} else {
-			buf.WriteString(strID)
-		}
+		buf.WriteString(aCodec.EncodeID(id))
+		buf.WriteByte('\n')
 	}
 
 	return buf.String()
-} // String()
+} // text()
 
 /* EoF */
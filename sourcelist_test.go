@@ -14,13 +14,13 @@ import (
 //lint:file-ignore ST1017 - I prefer Yoda conditions
 
 func Test_tSourceList_clear(t *testing.T) {
-	sl1 := &tSourceList{1, 2, 3, 4, 5, 6, 7, 8, 9}
-	wl1 := &tSourceList{}
+	sl1 := &tSourceList[int64]{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	wl1 := &tSourceList[int64]{}
 
 	tests := []struct {
 		name string
-		sl   *tSourceList
-		want *tSourceList
+		sl   *tSourceList[int64]
+		want *tSourceList[int64]
 	}{
 		{"1", sl1, wl1},
 
@@ -36,14 +36,41 @@ func Test_tSourceList_clear(t *testing.T) {
 	}
 } // Test_tSourceList_clear()
 
+func Test_tSourceList_diff(t *testing.T) {
+	tests := []struct {
+		name        string
+		sl          tSourceList[int64]
+		list        tSourceList[int64]
+		wantAdded   []int64
+		wantRemoved []int64
+	}{
+		{"0", tSourceList[int64]{}, tSourceList[int64]{}, nil, nil},
+		{"1", tSourceList[int64]{1, 3}, tSourceList[int64]{1, 3}, nil, nil},
+		{"2", tSourceList[int64]{}, tSourceList[int64]{1, 3}, []int64{1, 3}, nil},
+		{"3", tSourceList[int64]{1, 3}, tSourceList[int64]{}, nil, []int64{1, 3}},
+		{"4", tSourceList[int64]{1, 3, 5}, tSourceList[int64]{0, 3, 4, 9}, []int64{0, 4, 9}, []int64{1, 5}},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed := tt.sl.diff(tt.list)
+			if !slices.Equal(added, tt.wantAdded) || !slices.Equal(removed, tt.wantRemoved) {
+				t.Errorf("%q: tSourceList.diff() = %v, %v, want %v, %v",
+					tt.name, added, removed, tt.wantAdded, tt.wantRemoved)
+			}
+		})
+	}
+} // Test_tSourceList_diff()
+
 func Test_tSourceList_equals(t *testing.T) {
-	sl1 := tSourceList{1, 2, 3}
-	sl2 := tSourceList{3, 2, 1}
+	sl1 := tSourceList[int64]{1, 2, 3}
+	sl2 := tSourceList[int64]{3, 2, 1}
 
 	tests := []struct {
 		name string
-		sl   tSourceList
-		list tSourceList
+		sl   tSourceList[int64]
+		list tSourceList[int64]
 		want bool
 	}{
 		{"0", sl1, nil, false},
@@ -63,17 +90,17 @@ func Test_tSourceList_equals(t *testing.T) {
 } // Test_tSourceList_equals()
 
 func Test_tSourceList_findIndex(t *testing.T) {
-	sl1 := &tSourceList{
+	sl1 := &tSourceList[int64]{
 		1, 2, 3, 4, 5,
 	}
 
 	tests := []struct {
 		name string
-		sl   *tSourceList
+		sl   *tSourceList[int64]
 		id   int64
 		want int
 	}{
-		{"empty list", &tSourceList{}, 1, -1},
+		{"empty list", &tSourceList[int64]{}, 1, -1},
 		{"first", sl1, 1, 0},
 		{"middle", sl1, 3, 2},
 		{"last", sl1, 5, 4},
@@ -92,7 +119,7 @@ func Test_tSourceList_findIndex(t *testing.T) {
 } // Test_tSourceList_findIndex()
 
 func Test_tSourceList_insert(t *testing.T) {
-	sl := tSourceList{}
+	sl := tSourceList[int64]{}
 
 	tests := []struct {
 		name string
@@ -117,13 +144,78 @@ func Test_tSourceList_insert(t *testing.T) {
 	}
 } // Test_tSourceList_insert()
 
+func Test_tSourceList_intersect(t *testing.T) {
+	sl0 := &tSourceList[int64]{}
+	sl1 := &tSourceList[int64]{1, 3, 5, 7}
+
+	tests := []struct {
+		name   string
+		sl     *tSourceList[int64]
+		list   tSourceList[int64]
+		want   bool
+		wantSl tSourceList[int64]
+	}{
+		{"0", sl0, tSourceList[int64]{1}, false, tSourceList[int64]{}},
+		{"1", sl1, tSourceList[int64]{0, 1, 3, 5, 7, 9}, false, tSourceList[int64]{1, 3, 5, 7}},
+		{"2", sl1, tSourceList[int64]{0, 3, 4, 7}, true, tSourceList[int64]{3, 7}},
+		{"3", sl1, tSourceList[int64]{}, true, tSourceList[int64]{}},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sl.intersect(tt.list); got != tt.want {
+				t.Errorf("%q: tSourceList.intersect() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+			if !tt.sl.equals(tt.wantSl) {
+				t.Errorf("%q: tSourceList.intersect() =\n%v\n>>>> want: >>>>\n%v",
+					tt.name, *tt.sl, tt.wantSl)
+			}
+		})
+	}
+} // Test_tSourceList_intersect()
+
+func Test_tSourceList_merge(t *testing.T) {
+	sl0 := &tSourceList[int64]{}
+	sl1 := &tSourceList[int64]{1, 3, 5}
+
+	tests := []struct {
+		name   string
+		sl     *tSourceList[int64]
+		list   tSourceList[int64]
+		want   bool
+		wantSl tSourceList[int64]
+	}{
+		{"0", sl0, tSourceList[int64]{}, false, tSourceList[int64]{}},
+		{"1", sl0, tSourceList[int64]{2, 4}, true, tSourceList[int64]{2, 4}},
+		{"2", sl1, tSourceList[int64]{1, 3}, false, tSourceList[int64]{1, 3, 5}},
+		{"3", sl1, tSourceList[int64]{0, 3, 4, 9}, true, tSourceList[int64]{0, 1, 3, 4, 5, 9}},
+		{"4", sl1, tSourceList[int64]{9, 10}, true, tSourceList[int64]{0, 1, 3, 4, 5, 9, 10}},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sl.merge(tt.list); got != tt.want {
+				t.Errorf("%q: tSourceList.merge() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+			if !tt.sl.equals(tt.wantSl) {
+				t.Errorf("%q: tSourceList.merge() =\n%v\n>>>> want: >>>>\n%v",
+					tt.name, *tt.sl, tt.wantSl)
+			}
+		})
+	}
+} // Test_tSourceList_merge()
+
 func Test_tSourceList_remove(t *testing.T) {
-	sl0 := &tSourceList{}
-	sl1 := &tSourceList{1, 2, 3, 4, 5}
+	sl0 := &tSourceList[int64]{}
+	sl1 := &tSourceList[int64]{1, 2, 3, 4, 5}
 
 	tests := []struct {
 		name string
-		sl   *tSourceList
+		sl   *tSourceList[int64]
 		id   int64
 		want bool
 	}{
@@ -147,15 +239,15 @@ func Test_tSourceList_remove(t *testing.T) {
 } // Test_tSourceList_remove()
 
 func Test_tSourceList_rename(t *testing.T) {
-	sl1 := &tSourceList{1, 2, 3}
-	sl2 := &tSourceList{}
+	sl1 := &tSourceList[int64]{1, 2, 3}
+	sl2 := &tSourceList[int64]{}
 
 	type tArgs struct {
 		oldID, newID int64
 	}
 	tests := []struct {
 		name string
-		sl   *tSourceList
+		sl   *tSourceList[int64]
 		args tArgs
 		want bool
 	}{
@@ -167,6 +259,7 @@ func Test_tSourceList_rename(t *testing.T) {
 		{" 4", sl1, tArgs{1, 6}, true},   // Replace first element
 		{" 5", sl1, tArgs{3, 7}, true},   // Replace last element
 		{" 6", nil, tArgs{1, 2}, false},  // Nil list
+		{" 7", sl1, tArgs{4, 6}, true},   // New ID already present
 
 		// TODO: Add test cases.
 	}
@@ -181,20 +274,20 @@ func Test_tSourceList_rename(t *testing.T) {
 } // Test_tSourceList_rename()
 
 func Test_tSourceList_sort(t *testing.T) {
-	sl1 := &tSourceList{}
-	wl1 := &tSourceList{}
+	sl1 := &tSourceList[int64]{}
+	wl1 := &tSourceList[int64]{}
 
-	sl2 := &tSourceList{
+	sl2 := &tSourceList[int64]{
 		3, 1, 2,
 	}
-	wl2 := &tSourceList{
+	wl2 := &tSourceList[int64]{
 		1, 2, 3,
 	}
 
 	tests := []struct {
 		name string
-		sl   *tSourceList
-		want *tSourceList
+		sl   *tSourceList[int64]
+		want *tSourceList[int64]
 	}{
 		{"0", nil, nil},
 		{"1", sl1, wl1},
@@ -227,21 +320,21 @@ func Test_tSourceList_sort(t *testing.T) {
 } // Test_tSourceList_sort()
 
 func Test_tSourceList_String(t *testing.T) {
-	sl1 := &tSourceList{
+	sl1 := &tSourceList[int64]{
 		1,
 		2,
 		3,
 	}
 	wl1 := "0000000000000001\n0000000000000002\n0000000000000003\n"
 
-	sl2 := &tSourceList{}
+	sl2 := &tSourceList[int64]{}
 	wl2 := ""
-	sl3 := &tSourceList{3, 2, 1}
+	sl3 := &tSourceList[int64]{3, 2, 1}
 	wl3 := "0000000000000003\n0000000000000002\n0000000000000001\n"
 
 	tests := []struct {
 		name string
-		sl   *tSourceList
+		sl   *tSourceList[int64]
 		want string
 	}{
 		{" 1", sl1, wl1},
@@ -260,4 +353,36 @@ func Test_tSourceList_String(t *testing.T) {
 	}
 } // Test_tSourceList_String()
 
+func Test_tSourceList_subtract(t *testing.T) {
+	sl0 := &tSourceList[int64]{}
+	sl1 := &tSourceList[int64]{1, 3, 5, 7}
+
+	tests := []struct {
+		name   string
+		sl     *tSourceList[int64]
+		list   tSourceList[int64]
+		want   bool
+		wantSl tSourceList[int64]
+	}{
+		{"0", sl0, tSourceList[int64]{1}, false, tSourceList[int64]{}},
+		{"1", sl1, tSourceList[int64]{0, 2, 9}, false, tSourceList[int64]{1, 3, 5, 7}},
+		{"2", sl1, tSourceList[int64]{0, 3, 4, 7}, true, tSourceList[int64]{1, 5}},
+		{"3", sl1, tSourceList[int64]{1, 5}, true, tSourceList[int64]{}},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sl.subtract(tt.list); got != tt.want {
+				t.Errorf("%q: tSourceList.subtract() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+			if !tt.sl.equals(tt.wantSl) {
+				t.Errorf("%q: tSourceList.subtract() =\n%v\n>>>> want: >>>>\n%v",
+					tt.name, *tt.sl, tt.wantSl)
+			}
+		})
+	}
+} // Test_tSourceList_subtract()
+
 /* EoF */
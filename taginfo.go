@@ -0,0 +1,252 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"time"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `tTagInfo` holds additional data of a single `#hashtag`
+	// or `@mention`.
+	//
+	// NOTE: All fields must be exported to allow for `gob` encoding.
+	tTagInfo struct {
+		Display     string    // original (first seen) spelling of the tag
+		Description string    // free-text description of the tag
+		Created     time.Time // time the tag got its first ID
+		Modified    time.Time // time the tag's list of IDs last changed
+		Pinned      bool      // flag marking a curated tag
+	}
+
+	// `tTagInfoMap` maps the normalised tags to their additional data.
+	tTagInfoMap map[string]*tTagInfo
+)
+
+// -------------------------------------------------------------------------
+// methods of `tTagInfoMap`:
+
+// `clear()` removes all entries.
+//
+// Returns:
+//   - `*tTagInfoMap`: The cleared map.
+func (im *tTagInfoMap) clear() *tTagInfoMap {
+	if (nil != im) && (0 < len(*im)) {
+		clear(*im)
+	}
+
+	return im
+} // clear()
+
+// `countItem()` returns the data of `aKey` as a `TCountItem`.
+//
+// Parameters:
+//   - `aKey`: The normalised tag to lookup.
+//   - `aCount`: The number of IDs associated with `aKey`.
+//
+// Returns:
+//   - `TCountItem`: The item holding the data of `aKey`.
+func (im tTagInfoMap) countItem(aKey string, aCount int) TCountItem {
+	result := TCountItem{
+		Count:   aCount,
+		Tag:     aKey,
+		Display: im.display(aKey),
+	}
+	if ti, ok := im[aKey]; ok {
+		result.Created = ti.Created
+		result.Description = ti.Description
+		result.Modified = ti.Modified
+		result.Pinned = ti.Pinned
+	}
+
+	return result
+} // countItem()
+
+// `display()` returns the original spelling of `aKey`.
+//
+// If there's no spelling recorded for `aKey`, or if the recorded
+// spelling doesn't match `aKey` (anymore), `aKey` itself is returned.
+//
+// Parameters:
+//   - `aKey`: The normalised tag to lookup.
+//
+// Returns:
+//   - `string`: The display spelling of `aKey`.
+func (im tTagInfoMap) display(aKey string) string {
+	if ti, ok := im[aKey]; ok && ("" != ti.Display) {
+		if normalise(ti.Display) == aKey {
+			return ti.Display
+		}
+	}
+
+	return aKey
+} // display()
+
+// `drop()` removes the data of `aTag`.
+//
+// Parameters:
+//   - `aTag`: The tag whose data is to be removed.
+//
+// Returns:
+//   - `bool`: `true` if data was removed, or `false` otherwise.
+func (im tTagInfoMap) drop(aTag string) bool {
+	key := normalise(aTag)
+	if _, ok := im[key]; ok {
+		delete(im, key)
+		return true
+	}
+
+	return false
+} // drop()
+
+// `info()` returns the data of `aKey`, creating it if necessary.
+//
+// Parameters:
+//   - `aKey`: The normalised tag to lookup.
+//
+// Returns:
+//   - `*tTagInfo`: The data associated with `aKey`.
+func (im *tTagInfoMap) info(aKey string) *tTagInfo {
+	if nil == *im {
+		*im = make(tTagInfoMap, defaultListSize)
+	}
+
+	ti, ok := (*im)[aKey]
+	if !ok {
+		ti = &tTagInfo{}
+		(*im)[aKey] = ti
+	}
+
+	return ti
+} // info()
+
+// `merge()` moves the data of all `aSources` to `aTarget`.
+//
+// Data already present for `aTarget` is kept; otherwise the data of the
+// first source is used with `aTarget` (as given) becoming the new
+// display spelling.
+//
+// Parameters:
+//   - `aSources`: The tags whose data is to be moved.
+//   - `aTarget`: The tag to receive the data.
+func (im *tTagInfoMap) merge(aSources []string, aTarget string) {
+	target := normalise(aTarget)
+	if "" == target {
+		return
+	}
+
+	var (
+		key string
+		ok  bool
+		ti  *tTagInfo
+	)
+	_, hasTarget := (*im)[target]
+	for _, source := range aSources {
+		if key = normalise(source); key == target {
+			continue
+		}
+		if ti, ok = (*im)[key]; !ok {
+			continue
+		}
+		delete(*im, key)
+
+		if !hasTarget {
+			ti.Display = aTarget
+			(*im)[target] = ti
+			hasTarget = true
+		}
+	}
+} // merge()
+
+// `note()` records `aTag` as the display spelling of its normalised
+// key unless a spelling was recorded before.
+//
+// Parameters:
+//   - `aTag`: The tag as found in a text or given by the caller.
+func (im *tTagInfoMap) note(aTag string) {
+	if key := normalise(aTag); "" != key {
+		if ti := im.info(key); "" == ti.Display {
+			ti.Display = aTag
+		}
+	}
+} // note()
+
+// `prune()` removes the data of all tags for which `aKeep` returns
+// `false`.
+//
+// Parameters:
+//   - `aKeep`: The function deciding whether to keep a tag's data.
+func (im tTagInfoMap) prune(aKeep func(aKey string) bool) {
+	for key := range im {
+		if !aKeep(key) {
+			delete(im, key)
+		}
+	}
+} // prune()
+
+// `renormalise()` applies the current [Normalisation] to all keys.
+//
+// If several keys become equal, the data of one of them is kept.
+func (im *tTagInfoMap) renormalise() {
+	if 0 == len(*im) {
+		return
+	}
+
+	nm := make(tTagInfoMap, max(len(*im), defaultListSize))
+	for key, ti := range *im {
+		if key = normalise(key); "" == key {
+			continue
+		}
+		if _, ok := nm[key]; !ok {
+			nm[key] = ti
+		}
+	}
+	*im = nm
+} // renormalise()
+
+// `touch()` records the current time as the modification time of
+// `aKey` (and as its creation time if there's none yet).
+//
+// Parameters:
+//   - `aKey`: The normalised tag whose list of IDs was changed.
+func (im *tTagInfoMap) touch(aKey string) {
+	if "" == aKey {
+		return
+	}
+
+	now := time.Now()
+	ti := im.info(aKey)
+	if ti.Created.IsZero() {
+		ti.Created = now
+	}
+	ti.Modified = now
+} // touch()
+
+// `union()` adds the data of all tags of `aOther` not present in
+// this map.
+//
+// Parameters:
+//   - `aOther`: The map whose data is to be added.
+//
+// Returns:
+//   - `bool`: `true` if at least one tag's data was added, or `false` otherwise.
+func (im *tTagInfoMap) union(aOther tTagInfoMap) bool {
+	var result bool
+	for key, ti := range aOther {
+		if _, ok := (*im)[key]; !ok {
+			info := *ti
+			(*im)[key] = &info
+			result = true
+		}
+	}
+
+	return result
+} // union()
+
+/* EoF */
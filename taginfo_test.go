@@ -0,0 +1,138 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func Test_tTagInfoMap_countItem(t *testing.T) {
+	im := tTagInfoMap{}
+	im.note("#GoLang")
+	im.touch("#golang")
+	im.info("#golang").Description = "Go"
+	im.info("#golang").Pinned = true
+
+	got := im.countItem("#golang", 3)
+	if (3 != got.Count) || ("#GoLang" != got.Display) ||
+		("Go" != got.Description) || !got.Pinned || got.Created.IsZero() {
+		t.Errorf("tTagInfoMap.countItem() = %#v", got)
+	}
+
+	got = im.countItem("#unknown", 1)
+	if ("#unknown" != got.Display) || !got.Created.IsZero() {
+		t.Errorf("tTagInfoMap.countItem() unknown = %#v", got)
+	}
+} // Test_tTagInfoMap_countItem()
+
+func Test_tTagInfoMap_display(t *testing.T) {
+	im := tTagInfoMap{}
+	im.note("#OpenSource")
+	im.note("#OPENSOURCE") // ignored: not the first spelling
+	im.note("@Alice")
+	im["#stale"] = &tTagInfo{Display: "#Other"}
+
+	tests := []struct {
+		name string
+		key  string
+		want string
+	}{
+		{"0", "#unknown", "#unknown"},
+		{"1", "#opensource", "#OpenSource"},
+		{"2", "@alice", "@Alice"},
+		{"3", "#stale", "#stale"},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := im.display(tt.key); got != tt.want {
+				t.Errorf("%q: tTagInfoMap.display() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+} // Test_tTagInfoMap_display()
+
+func Test_tTagInfoMap_merge(t *testing.T) {
+	im := tTagInfoMap{}
+	im.note("#GoLang")
+	im.note("#Golang_Dev")
+	im.note("#Rust")
+
+	type tArgs struct {
+		aSources []string
+		aTarget  string
+	}
+	tests := []struct {
+		name    string
+		args    tArgs
+		key     string
+		want    string
+		wantLen int
+	}{
+		{"0", tArgs{nil, ""}, "#golang", "#GoLang", 3},
+		{"1", tArgs{[]string{"#golang"}, "#GoLanguage"}, "#golanguage", "#GoLanguage", 3},
+		{"2", tArgs{[]string{"#golang_dev"}, "#golanguage"}, "#golanguage", "#GoLanguage", 2},
+		{"3", tArgs{[]string{"#unknown"}, "#rust"}, "#rust", "#Rust", 2},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			im.merge(tt.args.aSources, tt.args.aTarget)
+			if got := im.display(tt.key); got != tt.want {
+				t.Errorf("%q: tTagInfoMap.merge() = %q, want %q",
+					tt.name, got, tt.want)
+			}
+			if got := len(im); got != tt.wantLen {
+				t.Errorf("%q: tTagInfoMap.merge() length = %d, want %d",
+					tt.name, got, tt.wantLen)
+			}
+		})
+	}
+} // Test_tTagInfoMap_merge()
+
+func Test_tTagInfoMap_prune(t *testing.T) {
+	hm := newHashMap[int64]()
+	hm.insert("#one", 1)
+
+	im := tTagInfoMap{}
+	im.note("#One")
+	im.note("#Two")
+	im.prune(hm.has)
+
+	if 1 != len(im) {
+		t.Errorf("tTagInfoMap.prune() length = %d, want 1", len(im))
+	}
+	if got := im.display("#one"); "#One" != got {
+		t.Errorf("tTagInfoMap.prune() = %q, want %q", got, "#One")
+	}
+} // Test_tTagInfoMap_prune()
+
+func Test_tTagInfoMap_touch(t *testing.T) {
+	im := tTagInfoMap{}
+	im.touch("")
+	if 0 != len(im) {
+		t.Errorf("tTagInfoMap.touch() empty key added %v", im)
+	}
+
+	im.touch("#tag")
+	created := im["#tag"].Created
+	if created.IsZero() || !im["#tag"].Modified.Equal(created) {
+		t.Errorf("tTagInfoMap.touch() = %v", im["#tag"])
+	}
+
+	im.touch("#tag")
+	if !im["#tag"].Created.Equal(created) || im["#tag"].Modified.Before(created) {
+		t.Errorf("tTagInfoMap.touch() again = %v", im["#tag"])
+	}
+} // Test_tTagInfoMap_touch()
+
+/* EoF */
@@ -0,0 +1,143 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `TTrendItem` holds a `#hashtag` or `@mention` and the number of
+	// its associations within a time window.
+	TTrendItem struct {
+		Tag      string // normalised tag
+		Display  string // original spelling of the tag
+		Count    int    // number of associations within the window
+		Previous int    // number of associations within the preceding window
+	}
+
+	// `TTrendOrder` determines how [TIndex.TrendingBy] ranks tags.
+	TTrendOrder uint8
+)
+
+const (
+	// `TrendByCount` ranks tags by their number of associations
+	// within the time window.
+	TrendByCount = TTrendOrder(iota)
+
+	// `TrendByGrowth` ranks tags by the increase of associations
+	// compared to the preceding window of the same length.
+	TrendByGrowth
+)
+
+// -------------------------------------------------------------------------
+// methods of `TTrendItem`:
+
+// `Growth()` returns the increase of associations compared to the
+// preceding time window.
+//
+// Returns:
+//   - `int`: The difference between `Count` and `Previous`.
+func (ti TTrendItem) Growth() int {
+	return ti.Count - ti.Previous
+} // Growth()
+
+// -------------------------------------------------------------------------
+// methods of `TIndex`:
+
+// `Trending()` returns the `aLimit` tags with the most associations
+// in the time window from `aSince` (inclusive) to `aUntil` (exclusive).
+//
+// Only associations recorded while the timestamp mode was enabled
+// (see [SetTimestamps]) and not yet expired (see [SetRetention]) are
+// considered.
+//
+// Parameters:
+//   - `aSince`: The start of the time window.
+//   - `aUntil`: The end of the time window.
+//   - `aLimit`: The maximal number of tags to return (`0` = unlimited).
+//
+// Returns:
+//   - `[]TTrendItem`: The ranked list of tags.
+func (ht *TIndex[ID]) Trending(aSince, aUntil time.Time, aLimit int) []TTrendItem {
+	return ht.TrendingBy(aSince, aUntil, aLimit, TrendByCount)
+} // Trending()
+
+// `TrendingBy()` returns the `aLimit` tags ranked by `aOrder` in the
+// time window from `aSince` (inclusive) to `aUntil` (exclusive).
+//
+// The `Previous` count of each item refers to the window of the same
+// length immediately preceding `aSince`. Tags without associations
+// in the time window are not included. Ties are ranked by tag.
+//
+// Parameters:
+//   - `aSince`: The start of the time window.
+//   - `aUntil`: The end of the time window.
+//   - `aLimit`: The maximal number of tags to return (`0` = unlimited).
+//   - `aOrder`: The ranking to use.
+//
+// Returns:
+//   - `[]TTrendItem`: The ranked list of tags.
+func (ht *TIndex[ID]) TrendingBy(aSince, aUntil time.Time, aLimit int, aOrder TTrendOrder) []TTrendItem {
+	if !aSince.Before(aUntil) {
+		return nil
+	}
+
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	prevSince := aSince.Add(-aUntil.Sub(aSince))
+	var (
+		at     time.Time
+		item   TTrendItem
+		result []TTrendItem
+	)
+	for key, ids := range ht.xt.Times {
+		item = TTrendItem{Tag: key}
+		for _, at = range ids {
+			if at.Before(prevSince) || !at.Before(aUntil) {
+				continue
+			}
+			if at.Before(aSince) {
+				item.Previous++
+			} else {
+				item.Count++
+			}
+		}
+		if 0 < item.Count {
+			item.Display = ht.xt.Info.display(key)
+			result = append(result, item)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b TTrendItem) int {
+		var c int
+		if TrendByGrowth == aOrder {
+			c = cmp.Compare(b.Growth(), a.Growth())
+		}
+		if 0 == c {
+			c = cmp.Compare(b.Count, a.Count)
+		}
+		if 0 == c {
+			c = cmp.Compare(a.Tag, b.Tag)
+		}
+		return c
+	})
+	if (0 < aLimit) && (aLimit < len(result)) {
+		result = result[:aLimit]
+	}
+
+	return result
+} // TrendingBy()
+
+/* EoF */
@@ -0,0 +1,118 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func prepTrending() (*THashTags, time.Time) {
+	ht := prepHT()
+	ht.SetTimestamps(true)
+	now := time.Now()
+	day := 24 * time.Hour
+
+	// last week: #old x3, #new x1
+	ht.IDparseAt(1, []byte("#old"), now.Add(-10*day))
+	ht.IDparseAt(2, []byte("#old"), now.Add(-9*day))
+	ht.IDparseAt(3, []byte("#Old #new"), now.Add(-8*day))
+	// this week: #old x2, #new x3, @bob x1
+	ht.IDparseAt(4, []byte("#old #new"), now.Add(-3*day))
+	ht.IDparseAt(5, []byte("#old #new"), now.Add(-2*day))
+	ht.HashAddAt("new", 6, now.Add(-day))
+	ht.MentionAddAt("@Bob", 6, now.Add(-day))
+
+	return ht, now
+} // prepTrending()
+
+func Test_THashTags_Trending(t *testing.T) {
+	ht, now := prepTrending()
+	since := now.Add(-7 * 24 * time.Hour)
+
+	tags := func(aList []TTrendItem) (rList []string) {
+		for _, item := range aList {
+			rList = append(rList, item.Tag)
+		}
+		return
+	}
+
+	tests := []struct {
+		name  string
+		since time.Time
+		until time.Time
+		limit int
+		want  []string
+	}{
+		{"week", since, now, 0, []string{"#new", "#old", "@bob"}},
+		{"limited", since, now, 2, []string{"#new", "#old"}},
+		{"empty window", now, now, 0, nil},
+		{"future", now, now.Add(time.Hour), 0, nil},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tags(ht.Trending(tt.since, tt.until, tt.limit)); !slices.Equal(got, tt.want) {
+				t.Errorf("%q: THashTags.Trending() = %v, want %v",
+					tt.name, got, tt.want)
+			}
+		})
+	}
+
+	got := ht.TrendingBy(since, now, 0, TrendByGrowth)
+	if want := []string{"#new", "@bob", "#old"}; !slices.Equal(tags(got), want) {
+		t.Errorf("THashTags.TrendingBy() = %v, want %v", tags(got), want)
+	}
+	if (3 != got[0].Count) || (1 != got[0].Previous) || (2 != got[0].Growth()) {
+		t.Errorf("THashTags.TrendingBy() item = %v", got[0])
+	}
+	if "@Bob" != got[1].Display {
+		t.Errorf("THashTags.TrendingBy() display = %q", got[1].Display)
+	}
+} // Test_THashTags_Trending()
+
+func Test_THashTags_Expire(t *testing.T) {
+	saveBinary := UseBinaryStorage
+	defer func() {
+		UseBinaryStorage = saveBinary
+	}()
+	UseBinaryStorage = false
+
+	ht, now := prepTrending()
+	if got := ht.Expire(); 0 != got {
+		t.Errorf("THashTags.Expire() without retention = %d, want 0", got)
+	}
+
+	ht.SetRetention(5 * 24 * time.Hour)
+	if _, err := ht.Store(); nil != err { // calls `Expire()`
+		t.Errorf("THashTags.Store() error = %v", err)
+		return
+	}
+	if got := ht.Expire(); 0 != got {
+		t.Errorf("THashTags.Expire() after Store = %d, want 0", got)
+	}
+
+	ht2, err := New(ht.Filename())
+	if nil != err {
+		t.Errorf("New() error = %v", err)
+		return
+	}
+	got := ht2.Trending(now.Add(-30*24*time.Hour), now, 0)
+	if (2 > len(got)) || ("#new" != got[0].Tag) || (3 != got[0].Count) {
+		t.Errorf("THashTags.Trending() after loading = %v", got)
+	}
+	// the associations themselves are kept
+	if got := ht2.HashLen("#old"); 5 != got {
+		t.Errorf("THashTags.Expire() removed IDs: %d", got)
+	}
+} // Test_THashTags_Expire()
+
+/* EoF */
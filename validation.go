@@ -0,0 +1,181 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `TValidateFunc` is a custom check of a (normalised) `#hashtag`
+	// or `@mention` including its leading mark.
+	//
+	// The function returns `true` if the tag is acceptable for
+	// indexing, or `false` otherwise.
+	TValidateFunc func(aTag string) bool
+
+	// `TValidation` holds the rules a `#hashtag` or `@mention` has
+	// to satisfy to be added to the list.
+	//
+	// The zero value accepts all tags.
+	TValidation struct {
+		MinLength     int             // minimal number of characters (`0` = unlimited)
+		MaxLength     int             // maximal number of characters (`0` = unlimited)
+		NoNumeric     bool            // reject purely numeric tags like `#2024`
+		RequireLetter bool            // reject tags without any letter like `#___`
+		Allow         []string        // if not empty: accept only these tags
+		Deny          []string        // tags never to accept (e.g. `#fff`)
+		Funcs         []TValidateFunc // custom checks all to be passed
+	}
+
+	// `tValidator` is the prepared form of a `TValidation`.
+	tValidator struct {
+		rules TValidation         // the rules as given by the caller
+		allow map[string]struct{} // normalised allow-list
+		deny  map[string]struct{} // normalised deny-list
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newValidator()` prepares `aRules` for checking tags.
+//
+// The tags of the allow- and deny-lists are normalised using the current
+// [Normalisation] setting; tags without a leading mark are considered
+// `#hashtags`.
+//
+// Parameters:
+//   - `aRules`: The validation rules to use.
+//
+// Returns:
+//   - `*tValidator`: The prepared validator.
+func newValidator(aRules TValidation) *tValidator {
+	v := &tValidator{
+		rules: aRules,
+		allow: tagSet(aRules.Allow),
+		deny:  tagSet(aRules.Deny),
+	}
+	// keep our own copies of the caller's slices
+	v.rules.Allow = slices.Clone(aRules.Allow)
+	v.rules.Deny = slices.Clone(aRules.Deny)
+	v.rules.Funcs = slices.Clone(aRules.Funcs)
+
+	return v
+} // newValidator()
+
+// `tagSet()` returns a set of the normalised `aTags`.
+//
+// Parameters:
+//   - `aTags`: The list of tags to use.
+//
+// Returns:
+//   - `map[string]struct{}`: The set of normalised tags (or `nil`).
+func tagSet(aTags []string) map[string]struct{} {
+	if 0 == len(aTags) {
+		return nil
+	}
+
+	result := make(map[string]struct{}, len(aTags))
+	for _, tag := range aTags {
+		if tag = tagName(tag); "" != tag {
+			result[normalise(tag)] = struct{}{}
+		}
+	}
+
+	return result
+} // tagSet()
+
+// -------------------------------------------------------------------------
+// methods of `tValidator`:
+
+// `isValid()` checks whether `aTag` satisfies all rules.
+//
+// A `nil` validator accepts all tags.
+//
+// Parameters:
+//   - `aTag`: The normalised tag (including its leading mark) to check.
+//
+// Returns:
+//   - `bool`: `true` if `aTag` is acceptable, or `false` otherwise.
+func (v *tValidator) isValid(aTag string) bool {
+	if nil == v {
+		return true
+	}
+	if 2 > len(aTag) {
+		return false // a mark without a name
+	}
+
+	if _, ok := v.deny[aTag]; ok {
+		return false
+	}
+	if 0 < len(v.allow) {
+		if _, ok := v.allow[aTag]; !ok {
+			return false
+		}
+	}
+
+	name := aTag[1:] // skip the leading mark
+	if (0 < v.rules.MinLength) || (0 < v.rules.MaxLength) {
+		nLen := utf8.RuneCountInString(name)
+		if (0 < v.rules.MinLength) && (nLen < v.rules.MinLength) {
+			return false
+		}
+		if (0 < v.rules.MaxLength) && (nLen > v.rules.MaxLength) {
+			return false
+		}
+	}
+
+	if v.rules.NoNumeric || v.rules.RequireLetter {
+		hasLetter, onlyDigits := false, true
+		for _, r := range name {
+			if unicode.IsLetter(r) {
+				hasLetter = true
+			}
+			if !unicode.IsDigit(r) {
+				onlyDigits = false
+			}
+		}
+		if v.rules.NoNumeric && onlyDigits {
+			return false
+		}
+		if v.rules.RequireLetter && !hasLetter {
+			return false
+		}
+	}
+
+	for _, f := range v.rules.Funcs {
+		if (nil != f) && !f(aTag) {
+			return false
+		}
+	}
+
+	return true
+} // isValid()
+
+// `ruleSet()` returns a copy of the validation rules.
+//
+// Returns:
+//   - `TValidation`: The rules used by this validator.
+func (v *tValidator) ruleSet() TValidation {
+	if nil == v {
+		return TValidation{}
+	}
+
+	result := v.rules
+	result.Allow = slices.Clone(v.rules.Allow)
+	result.Deny = slices.Clone(v.rules.Deny)
+	result.Funcs = slices.Clone(v.rules.Funcs)
+
+	return result
+} // ruleSet()
+
+/* EoF */
@@ -0,0 +1,70 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"regexp"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func Test_tValidator_isValid(t *testing.T) {
+	cssRE := regexp.MustCompile(`^#(?:[0-9a-f]{3}|[0-9a-f]{6})$`)
+	noCSS := func(aTag string) bool {
+		return !cssRE.MatchString(aTag)
+	}
+
+	var v0 *tValidator
+	v1 := newValidator(TValidation{
+		MinLength:     2,
+		MaxLength:     8,
+		NoNumeric:     true,
+		RequireLetter: true,
+		Deny:          []string{"Spam", "@Bot"},
+		Funcs:         []TValidateFunc{noCSS},
+	})
+	v2 := newValidator(TValidation{
+		Allow: []string{"#Go", "rust"},
+	})
+
+	tests := []struct {
+		name string
+		v    *tValidator
+		tag  string
+		want bool
+	}{
+		{"0", v0, "#1", true},
+		{"1", v1, "#golang", true},
+		{"2", v1, "#g", false},
+		{"3", v1, "#golanguage", false},
+		{"4", v1, "#2024", false},
+		{"5", v1, "#___", false},
+		{"6", v1, "#spam", false},
+		{"7", v1, "@bot", false},
+		{"8", v1, "#bot", true},
+		{"9", v1, "#fff", false},
+		{"10", v1, "#ffg", true},
+		{"11", v1, "#", false},
+		{"12", v2, "#go", true},
+		{"13", v2, "#rust", true},
+		{"14", v2, "#java", false},
+		{"15", v2, "@go", false},
+
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.isValid(tt.tag); got != tt.want {
+				t.Errorf("%q: tValidator.isValid(%q) = %v, want %v",
+					tt.name, tt.tag, got, tt.want)
+			}
+		})
+	}
+} // Test_tValidator_isValid()
+
+/* EoF */
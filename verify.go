@@ -0,0 +1,162 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"os"
+
+	se "github.com/mwat56/sourceerror"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `TLoadMode` determines how malformed data are handled while
+	// reading a list's file.
+	TLoadMode uint8
+
+	// `TLoadReport` lists the malformed data found in a list's file.
+	TLoadReport struct {
+		Filename string        // name of the file read
+		Issues   []*TFileError // malformed lines in order of occurrence
+		Lines    int           // number of lines read (text format only)
+		Tags     int           // number of tags read
+	}
+)
+
+const (
+	// `LoadLenient` silently ignores malformed lines (the default).
+	LoadLenient TLoadMode = iota
+
+	// `LoadStrict` rejects a file with malformed lines.
+	LoadStrict
+
+	// `LoadRepair` drops malformed lines but keeps all valid data.
+	LoadRepair
+)
+
+var (
+	// Reasons for a malformed line of the plain text format:
+	errBadHeader = errors.New("malformed tag header")
+	errBadLine   = errors.New("malformed line")
+	errBadScope  = errors.New("malformed scope line")
+	errNoHeader  = errors.New("ID outside of a tag's section")
+)
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `Verify()` checks the file `aFilename` of a [THashTags] list
+// without loading it.
+//
+// The file is expected in the format selected by [UseBinaryStorage].
+//
+// Parameters:
+//   - `aFilename`: The name of the file to check.
+//
+// Returns:
+//   - `TLoadReport`: The malformed data found.
+//   - `error`: `nil` if the file is valid, or `ErrCorruptFile` etc. otherwise.
+func Verify(aFilename string) (TLoadReport, error) {
+	return VerifyIndex[int64](aFilename, nil)
+} // Verify()
+
+// `VerifyIndex()` checks the file `aFilename` of a [TIndex] list
+// without loading it.
+//
+// Parameters:
+//   - `aFilename`: The name of the file to check.
+//   - `aCodec`: The codec for the IDs (`nil` = [DefaultCodec]).
+//
+// Returns:
+//   - `TLoadReport`: The malformed data found.
+//   - `error`: `nil` if the file is valid, or `ErrCorruptFile` etc. otherwise.
+func VerifyIndex[ID cmp.Ordered](aFilename string, aCodec TIDCodec[ID]) (TLoadReport, error) {
+	report := TLoadReport{Filename: aFilename}
+	if _, err := os.Stat(aFilename); nil != err {
+		return report, se.New(loadError(aFilename, err), 1)
+	}
+
+	xt := newExtras[ID]()
+	xt.ic = aCodec
+	_, err := newHashMap[ID]().loadFile(context.Background(),
+		aFilename, xt, LoadStrict, &report, nil)
+
+	return report, err // already wrapped
+} // VerifyIndex()
+
+// -------------------------------------------------------------------------
+// methods of `TLoadReport`:
+
+// `OK()` reports whether no malformed data were found.
+//
+// Returns:
+//   - `bool`: `true` if the file is valid, or `false` otherwise.
+func (lr TLoadReport) OK() bool {
+	return 0 == len(lr.Issues)
+} // OK()
+
+// -------------------------------------------------------------------------
+// methods of `TIndex`:
+
+// `LoadStrict()` reads the configured file like [TIndex.Load] but
+// rejects a file with malformed data.
+//
+// If malformed data are found the list remains unchanged and the
+// returned report lists all of them with their line numbers.
+//
+// Returns:
+//   - `TLoadReport`: The malformed data found.
+//   - `error`: `nil` in case of success, or `ErrCorruptFile` etc. otherwise.
+func (ht *TIndex[ID]) LoadStrict() (TLoadReport, error) {
+	if nil != ht.root {
+		return ht.root.LoadStrict()
+	}
+
+	var report TLoadReport
+	err := ht.loadFresh(context.Background(), LoadStrict, &report)
+
+	return report, err // already wrapped
+} // LoadStrict()
+
+// `Repair()` reads the configured file like [TIndex.Load] salvaging
+// all valid data of a damaged file, and stores the repaired list if
+// malformed data were found.
+//
+// In the plain text format malformed lines (and IDs following a
+// malformed tag header) are dropped. In the binary format damaged
+// additional data (e.g. descriptions or timestamps) are dropped
+// while the tags and their IDs are kept.
+//
+// Returns:
+//   - `TLoadReport`: The malformed data dropped.
+//   - `error`: `nil` in case of success, or `ErrReadOnly` or a possible I/O error.
+func (ht *TIndex[ID]) Repair() (TLoadReport, error) {
+	if nil != ht.root {
+		return ht.root.Repair()
+	}
+
+	var report TLoadReport
+	if ht.ro {
+		return report, se.New(&TFileError{Kind: ErrReadOnly, Filename: ht.fn}, 1)
+	}
+	if err := ht.loadFresh(context.Background(), LoadRepair, &report); nil != err {
+		return report, err // already wrapped
+	}
+	if !report.OK() {
+		if _, err := ht.Store(); nil != err {
+			return report, err // already wrapped
+		}
+	}
+
+	return report, nil
+} // Repair()
+
+/* EoF */
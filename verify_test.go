@@ -0,0 +1,141 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+const damagedText = `0000000000000009
+[#go]
+0000000000000001
+garbage
+[ nope ]
+0000000000000002
+[@bob]
+0000000000000003
+|orphan
+`
+
+func Test_Verify(t *testing.T) {
+	saveBinary := UseBinaryStorage
+	defer func() {
+		UseBinaryStorage = saveBinary
+	}()
+	UseBinaryStorage = false
+	fn := filepath.Join(t.TempDir(), "damaged.txt")
+	_ = os.WriteFile(fn, []byte(damagedText), 0600)
+
+	report, err := Verify(fn)
+	if !errors.Is(err, ErrCorruptFile) {
+		t.Errorf("Verify() error = %v, want %v", err, ErrCorruptFile)
+	}
+	var lines []int
+	for _, issue := range report.Issues {
+		lines = append(lines, issue.Line)
+		if fn != issue.Filename {
+			t.Errorf("Verify() issue filename = %q, want %q", issue.Filename, fn)
+		}
+	}
+	if want := []int{1, 4, 5, 6, 9}; !slices.Equal(lines, want) {
+		t.Errorf("Verify() lines = %v, want %v", lines, want)
+	}
+	if report.OK() || (9 != report.Lines) {
+		t.Errorf("Verify() report = %+v", report)
+	}
+	if !errors.Is(report.Issues[2], errBadHeader) || !errors.Is(report.Issues[3], errNoHeader) {
+		t.Errorf("Verify() issues = %v, %v", report.Issues[2], report.Issues[3])
+	}
+
+	if _, err = Verify(fn + ".missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Verify() error = %v, want %v", err, os.ErrNotExist)
+	}
+} // Test_Verify()
+
+func Test_THashTags_Repair(t *testing.T) {
+	saveBinary := UseBinaryStorage
+	defer func() {
+		UseBinaryStorage = saveBinary
+	}()
+	UseBinaryStorage = false
+	fn := filepath.Join(t.TempDir(), "damaged.txt")
+	_ = os.WriteFile(fn, []byte(damagedText), 0600)
+
+	// lenient loading keeps the misplaced ID
+	ht, err := New(fn)
+	if nil != err {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := ht.HashList("#go"); !slices.Equal(got, []int64{1, 2}) {
+		t.Errorf("New().HashList() = %v, want [1 2]", got)
+	}
+
+	ht.HashAdd("#keep", 7)
+	if _, err = ht.LoadStrict(); !errors.Is(err, ErrCorruptFile) {
+		t.Errorf("THashTags.LoadStrict() error = %v, want %v", err, ErrCorruptFile)
+	}
+	if 1 != ht.HashLen("#keep") {
+		t.Error("THashTags.LoadStrict() changed the list")
+	}
+
+	report, err := ht.Repair()
+	if (nil != err) || (5 != len(report.Issues)) || (2 != report.Tags) {
+		t.Errorf("THashTags.Repair() = %+v, %v", report, err)
+	}
+	if got := ht.HashList("#go"); !slices.Equal(got, []int64{1}) {
+		t.Errorf("THashTags.Repair().HashList() = %v, want [1]", got)
+	}
+	if got := ht.MentionList("@bob"); !slices.Equal(got, []int64{3}) {
+		t.Errorf("THashTags.Repair().MentionList() = %v, want [3]", got)
+	}
+	if report, err = Verify(fn); (nil != err) || !report.OK() {
+		t.Errorf("Verify() after repair = %+v, %v", report, err)
+	}
+	if report, err = ht.LoadStrict(); (nil != err) || (2 != report.Tags) {
+		t.Errorf("THashTags.LoadStrict() = %+v, %v", report, err)
+	}
+} // Test_THashTags_Repair()
+
+func Test_THashTags_Repair_binary(t *testing.T) {
+	saveBinary := UseBinaryStorage
+	defer func() {
+		UseBinaryStorage = saveBinary
+	}()
+	UseBinaryStorage = true
+	fn := filepath.Join(t.TempDir(), "damaged.gob")
+
+	ht, _ := New(fn)
+	ht.SetTimestamps(true)
+	ht.IDparse(1, []byte("#go and @bob"))
+	if _, err := ht.Store(); nil != err {
+		t.Fatalf("THashTags.Store() error = %v", err)
+	}
+	data, _ := os.ReadFile(fn)
+	_ = os.WriteFile(fn, data[:len(data)-1], 0600) // damage the extras
+
+	if _, err := Verify(fn); !errors.Is(err, ErrCorruptFile) {
+		t.Errorf("Verify() error = %v, want %v", err, ErrCorruptFile)
+	}
+	report, err := ht.Repair()
+	if (nil != err) || (1 != len(report.Issues)) {
+		t.Errorf("THashTags.Repair() = %+v, %v", report, err)
+	}
+	if (1 != ht.HashLen("#go")) || (1 != ht.MentionLen("@bob")) {
+		t.Errorf("THashTags.Repair() = %v", ht.String())
+	}
+	if _, err = Verify(fn); nil != err {
+		t.Errorf("Verify() after repair error = %v", err)
+	}
+} // Test_THashTags_Repair_binary()
+
+/* EoF */
@@ -0,0 +1,129 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `TIDView` is a read-only view of the IDs associated with a tag,
+	// sorted in ascending order.
+	//
+	// The zero value is an empty view.
+	TIDView[ID cmp.Ordered] struct {
+		ids []ID // the viewed IDs
+	}
+)
+
+// -------------------------------------------------------------------------
+// methods of `TIDView`:
+
+// `At()` returns the ID at position `aIndex` of the view.
+//
+// Like indexing a slice this method panics if `aIndex` is out of range.
+//
+// Parameters:
+//   - `aIndex`: The position of the ID (`0 <= aIndex < Len()`).
+//
+// Returns:
+//   - `ID`: The ID at position `aIndex`.
+func (v TIDView[ID]) At(aIndex int) ID {
+	return v.ids[aIndex]
+} // At()
+
+// `Contains()` reports whether `aID` is part of the view.
+//
+// Parameters:
+//   - `aID`: The ID to lookup.
+//
+// Returns:
+//   - `bool`: `true` if `aID` was found, or `false` otherwise.
+func (v TIDView[ID]) Contains(aID ID) bool {
+	_, ok := slices.BinarySearch(v.ids, aID)
+
+	return ok
+} // Contains()
+
+// `Copy()` returns a copy of the viewed IDs which the caller may
+// modify at will.
+//
+// Returns:
+//   - `[]ID`: The copied IDs (or `nil` if the view is empty).
+func (v TIDView[ID]) Copy() []ID {
+	return slices.Clone(v.ids)
+} // Copy()
+
+// `Len()` returns the number of IDs in the view.
+//
+// Returns:
+//   - `int`: The number of IDs.
+func (v TIDView[ID]) Len() int {
+	return len(v.ids)
+} // Len()
+
+// -------------------------------------------------------------------------
+// methods of `TIndex`:
+
+// `HashView()` returns a read-only view of the IDs associated with
+// `aHash` without copying them.
+//
+// UNSAFE: The view refers to the list's live storage. It reflects
+// (possibly inconsistently) all changes of the tag's IDs made after
+// this call, and using it while another goroutine modifies the list
+// is a data race. Use [TIndex.HashList] or the view's `Copy()` to
+// get data which remain stable.
+//
+// Parameters:
+//   - `aHash`: The hash to lookup.
+//
+// Returns:
+//   - `TIDView[ID]`: The view of the IDs referencing `aHash`.
+func (ht *TIndex[ID]) HashView(aHash string) TIDView[ID] {
+	return ht.idView(MarkHash, aHash)
+} // HashView()
+
+// `idView()` returns a read-only view of the IDs associated with `aTag`.
+//
+// Parameters:
+//   - `aDelim`: The start of words to search (i.e. either '@' or '#').
+//   - `aTag`: The tag to lookup.
+//
+// Returns:
+//   - `TIDView[ID]`: The view of the IDs referencing `aTag`.
+func (ht *TIndex[ID]) idView(aDelim byte, aTag string) TIDView[ID] {
+	if aTag = strings.TrimSpace(aTag); "" == aTag {
+		return TIDView[ID]{}
+	}
+
+	if ht.safe {
+		ht.mtx.RLock()
+		defer ht.mtx.RUnlock()
+	}
+
+	return TIDView[ID]{ids: ht.hm.view(aDelim, aTag)}
+} // idView()
+
+// `MentionView()` returns a read-only view of the IDs associated with
+// `aMention` without copying them.
+//
+// UNSAFE: See [TIndex.HashView] for the limitations of the view.
+//
+// Parameters:
+//   - `aMention`: The mention to lookup.
+//
+// Returns:
+//   - `TIDView[ID]`: The view of the IDs referencing `aMention`.
+func (ht *TIndex[ID]) MentionView(aMention string) TIDView[ID] {
+	return ht.idView(MarkMention, aMention)
+} // MentionView()
+
+/* EoF */
@@ -0,0 +1,70 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"slices"
+	"testing"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+func Test_TIDView(t *testing.T) {
+	v := TIDView[int64]{ids: []int64{2, 4, 6}}
+
+	if got := v.Len(); 3 != got {
+		t.Errorf("TIDView.Len() = %d, want 3", got)
+	}
+	if got := v.At(1); 4 != got {
+		t.Errorf("TIDView.At(1) = %d, want 4", got)
+	}
+	if !v.Contains(6) || v.Contains(5) {
+		t.Error("TIDView.Contains() failed")
+	}
+
+	ids := v.Copy()
+	ids[0] = 9
+	if got := v.At(0); 2 != got {
+		t.Errorf("TIDView.Copy() shares storage: At(0) = %d", got)
+	}
+
+	var empty TIDView[int64]
+	if (0 != empty.Len()) || empty.Contains(0) || (nil != empty.Copy()) {
+		t.Error("TIDView{} isn't empty")
+	}
+} // Test_TIDView()
+
+func Test_THashTags_HashList_copy(t *testing.T) {
+	ht, _ := New("")
+	ht.HashAdd("#go", 1)
+	ht.HashAdd("#go", 2)
+	ht.MentionAdd("@bob", 3)
+
+	ids := ht.HashList("#go")
+	ids[0] = 7
+	_ = append(ids[:1], 8)
+	if got := ht.HashList("#go"); !slices.Equal(got, []int64{1, 2}) {
+		t.Errorf("THashTags.HashList() isn't a copy: %v", got)
+	}
+	mentions := ht.MentionList("@bob")
+	mentions[0] = 7
+	if got := ht.MentionList("@bob"); !slices.Equal(got, []int64{3}) {
+		t.Errorf("THashTags.MentionList() isn't a copy: %v", got)
+	}
+
+	if v := ht.HashView("#GO"); (2 != v.Len()) || !v.Contains(2) {
+		t.Errorf("THashTags.HashView() = %v", v.Copy())
+	}
+	if v := ht.MentionView("@bob"); (1 != v.Len()) || (3 != v.At(0)) {
+		t.Errorf("THashTags.MentionView() = %v", v.Copy())
+	}
+	if v := ht.HashView(" "); 0 != v.Len() {
+		t.Errorf("THashTags.HashView(\" \") = %v", v.Copy())
+	}
+} // Test_THashTags_HashList_copy()
+
+/* EoF */
@@ -0,0 +1,262 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	se "github.com/mwat56/sourceerror"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+type (
+	// `TWatchEvent` describes a reload of a list's file triggered
+	// by [TIndex.Watch].
+	TWatchEvent struct {
+		Filename string    // name of the file read
+		Time     time.Time // time of the reload
+		Err      error     // a possible error (the list remains unchanged)
+	}
+
+	// `TWatchFunc` is called after each reload triggered by
+	// [TIndex.Watch].
+	TWatchFunc func(aEvent TWatchEvent)
+
+	// `tWatchers` holds the subscribers of a list's reloads.
+	tWatchers struct {
+		mtx     sync.Mutex         // safeguard against concurrent accesses
+		subs    map[int]TWatchFunc // the subscribed functions
+		next    int                // key of the next subscription
+		running bool               // flag for a running watcher
+	}
+)
+
+const (
+	// `DefaultWatchInterval` is the polling interval used by
+	// [TIndex.Watch] if no interval is given.
+	DefaultWatchInterval = 2 * time.Second
+)
+
+// -------------------------------------------------------------------------
+// methods of `tWatchers`:
+
+// `notify()` calls all subscribed functions with `aEvent`.
+//
+// Parameters:
+//   - `aEvent`: The event to report.
+func (ws *tWatchers) notify(aEvent TWatchEvent) {
+	ws.mtx.Lock()
+	subs := make([]TWatchFunc, 0, len(ws.subs))
+	for _, fn := range ws.subs {
+		subs = append(subs, fn)
+	}
+	ws.mtx.Unlock()
+
+	for _, fn := range subs {
+		fn(aEvent)
+	}
+} // notify()
+
+// `start()` marks the watcher as running.
+//
+// Returns:
+//   - `bool`: `true` if the watcher was started, or `false` if it's already running.
+func (ws *tWatchers) start() bool {
+	ws.mtx.Lock()
+	defer ws.mtx.Unlock()
+
+	if ws.running {
+		return false
+	}
+	ws.running = true
+
+	return true
+} // start()
+
+// `stop()` marks the watcher as stopped.
+func (ws *tWatchers) stop() {
+	ws.mtx.Lock()
+	ws.running = false
+	ws.mtx.Unlock()
+} // stop()
+
+// `subscribe()` adds `aFunc` to the subscribed functions.
+//
+// Parameters:
+//   - `aFunc`: The function to call after each reload.
+//
+// Returns:
+//   - `func()`: The function cancelling the subscription.
+func (ws *tWatchers) subscribe(aFunc TWatchFunc) func() {
+	ws.mtx.Lock()
+	defer ws.mtx.Unlock()
+
+	if nil == ws.subs {
+		ws.subs = make(map[int]TWatchFunc)
+	}
+	key := ws.next
+	ws.next++
+	ws.subs[key] = aFunc
+
+	return func() {
+		ws.mtx.Lock()
+		delete(ws.subs, key)
+		ws.mtx.Unlock()
+	}
+} // subscribe()
+
+// -------------------------------------------------------------------------
+// methods of `TIndex`:
+
+// `Subscribe()` registers `aFunc` to be called after each reload of
+// the list's file triggered by [TIndex.Watch].
+//
+// The function is called by the watcher's goroutine after the list's
+// data have been replaced, i.e. it may access the list.
+//
+// Parameters:
+//   - `aFunc`: The function to call after each reload.
+//
+// Returns:
+//   - `func()`: The function cancelling the subscription.
+func (ht *TIndex[ID]) Subscribe(aFunc TWatchFunc) func() {
+	if nil != ht.root {
+		return ht.root.Subscribe(aFunc)
+	}
+	if nil == aFunc {
+		return func() {}
+	}
+
+	return ht.ws.subscribe(aFunc)
+} // Subscribe()
+
+// `watch()` reloads the list whenever `aChanges` reports a change of
+// the list's file or – if `aChanges` is `nil` or gets closed – every
+// `aInterval` until `aCtx` is done.
+//
+// A file which can't be loaded is reported only once until it gets
+// changed again.
+//
+// Parameters:
+//   - `aCtx`: The context to watch.
+//   - `aInterval`: The polling interval.
+//   - `aChanges`: Optional notifications of file changes.
+func (ht *TIndex[ID]) watch(aCtx context.Context, aInterval time.Duration, aChanges <-chan struct{}) {
+	defer ht.ws.stop()
+
+	var tick <-chan time.Time
+	ticker := time.NewTicker(aInterval)
+	defer ticker.Stop()
+	if nil == aChanges {
+		tick = ticker.C
+	}
+	var failed tFileStamp // state of the file which failed to load
+
+	for {
+		select {
+		case <-aCtx.Done():
+			return
+
+		case _, ok := <-aChanges:
+			if !ok {
+				// fall back to polling
+				aChanges, tick = nil, ticker.C
+				continue
+			}
+
+		case <-tick:
+		}
+
+		reloaded, err := ht.Reload()
+		if nil != err {
+			stamp, _ := stampName(ht.Filename(), failed)
+			if !failed.missing() && !stamp.differs(failed) {
+				continue // already reported
+			}
+			failed = stamp
+		} else {
+			failed = tFileStamp{}
+		}
+		if reloaded || (nil != err) {
+			ht.ws.notify(TWatchEvent{
+				Filename: ht.Filename(),
+				Time:     time.Now(),
+				Err:      err,
+			})
+		}
+	}
+} // watch()
+
+// `Watch()` starts watching the list's file in a background goroutine,
+// reloading the list (see [TIndex.Reload]) whenever the file is changed
+// or replaced by another process, until `aCtx` is done.
+//
+// On Linux the file's directory is watched by `inotify`, on other
+// platforms (or if `inotify` isn't available) the file is checked
+// every `aInterval`. The list's data are replaced atomically, i.e.
+// all queries answered before a reload use the former data and all
+// queries answered after it use the new data. The functions
+// registered by [TIndex.Subscribe] are called after each reload.
+//
+// Calling this method while the list is already watched does nothing.
+//
+// Parameters:
+//   - `aCtx`: The context stopping the watcher once done.
+//   - `aInterval`: The polling interval (`0` = [DefaultWatchInterval]).
+//
+// Returns:
+//   - `error`: `nil` in case of success, or `ErrEmptyFilename`.
+func (ht *TIndex[ID]) Watch(aCtx context.Context, aInterval time.Duration) error {
+	if nil != ht.root {
+		return ht.root.Watch(aCtx, aInterval)
+	}
+
+	fn := ht.Filename()
+	if "" == fn {
+		return se.New(ErrEmptyFilename, 1)
+	}
+	if 0 >= aInterval {
+		aInterval = DefaultWatchInterval
+	}
+	if !ht.ws.start() {
+		return nil
+	}
+
+	changes, closer, err := watchFile(fn)
+	if nil != err {
+		changes = nil // fall back to polling
+	} else {
+		go func() {
+			<-aCtx.Done()
+			_ = closer()
+		}()
+	}
+	go ht.watch(aCtx, aInterval, changes)
+
+	return nil
+} // Watch()
+
+// `Watching()` reports whether the list's file is watched by
+// [TIndex.Watch].
+//
+// Returns:
+//   - `bool`: `true` if the file is watched, or `false` otherwise.
+func (ht *TIndex[ID]) Watching() bool {
+	if nil != ht.root {
+		return ht.root.Watching()
+	}
+
+	ht.ws.mtx.Lock()
+	defer ht.ws.mtx.Unlock()
+
+	return ht.ws.running
+} // Watching()
+
+/* EoF */
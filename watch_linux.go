@@ -0,0 +1,77 @@
+//go:build linux
+
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+// `watchFile()` watches the directory of `aFilename` by `inotify`
+// reporting each completed write and each replacement of the file.
+//
+// The returned channel is closed once the returned function is called.
+//
+// Parameters:
+//   - `aFilename`: The name of the file to watch.
+//
+// Returns:
+//   - `<-chan struct{}`: The notifications of file changes.
+//   - `func() error`: The function stopping the watcher.
+//   - `error`: A possible error setting up the watcher.
+func watchFile(aFilename string) (<-chan struct{}, func() error, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if nil != err {
+		return nil, nil, err
+	}
+	mask := uint32(syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_TO)
+	if _, err = syscall.InotifyAddWatch(fd, filepath.Dir(aFilename), mask); nil != err {
+		_ = syscall.Close(fd)
+		return nil, nil, err
+	}
+
+	// a non-blocking descriptor gets closed by the runtime's poller
+	file := os.NewFile(uintptr(fd), "inotify")
+	name := []byte(filepath.Base(aFilename))
+	changes := make(chan struct{}, 1)
+
+	go func() {
+		defer close(changes)
+		buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+		for {
+			n, err := file.Read(buf)
+			if nil != err {
+				return
+			}
+			for off := 0; off+syscall.SizeofInotifyEvent <= n; {
+				// the fields `Wd`, `Mask`, `Cookie`, `Len` are followed by `Name`
+				size := int(binary.NativeEndian.Uint32(buf[off+12:]))
+				start := off + syscall.SizeofInotifyEvent
+				off = start + size
+				if (off > n) || !bytes.Equal(bytes.TrimRight(buf[start:off], "\x00"), name) {
+					continue
+				}
+				select {
+				case changes <- struct{}{}:
+				default: // a notification is already pending
+				}
+			}
+		}
+	}()
+
+	return changes, file.Close, nil
+} // watchFile()
+
+/* EoF */
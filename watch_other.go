@@ -0,0 +1,32 @@
+//go:build !linux
+
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"errors"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+// `watchFile()` is not supported on platforms without `inotify`,
+// i.e. the file is polled instead.
+//
+// Parameters:
+//   - `aFilename`: The name of the file to watch.
+//
+// Returns:
+//   - `<-chan struct{}`: Always `nil`.
+//   - `func() error`: Always `nil`.
+//   - `error`: Always `errors.ErrUnsupported`.
+func watchFile(aFilename string) (<-chan struct{}, func() error, error) {
+	return nil, nil, errors.ErrUnsupported
+} // watchFile()
+
+/* EoF */
@@ -0,0 +1,142 @@
+/*
+Copyright © 2025  M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package hashtags
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+// `waitEvent()` returns the next event sent to `aEvents`.
+func waitEvent(t *testing.T, aEvents <-chan TWatchEvent) TWatchEvent {
+	t.Helper()
+	select {
+	case ev := <-aEvents:
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload event")
+	}
+
+	return TWatchEvent{}
+} // waitEvent()
+
+func Test_THashTags_Watch(t *testing.T) {
+	dir := t.TempDir()
+	fn := filepath.Join(dir, "watched.db")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ht, _ := New("")
+	if err := ht.Watch(ctx, 0); !errors.Is(err, ErrEmptyFilename) {
+		t.Errorf("THashTags.Watch() error = %v, want %v", err, ErrEmptyFilename)
+	}
+
+	ht, _ = New(fn)
+	events := make(chan TWatchEvent, 4)
+	unsubscribe := ht.Subscribe(func(aEvent TWatchEvent) {
+		events <- aEvent
+	})
+	defer unsubscribe()
+	if err := ht.Watch(ctx, 10*time.Millisecond); nil != err {
+		t.Fatalf("THashTags.Watch() error = %v", err)
+	}
+	if !ht.Watching() {
+		t.Error("THashTags.Watching() = false, want true")
+	}
+
+	// the file gets written by another list
+	writer, _ := New(fn)
+	writer.HashAdd("#go", 1)
+	_, _ = writer.Store()
+	if ev := waitEvent(t, events); (nil != ev.Err) || (fn != ev.Filename) {
+		t.Errorf("THashTags.Watch() event = %+v", ev)
+	}
+	if 1 != ht.HashLen("#go") {
+		t.Errorf("THashTags.Watch() = %v", ht.String())
+	}
+
+	// the file gets replaced by another one
+	tmp := filepath.Join(dir, "watched.tmp")
+	_ = writer.SetFilename(tmp)
+	writer.MentionAdd("@bob", 2)
+	_, _ = writer.Store()
+	if err := os.Rename(tmp, fn); nil != err {
+		t.Fatalf("os.Rename() error = %v", err)
+	}
+	waitEvent(t, events)
+	if (1 != ht.HashLen("#go")) || (1 != ht.MentionLen("@bob")) {
+		t.Errorf("THashTags.Watch() = %v", ht.String())
+	}
+
+	cancel()
+	for deadline := time.Now().Add(5 * time.Second); ht.Watching(); {
+		if time.Now().After(deadline) {
+			t.Fatal("THashTags.Watch() didn't stop")
+		}
+		time.Sleep(time.Millisecond)
+	}
+} // Test_THashTags_Watch()
+
+func Test_THashTags_watch_polling(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "polled.db")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ht, _ := New(fn)
+	events := make(chan TWatchEvent, 4)
+	ht.Subscribe(func(aEvent TWatchEvent) {
+		events <- aEvent
+	})
+	ht.ws.start()
+	go ht.watch(ctx, 10*time.Millisecond, nil)
+
+	writer, _ := New(fn)
+	writer.HashAdd("#go", 1)
+	_, _ = writer.Store()
+	waitEvent(t, events)
+	if 1 != ht.HashLen("#go") {
+		t.Errorf("THashTags.watch() = %v", ht.String())
+	}
+
+	// a damaged file is reported but keeps the list unchanged
+	_ = os.WriteFile(fn, []byte("garbage"), 0600)
+	if ev := waitEvent(t, events); nil == ev.Err {
+		t.Error("THashTags.watch() reported no error")
+	}
+	if 1 != ht.HashLen("#go") {
+		t.Errorf("THashTags.watch() = %v", ht.String())
+	}
+
+	// the same damage is reported only once
+	select {
+	case ev := <-events:
+		t.Errorf("THashTags.watch() event = %+v", ev)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// but a different one again
+	_ = os.WriteFile(fn, []byte("more garbage"), 0600)
+	if ev := waitEvent(t, events); nil == ev.Err {
+		t.Error("THashTags.watch() reported no error")
+	}
+	writer.HashAdd("#rust", 2)
+	_, _ = writer.Store()
+	if ev := waitEvent(t, events); nil != ev.Err {
+		t.Errorf("THashTags.watch() error = %v", ev.Err)
+	}
+	if 1 != ht.HashLen("#rust") {
+		t.Errorf("THashTags.watch() = %v", ht.String())
+	}
+} // Test_THashTags_watch_polling()
+
+/* EoF */